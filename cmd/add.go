@@ -15,9 +15,12 @@ var (
 	addTitle    string
 	addBody     string
 	addHTML     string
+	addMarkdown string
+	addMDFile   string
 	addNotebook string
 	addTags     []string
 	addAttach   []string
+	addSanitize bool
 )
 
 // wrapENML wraps plain text content in the required Evernote ENML format.
@@ -40,12 +43,27 @@ func wrapHTMLInENML(body string) string {
 var addCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Add a new note",
+	Long: `Add a new note. The body can be given as plain text (--body), raw HTML
+(--html) or Markdown (--markdown or --md-file). Local images referenced from
+Markdown are uploaded as attachments and shown inline.
+
+Examples:
+  evernote-cli add --title "Groceries" --body "Milk and eggs"
+  evernote-cli add --title "Standup" --body "Notes" --notebook "Work Journal"
+  evernote-cli add --title "Plan" --markdown "- [ ] Draft **first** version"
+  evernote-cli add --title "Runbook" --md-file runbook.md --attach config.yml`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if addTitle == "" {
 			return fmt.Errorf("--title is required")
 		}
-		if addBody != "" && addHTML != "" {
-			return fmt.Errorf("--body and --html cannot be used together")
+		inputs := 0
+		for _, v := range []string{addBody, addHTML, addMarkdown, addMDFile} {
+			if v != "" {
+				inputs++
+			}
+		}
+		if inputs > 1 {
+			return fmt.Errorf("only one of --body, --html, --markdown or --md-file can be used")
 		}
 
 		ns, token, err := getNoteStoreFunc()
@@ -67,7 +85,18 @@ var addCmd = &cobra.Command{
 
 		// Build ENML content with optional media tags for attachments
 		var content string
-		if addHTML != "" {
+		if addMarkdown != "" || addMDFile != "" {
+			src, baseDir, err := readMarkdownInput(cmd, addMarkdown, addMDFile)
+			if err != nil {
+				return err
+			}
+			var images []*edam.Resource
+			content, images, err = markdownToENML(src, baseDir)
+			if err != nil {
+				return err
+			}
+			resources = append(resources, images...)
+		} else if addHTML != "" {
 			content = wrapHTMLInENML(addHTML)
 		} else {
 			content = wrapENML(addBody)
@@ -76,6 +105,10 @@ var addCmd = &cobra.Command{
 			mediaBlock := strings.Join(mediaTags, "")
 			content = strings.Replace(content, "</en-note>", mediaBlock+"</en-note>", 1)
 		}
+		content, err = prepareENML(content, resources, addSanitize)
+		if err != nil {
+			return err
+		}
 
 		note := &edam.Note{
 			Title:     &addTitle,
@@ -84,7 +117,12 @@ var addCmd = &cobra.Command{
 		}
 
 		if addNotebook != "" {
-			note.NotebookGuid = &addNotebook
+			nb, err := newNameResolver(ns, token).notebook(addNotebook)
+			if err != nil {
+				return err
+			}
+			guid := string(nb.GetGUID())
+			note.NotebookGuid = &guid
 		}
 		if len(addTags) > 0 {
 			note.TagNames = addTags
@@ -111,8 +149,11 @@ func init() {
 	addCmd.Flags().StringVar(&addTitle, "title", "", "title of the note (required)")
 	addCmd.Flags().StringVar(&addBody, "body", "", "body of the note")
 	addCmd.Flags().StringVar(&addHTML, "html", "", "body of the note as raw HTML (not escaped)")
-	addCmd.Flags().StringVar(&addNotebook, "notebook", "", "notebook GUID")
+	addCmd.Flags().StringVar(&addMarkdown, "markdown", "", "body of the note as Markdown")
+	addCmd.Flags().StringVar(&addMDFile, "md-file", "", "read the note body from a Markdown file (- for stdin)")
+	addCmd.Flags().StringVar(&addNotebook, "notebook", "", "notebook name or GUID")
 	addCmd.Flags().StringSliceVar(&addTags, "tags", nil, "comma separated list of tag names")
 	addCmd.Flags().StringSliceVar(&addAttach, "attach", nil, "file paths to attach to the note")
+	addCmd.Flags().BoolVar(&addSanitize, "sanitize", false, "strip markup that is not valid ENML instead of failing")
 	rootCmd.AddCommand(addCmd)
 }
@@ -3,6 +3,8 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/dreampuf/evernote-sdk-golang/edam"
@@ -63,6 +65,31 @@ func TestAddCommand(t *testing.T) {
 		assert.Contains(t, output, "GUID: new-note-123")
 	})
 
+	t.Run("notebook by name", func(t *testing.T) {
+		nbName, nbGUID := "Work Journal", edam.GUID("nb-42")
+		mock := &mockNoteStore{notebooks: []*edam.Notebook{{Name: &nbName, GUID: &nbGUID}}}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+
+		addTitle = "Standup"
+		addBody = "notes"
+		addNotebook = "work journal"
+		addTags = nil
+		defer func() { addNotebook = "" }()
+
+		var buf bytes.Buffer
+		addCmd.SetOut(&buf)
+		jsonFlag = false
+		require.NoError(t, addCmd.RunE(addCmd, []string{}))
+		require.NotNil(t, mock.savedNote)
+		assert.Equal(t, "nb-42", mock.savedNote.GetNotebookGuid())
+
+		addNotebook = "Missing"
+		err := addCmd.RunE(addCmd, []string{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `notebook "Missing" not found`)
+	})
+
 	t.Run("note creation with JSON output", func(t *testing.T) {
 		createdTitle := "JSON Note"
 		createdGuid := edam.GUID("json-note-123")
@@ -92,6 +119,74 @@ func TestAddCommand(t *testing.T) {
 		assert.Contains(t, output, "json-note-123")
 	})
 
+	t.Run("note from markdown file with local image", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "diagram.png"), []byte("png"), 0644))
+		mdPath := filepath.Join(dir, "note.md")
+		require.NoError(t, os.WriteFile(mdPath, []byte("# Runbook\n\n- [ ] step\n\n![diagram](diagram.png)"), 0644))
+
+		mock := &mockNoteStore{}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+
+		addTitle = "Runbook"
+		addBody = ""
+		addMDFile = mdPath
+		addNotebook = ""
+		addTags = nil
+		defer func() { addMDFile = "" }()
+
+		var buf bytes.Buffer
+		addCmd.SetOut(&buf)
+		jsonFlag = false
+		err := addCmd.RunE(addCmd, []string{})
+		require.NoError(t, err)
+
+		require.NotNil(t, mock.savedNote)
+		assert.Contains(t, mock.savedNote.GetContent(), "<h1>Runbook</h1>")
+		assert.Contains(t, mock.savedNote.GetContent(), `<en-todo checked="false"/>`)
+		assert.Contains(t, mock.savedNote.GetContent(), `<en-media type="image/png"`)
+		assert.Len(t, mock.savedNote.GetResources(), 1)
+	})
+
+	t.Run("markdown and body conflict", func(t *testing.T) {
+		addTitle = "Test"
+		addBody = "body"
+		addMarkdown = "*md*"
+		defer func() { addMarkdown = "" }()
+
+		err := addCmd.RunE(addCmd, []string{})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "only one of --body, --html, --markdown or --md-file")
+	})
+
+	t.Run("invalid html is rejected unless sanitized", func(t *testing.T) {
+		mock := &mockNoteStore{}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+
+		addTitle = "HTML"
+		addBody = ""
+		addHTML = `<div id="x">hi</div><script>bad()</script>`
+		defer func() {
+			addHTML = ""
+			addSanitize = false
+		}()
+
+		err := addCmd.RunE(addCmd, []string{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not valid ENML")
+		assert.Nil(t, mock.savedNote)
+
+		addSanitize = true
+		var buf bytes.Buffer
+		addCmd.SetOut(&buf)
+		jsonFlag = false
+		require.NoError(t, addCmd.RunE(addCmd, []string{}))
+		require.NotNil(t, mock.savedNote)
+		assert.Equal(t, wrapHTMLInENML("<div>hi</div>"), mock.savedNote.GetContent())
+	})
+
 	t.Run("title required", func(t *testing.T) {
 		addTitle = ""
 		addBody = "body"
@@ -17,6 +17,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var attachSanitize bool
+
 // buildResource reads a file from disk and returns an Evernote Resource with its MD5 hash.
 func buildResource(filePath string) (*edam.Resource, []byte, error) {
 	data, err := os.ReadFile(filePath)
@@ -24,9 +26,6 @@ func buildResource(filePath string) (*edam.Resource, []byte, error) {
 		return nil, nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
 
-	hash := md5.Sum(data)
-	hashSlice := hash[:]
-	size := int32(len(data))
 	fileName := filepath.Base(filePath)
 	isAttachment := true
 
@@ -37,11 +36,7 @@ func buildResource(filePath string) (*edam.Resource, []byte, error) {
 	}
 
 	resource := &edam.Resource{
-		Data: &edam.Data{
-			Body:     data,
-			Size:     &size,
-			BodyHash: hashSlice,
-		},
+		Data: newResourceData(data),
 		Mime: &mimeType,
 		Attributes: &edam.ResourceAttributes{
 			FileName:   &fileName,
@@ -49,7 +44,19 @@ func buildResource(filePath string) (*edam.Resource, []byte, error) {
 		},
 	}
 
-	return resource, hashSlice, nil
+	return resource, resource.Data.BodyHash, nil
+}
+
+// newResourceData wraps a resource body with its size and MD5 hash, which
+// <en-media> tags use to refer to the resource.
+func newResourceData(body []byte) *edam.Data {
+	hash := md5.Sum(body)
+	size := int32(len(body))
+	return &edam.Data{
+		Body:     body,
+		Size:     &size,
+		BodyHash: hash[:],
+	}
 }
 
 // buildMediaTag returns an ENML <en-media> tag for a resource with the given hash and MIME type.
@@ -102,6 +109,10 @@ Examples:
 		content := existing.GetContent()
 		mediaBlock := strings.Join(mediaTags, "")
 		content = strings.Replace(content, "</en-note>", mediaBlock+"</en-note>", 1)
+		content, err = prepareENML(content, resources, attachSanitize)
+		if err != nil {
+			return err
+		}
 
 		// Build update note
 		title := existing.GetTitle()
@@ -130,5 +141,6 @@ Examples:
 }
 
 func init() {
+	attachCmd.Flags().BoolVar(&attachSanitize, "sanitize", false, "strip markup that is not valid ENML instead of failing")
 	rootCmd.AddCommand(attachCmd)
 }
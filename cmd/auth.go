@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -11,73 +15,164 @@ import (
 	"strings"
 	"time"
 
-	"github.com/dreampuf/evernote-sdk-golang/client"
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/mrjones/oauth"
 	"github.com/spf13/cobra"
 )
 
-// runAuthFlow performs the OAuth 1.0a flow using the Evernote SDK and returns
-// the auth token and NoteStore URL.
-func runAuthFlow(clientID, clientSecret string) (string, string, error) {
-	c := client.NewClient(clientID, clientSecret, client.PRODUCTION)
+// environmentFlag is the --environment flag of auth and init.
+var environmentFlag string
+
+var (
+	authNoBrowser    bool
+	authCallbackPort int
+	authImportToken  string
+)
+
+// authTimeout is how long to wait for the user to finish signing in.
+var authTimeout = 5 * time.Minute
+
+// openBrowserFunc opens the authorization page. Can be overridden in tests.
+var openBrowserFunc = openBrowser
+
+// runAuthFlow performs the OAuth 1.0a flow against the environment and
+// returns the auth token and NoteStore URL. Evernote redirects the browser
+// to a listener on 127.0.0.1 with the verifier, or with --no-browser the
+// user pastes the redirect URL or the verifier into in.
+func runAuthFlow(cmd *cobra.Command, in *bufio.Reader, clientID, clientSecret string, env environment) (string, string, error) {
+	if authCallbackPort < 0 || authCallbackPort > 65535 {
+		return "", "", fmt.Errorf("invalid --callback-port %d", authCallbackPort)
+	}
+	c := env.newClient(clientID, clientSecret)
+	out := cmd.OutOrStdout()
+
+	var verifier string
+	if authNoBrowser {
+		// Nothing listens on the callback, so the browser shows an error page
+		// whose address holds the verifier.
+		callbackURL := "http://127.0.0.1/callback"
+		if authCallbackPort != 0 {
+			callbackURL = fmt.Sprintf("http://127.0.0.1:%d/callback", authCallbackPort)
+		}
+		requestToken, authURL, err := c.GetRequestToken(callbackURL)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get request token: %w", err)
+		}
+		fmt.Fprintf(out, "Open this URL in a browser and sign in:\n\n  %s\n\n", authURL)
+		fmt.Fprintln(out, "The browser is then sent to a page on 127.0.0.1 that will not load.")
+		fmt.Fprint(out, "Paste the address of that page, or its oauth_verifier value: ")
+		line, err := in.ReadString('\n')
+		if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+			return "", "", fmt.Errorf("failed to read verifier: %w", err)
+		}
+		if verifier, err = parseVerifier(line, requestToken.Token); err != nil {
+			return "", "", err
+		}
+		return exchangeVerifier(c, requestToken, verifier)
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", authCallbackPort))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to listen for the OAuth callback (try --callback-port or --no-browser): %w", err)
+	}
+	callbackURL := fmt.Sprintf("http://127.0.0.1:%d/callback", listener.Addr().(*net.TCPAddr).Port)
 
 	// Get request token and authorization URL
-	requestToken, authURL, err := c.GetRequestToken("http://localhost:8080/callback")
+	requestToken, authURL, err := c.GetRequestToken(callbackURL)
 	if err != nil {
+		listener.Close()
 		return "", "", fmt.Errorf("failed to get request token: %w", err)
 	}
 
 	// Set up local server for callback using a custom mux to avoid conflicts
 	mux := http.NewServeMux()
 	srv := &http.Server{
-		Addr:         ":8080",
 		Handler:      mux,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
-	verifierCh := make(chan string, 1)
+	type result struct {
+		verifier string
+		err      error
+	}
+	resultCh := make(chan result, 1)
 
 	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
-		verifier := r.URL.Query().Get("oauth_verifier")
-		if verifier == "" {
-			fmt.Fprintf(w, "Authentication failed. Missing verifier.")
-			return
+		verifier, err := parseVerifier("?"+r.URL.RawQuery, requestToken.Token)
+		if err != nil {
+			fmt.Fprintf(w, "Authentication failed: %v", err)
+		} else {
+			fmt.Fprintf(w, "Authentication complete. You can close this window.")
+		}
+		select {
+		case resultCh <- result{verifier, err}:
+		default:
 		}
-		fmt.Fprintf(w, "Authentication complete. You can close this window.")
-		verifierCh <- verifier
 	})
 
 	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			fmt.Printf("Server error: %v\n", err)
+		if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Server error: %v\n", err)
 		}
 	}()
+	defer srv.Shutdown(context.Background())
 
 	// Open browser to authorization URL
-	openBrowser(authURL)
-	fmt.Printf("If the browser did not open, visit: %s\n", authURL)
+	openBrowserFunc(authURL)
+	fmt.Fprintf(out, "If the browser did not open, visit: %s\n", authURL)
 
 	// Wait for callback with timeout
-	var verifier string
 	select {
-	case verifier = <-verifierCh:
-	case <-time.After(5 * time.Minute):
-		srv.Shutdown(context.Background())
+	case res := <-resultCh:
+		if res.err != nil {
+			return "", "", res.err
+		}
+		verifier = res.verifier
+	case <-time.After(authTimeout):
 		return "", "", fmt.Errorf("authentication timeout")
 	}
+	return exchangeVerifier(c, requestToken, verifier)
+}
+
+// parseVerifier reads the OAuth verifier from a redirect URL, its query
+// string, or the bare verifier, and checks that the redirect belongs to
+// this sign-in.
+func parseVerifier(input, requestToken string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", fmt.Errorf("no verifier given")
+	}
+	if !strings.Contains(input, "oauth_") {
+		return input, nil
+	}
 
-	srv.Shutdown(context.Background())
+	query := input
+	if i := strings.Index(query, "?"); i >= 0 {
+		query = query[i+1:]
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", fmt.Errorf("could not read the redirect URL: %w", err)
+	}
+	if token := values.Get("oauth_token"); token != "" && token != requestToken {
+		return "", fmt.Errorf("the redirect is for a different sign-in, run the command again")
+	}
+	verifier := values.Get("oauth_verifier")
+	if verifier == "" {
+		return "", fmt.Errorf("access was not granted")
+	}
+	return verifier, nil
+}
 
-	// Exchange verifier for access token
+// exchangeVerifier trades the verifier for the access token and returns it
+// with the NoteStore URL that comes with it.
+func exchangeVerifier(c evernoteClient, requestToken *oauth.RequestToken, verifier string) (string, string, error) {
 	accessToken, err := c.GetAuthorizedToken(requestToken, verifier)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get access token: %w", err)
 	}
-
-	// Extract the NoteStore URL from the access token response
-	noteStoreURL := accessToken.AdditionalData["edam_noteStoreUrl"]
-
-	return accessToken.Token, noteStoreURL, nil
+	return accessToken.Token, accessToken.AdditionalData["edam_noteStoreUrl"], nil
 }
 
 // openBrowser opens the given URL in the user's default browser.
@@ -110,14 +205,116 @@ func openBrowser(urlStr string) {
 	}
 }
 
+// importToken saves a developer or auth token given with --token in the
+// active profile instead of signing in. Its NoteStore URL comes from
+// --notestore-url or EVERNOTE_NOTESTORE_URL, or is looked up, which also
+// checks the token.
+func importToken(cmd *cobra.Command, cfg *Config, env environment) error {
+	token := authImportToken
+	if token == "-" {
+		line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+			return fmt.Errorf("failed to read token: %w", err)
+		}
+		token = strings.TrimSpace(line)
+	}
+	if token == "" {
+		return fmt.Errorf("no token given")
+	}
+
+	noteStoreURL := firstSet(noteStoreURLFlag, os.Getenv("EVERNOTE_NOTESTORE_URL"))
+	if noteStoreURL == "" {
+		us, err := env.newClient("", "").GetUserStore()
+		if err == nil {
+			var urls *edam.UserUrls
+			if urls, err = us.GetUserUrls(context.Background(), token); err == nil {
+				noteStoreURL = urls.GetNoteStoreUrl()
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check token: %w", formatAPIError(err))
+		}
+	}
+
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	cfg.AuthToken = token
+	cfg.NoteStoreURL = noteStoreURL
+	cfg.Environment = env.name
+	cfg.SecretStore = firstSet(secretStoreFlag, cfg.SecretStore)
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Token saved to profile %s.\n", currentProfile())
+	return nil
+}
+
 // authCmd authenticates with Evernote using OAuth 1.0a.
 var authCmd = &cobra.Command{
 	Use:   "auth",
 	Short: "Authenticate with Evernote",
+	Long: `Sign in to Evernote in the browser with OAuth and save the token in the
+active profile.
+
+The browser is sent back to a listener on 127.0.0.1, on the port given with
+--callback-port or a free one. Over SSH or in a container, use --no-browser:
+the sign-in URL is printed, and once you have signed in you paste the address
+the browser was sent to (or just its oauth_verifier value).
+
+--token saves a token you already have, such as a developer token, without
+signing in; pass - to read it from stdin. Its NoteStore URL is looked up
+unless given with --notestore-url or EVERNOTE_NOTESTORE_URL.
+
+Commands can also skip the saved profile: EVERNOTE_AUTH_TOKEN, with
+EVERNOTE_NOTESTORE_URL and EVERNOTE_ENVIRONMENT when needed, is used instead
+of the profile's token, and --auth-token and --notestore-url override both.
+
+--environment chooses the Evernote service: production (the default),
+sandbox, yinxiang, or the URL of another host. The choice is saved in the
+profile and used by every later command.
+
+--secret-store chooses where the token and client secret are kept:
+plaintext in the config file (the default), keyring for the desktop keyring
+through secret-tool, or file for a file encrypted with a passphrase, which
+is read from EVERNOTE_SECRET_PASSPHRASE or asked for.
+
+Examples:
+  evernote-cli auth
+  evernote-cli auth --no-browser
+  evernote-cli auth --callback-port 8080
+  evernote-cli auth --profile test --environment sandbox
+  evernote-cli auth --environment https://evernote.example.com
+  evernote-cli auth --secret-store keyring
+  evernote-cli auth --token - < token.txt
+  EVERNOTE_AUTH_TOKEN=S=s1:U=... evernote-cli notebooks`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// A profile whose secrets cannot be read must not be saved over
+		// with blank credentials, or moved out of its secret store.
+		cfg, err := loadConfig()
+		if isNoProfile(err) {
+			cfg, err = nil, nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not read config: %w", err)
+		}
+		envName := environmentFlag
+		if envName == "" && cfg != nil {
+			envName = cfg.Environment
+		}
+		env, err := parseEnvironment(envName)
+		if err != nil {
+			return err
+		}
+		if err := checkSecretStore(secretStoreFlag); err != nil {
+			return err
+		}
+		if authImportToken != "" {
+			return importToken(cmd, cfg, env)
+		}
+
 		clientID := os.Getenv("EVERNOTE_CLIENT_ID")
 		clientSecret := os.Getenv("EVERNOTE_CLIENT_SECRET")
-		cfg, _ := loadConfig()
 		if clientID == "" && cfg != nil {
 			clientID = cfg.ClientID
 		}
@@ -128,7 +325,7 @@ var authCmd = &cobra.Command{
 			return fmt.Errorf("client ID and secret must be provided (run 'evernote-cli init')")
 		}
 
-		token, noteStoreURL, err := runAuthFlow(clientID, clientSecret)
+		token, noteStoreURL, err := runAuthFlow(cmd, bufio.NewReader(cmd.InOrStdin()), clientID, clientSecret, env)
 		if err != nil {
 			return err
 		}
@@ -140,15 +337,22 @@ var authCmd = &cobra.Command{
 		cfg.ClientSecret = clientSecret
 		cfg.AuthToken = token
 		cfg.NoteStoreURL = noteStoreURL
+		cfg.Environment = env.name
+		cfg.SecretStore = firstSet(secretStoreFlag, cfg.SecretStore)
 
 		if err := saveConfig(cfg); err != nil {
 			return err
 		}
-		fmt.Println("Authentication successful. Token saved.")
+		fmt.Fprintf(cmd.OutOrStdout(), "Authentication successful. Token saved to profile %s.\n", currentProfile())
 		return nil
 	},
 }
 
 func init() {
+	authCmd.Flags().StringVar(&environmentFlag, "environment", "", "Evernote service: production, sandbox, yinxiang or a host URL (default from the profile, else production)")
+	authCmd.Flags().StringVar(&secretStoreFlag, "secret-store", "", "where to keep the token: plaintext, keyring or file (default from the profile, else plaintext)")
+	authCmd.Flags().StringVar(&authImportToken, "token", "", "save this token (or - to read it from stdin) instead of signing in")
+	authCmd.Flags().BoolVar(&authNoBrowser, "no-browser", false, "print the sign-in URL and read the redirect URL or verifier from stdin")
+	authCmd.Flags().IntVar(&authCallbackPort, "callback-port", 0, "port on 127.0.0.1 for the OAuth callback (0 picks a free port)")
 	rootCmd.AddCommand(authCmd)
 }
@@ -1,10 +1,22 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAuthCmdConfiguration(t *testing.T) {
@@ -65,15 +77,230 @@ func TestOpenBrowser(t *testing.T) {
 }
 
 func TestHTTPServerConfiguration(t *testing.T) {
-	t.Run("server address", func(t *testing.T) {
-		expectedAddr := ":8080"
-		assert.Equal(t, expectedAddr, ":8080")
+	t.Run("callback port", func(t *testing.T) {
+		flag := authCmd.Flags().Lookup("callback-port")
+		require.NotNil(t, flag)
+		assert.Equal(t, "0", flag.DefValue, "a free port is picked by default")
 	})
 
-	t.Run("callback URL format", func(t *testing.T) {
-		callbackURL := "http://localhost:8080/callback"
-		assert.True(t, strings.HasPrefix(callbackURL, "http://"))
-		assert.True(t, strings.Contains(callbackURL, "localhost:8080"))
-		assert.True(t, strings.HasSuffix(callbackURL, "/callback"))
+	t.Run("no browser", func(t *testing.T) {
+		flag := authCmd.Flags().Lookup("no-browser")
+		require.NotNil(t, flag)
+		assert.Equal(t, "false", flag.DefValue)
+		assert.NotNil(t, initCmd.Flags().Lookup("no-browser"))
 	})
 }
+
+func TestParseVerifier(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   string
+	}{
+		{"abc123\n", "abc123", ""},
+		{"http://127.0.0.1/callback?oauth_token=rt&oauth_verifier=v1&sandbox_lnb=false", "v1", ""},
+		{"?oauth_token=rt&oauth_verifier=v2", "v2", ""},
+		{"http://127.0.0.1/callback?oauth_token=other&oauth_verifier=v1", "", "different sign-in"},
+		{"http://127.0.0.1/callback?oauth_token=rt", "", "access was not granted"},
+		{"  ", "", "no verifier given"},
+	}
+	for _, tt := range tests {
+		got, err := parseVerifier(tt.input, "rt")
+		if tt.err != "" {
+			require.Error(t, err, tt.input)
+			assert.Contains(t, err.Error(), tt.err)
+			continue
+		}
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+// fakeOAuthHost serves the OAuth endpoints of an Evernote host. It sends
+// the callback URL given with the request token on callbacks.
+func fakeOAuthHost(t *testing.T, callbacks chan<- string) environment {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if strings.Contains(header, "oauth_verifier") {
+			if !strings.Contains(header, `oauth_verifier="v1"`) {
+				http.Error(w, "bad verifier", http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, "oauth_token=access-token&oauth_token_secret=&edam_noteStoreUrl=https%3A%2F%2Fexample.com%2Fshard%2Fs1%2Fnotestore")
+			return
+		}
+		for _, part := range strings.Split(strings.TrimPrefix(header, "OAuth "), ",") {
+			if k, v, ok := strings.Cut(strings.TrimSpace(part), "="); ok && k == "oauth_callback" {
+				callback, _ := url.QueryUnescape(strings.Trim(v, `"`))
+				callbacks <- callback
+			}
+		}
+		fmt.Fprint(w, "oauth_token=rt&oauth_token_secret=s&oauth_callback_confirmed=true")
+	}))
+	t.Cleanup(srv.Close)
+	env, err := parseEnvironment(srv.URL)
+	require.NoError(t, err)
+	return env
+}
+
+// resetAuthFlags restores the auth flags after a test.
+func resetAuthFlags() {
+	authNoBrowser = false
+	authCallbackPort = 0
+	authTimeout = 5 * time.Minute
+	openBrowserFunc = openBrowser
+}
+
+func TestRunAuthFlowNoBrowser(t *testing.T) {
+	defer resetAuthFlags()
+	callbacks := make(chan string, 1)
+	env := fakeOAuthHost(t, callbacks)
+	authNoBrowser = true
+	openBrowserFunc = func(string) { t.Error("the browser should not be opened") }
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	in := bufio.NewReader(strings.NewReader("http://127.0.0.1/callback?oauth_token=rt&oauth_verifier=v1\n"))
+	token, noteStoreURL, err := runAuthFlow(cmd, in, "id", "secret", env)
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", token)
+	assert.Equal(t, "https://example.com/shard/s1/notestore", noteStoreURL)
+	assert.Equal(t, "http://127.0.0.1/callback", <-callbacks)
+	assert.Contains(t, out.String(), "/OAuth.action?oauth_token=rt")
+
+	t.Run("bare verifier without a newline", func(t *testing.T) {
+		token, _, err := runAuthFlow(cmd, bufio.NewReader(strings.NewReader("v1")), "id", "secret", env)
+		require.NoError(t, err)
+		assert.Equal(t, "access-token", token)
+		<-callbacks
+	})
+
+	t.Run("nothing pasted", func(t *testing.T) {
+		_, _, err := runAuthFlow(cmd, bufio.NewReader(strings.NewReader("")), "id", "secret", env)
+		assert.Error(t, err)
+		<-callbacks
+	})
+}
+
+func TestRunAuthFlowCallback(t *testing.T) {
+	defer resetAuthFlags()
+	callbacks := make(chan string, 1)
+	env := fakeOAuthHost(t, callbacks)
+
+	var page string
+	openBrowserFunc = func(string) {
+		// Stand in for the browser: follow Evernote's redirect to the callback.
+		resp, err := http.Get(<-callbacks + "?oauth_token=rt&oauth_verifier=v1")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		page = string(body)
+	}
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	token, _, err := runAuthFlow(cmd, bufio.NewReader(strings.NewReader("")), "id", "secret", env)
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", token)
+	assert.Contains(t, page, "Authentication complete")
+
+	t.Run("listens on 127.0.0.1 on the chosen port", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		authCallbackPort = l.Addr().(*net.TCPAddr).Port
+		l.Close()
+
+		var callback string
+		openBrowserFunc = func(string) {
+			callback = <-callbacks
+			resp, err := http.Get(callback + "?oauth_token=rt")
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+		_, _, err = runAuthFlow(cmd, nil, "id", "secret", env)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "access was not granted")
+		assert.Equal(t, fmt.Sprintf("http://127.0.0.1:%d/callback", authCallbackPort), callback)
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		authCallbackPort = 0
+		authTimeout = 10 * time.Millisecond
+		openBrowserFunc = func(string) { <-callbacks }
+		_, _, err := runAuthFlow(cmd, nil, "id", "secret", env)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "authentication timeout")
+	})
+
+	t.Run("invalid port", func(t *testing.T) {
+		authCallbackPort = 70000
+		_, _, err := runAuthFlow(cmd, nil, "id", "secret", env)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid --callback-port")
+	})
+}
+
+func TestAuthToken(t *testing.T) {
+	setConfigPath(t)
+	defer func() { authImportToken, environmentFlag = "", "" }()
+
+	var out bytes.Buffer
+	authCmd.SetOut(&out)
+	authImportToken, noteStoreURLFlag = "S=s1:U=1:dev", "https://sandbox.evernote.com/shard/s1/notestore"
+	environmentFlag = "sandbox"
+	require.NoError(t, authCmd.RunE(authCmd, nil))
+	assert.Equal(t, "Token saved to profile default.\n", out.String())
+
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, &Config{AuthToken: "S=s1:U=1:dev", NoteStoreURL: "https://sandbox.evernote.com/shard/s1/notestore", Environment: "sandbox"}, cfg)
+
+	t.Run("from stdin", func(t *testing.T) {
+		authImportToken, environmentFlag = "-", ""
+		authCmd.SetIn(strings.NewReader("S=s1:U=2:dev\n"))
+		defer authCmd.SetIn(nil)
+		require.NoError(t, authCmd.RunE(authCmd, nil))
+		cfg, err := loadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, "S=s1:U=2:dev", cfg.AuthToken)
+		assert.Equal(t, "sandbox", cfg.Environment, "the profile's environment is kept")
+	})
+
+	t.Run("checks the token when the NoteStore URL is not given", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+		authImportToken, noteStoreURLFlag, environmentFlag = "bad", "", srv.URL
+		err := authCmd.RunE(authCmd, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to check token")
+	})
+}
+
+func TestAuthTokenUnreadableSecrets(t *testing.T) {
+	setConfigPath(t)
+	fakeSecretTool(t)
+	defer func() { authImportToken = "" }()
+	require.NoError(t, saveConfig(&Config{ClientID: "id", ClientSecret: "secret", AuthToken: "token", SecretStore: secretStoreKeyring}))
+
+	original := secretToolCommand
+	secretToolCommand = filepath.Join(t.TempDir(), "missing")
+	defer func() { secretToolCommand = original }()
+
+	authImportToken, noteStoreURLFlag = "S=s1:U=2:dev", "https://www.evernote.com/shard/s1/notestore"
+	err := authCmd.RunE(authCmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not read config")
+	assert.Equal(t, &Config{ClientID: "id", SecretStore: secretStoreKeyring}, rawProfile(t, "default"), "the profile is left as it was")
+
+	secretToolCommand = original
+	require.NoError(t, saveConfig(&Config{ClientID: "id", AuthToken: "new"}))
+	assert.Equal(t, secretStoreKeyring, rawProfile(t, "default").SecretStore, "saving without a store keeps the profile's")
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "new", cfg.AuthToken)
+}
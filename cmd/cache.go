@@ -0,0 +1,489 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+)
+
+// offlineFlag serves reads from the local cache without contacting Evernote.
+var offlineFlag bool
+
+// noCacheFlag keeps what is read from Evernote out of the local cache.
+var noCacheFlag bool
+
+// cacheWarnings receives notices about the local cache. Can be overridden
+// in tests.
+var cacheWarnings io.Writer = os.Stderr
+
+// offlineError reports an operation that needs a connection to Evernote.
+func offlineError(what string) error {
+	return fmt.Errorf("%s is not available offline", what)
+}
+
+// isNetworkError reports whether err means Evernote could not be reached, as
+// opposed to Evernote rejecting the request.
+func isNetworkError(err error) bool {
+	var transport thrift.TTransportException
+	if errors.As(err, &transport) && transport.Err() != nil {
+		err = transport.Err()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// cachingNoteStore is a read-through cache in front of a NoteStore. Notes,
+// notebooks, tags, saved searches and resource metadata read from Evernote
+// are kept in the local store, and are served from it when offline is set or
+// once Evernote turns out to be unreachable. Attachment bodies are only
+// stored by 'sync --resources', so that reading large notes does not copy
+// them to disk. Writes go to Evernote and are refused while offline.
+type cachingNoteStore struct {
+	remote  noteStoreClient
+	store   *localStore
+	offline bool
+	// readOnly stops caching after the store could not be written.
+	readOnly bool
+	// warn receives a notice when the cache is used because Evernote is
+	// unreachable, or cannot be written.
+	warn io.Writer
+	// index is the search index, loaded on the first offline search.
+	index *searchIndex
+}
+
+// newCachingNoteStore returns a cache over remote, which may be nil when offline.
+func newCachingNoteStore(remote noteStoreClient, store *localStore, offline bool) *cachingNoteStore {
+	return &cachingNoteStore{remote: remote, store: store, offline: offline, warn: cacheWarnings}
+}
+
+// fallBack reports whether a failed request should be answered from the
+// cache, switching to offline mode if Evernote could not be reached.
+func (c *cachingNoteStore) fallBack(err error) bool {
+	if !isNetworkError(err) {
+		return false
+	}
+	if !c.offline && c.warn != nil {
+		fmt.Fprintln(c.warn, "Evernote is unreachable, using the offline cache.")
+	}
+	c.offline = true
+	return true
+}
+
+// cache stores an object. Caching is best effort: once the store cannot be
+// written, a warning is printed and nothing more is cached.
+func (c *cachingNoteStore) cache(kind string, guid edam.GUID, v interface{}) {
+	if c.readOnly {
+		return
+	}
+	if err := c.store.put(kind, guid, v); err != nil {
+		c.readOnly = true
+		if c.warn != nil {
+			fmt.Fprintf(c.warn, "Warning: could not write the local cache, continuing without it: %v\n", err)
+		}
+	}
+}
+
+// forget drops an object that changed in Evernote; the next sync fetches it again.
+func (c *cachingNoteStore) forget(kind string, guid edam.GUID) {
+	c.store.remove(kind, guid)
+}
+
+// replaceAll caches a complete list of objects and forgets any stored
+// object of the same kind that is no longer in it.
+func (c *cachingNoteStore) replaceAll(kind string, guids []edam.GUID, put func(i int)) {
+	keep := map[edam.GUID]bool{}
+	for i, guid := range guids {
+		keep[guid] = true
+		put(i)
+	}
+	stored, _ := c.store.list(kind)
+	for _, guid := range stored {
+		if !keep[guid] {
+			c.forget(kind, guid)
+		}
+	}
+}
+
+// ListNotebooks returns the notebooks from Evernote, or from the cache when offline.
+func (c *cachingNoteStore) ListNotebooks(ctx context.Context, authenticationToken string) ([]*edam.Notebook, error) {
+	if !c.offline {
+		notebooks, err := c.remote.ListNotebooks(ctx, authenticationToken)
+		if err == nil {
+			guids := make([]edam.GUID, len(notebooks))
+			for i, nb := range notebooks {
+				guids[i] = nb.GetGUID()
+			}
+			c.replaceAll(storeNotebooks, guids, func(i int) { c.cache(storeNotebooks, guids[i], notebooks[i]) })
+			return notebooks, nil
+		}
+		if !c.fallBack(err) {
+			return nil, err
+		}
+	}
+	return c.store.notebooks()
+}
+
+// ListTags returns the tags from Evernote, or from the cache when offline.
+func (c *cachingNoteStore) ListTags(ctx context.Context, authenticationToken string) ([]*edam.Tag, error) {
+	if !c.offline {
+		tags, err := c.remote.ListTags(ctx, authenticationToken)
+		if err == nil {
+			guids := make([]edam.GUID, len(tags))
+			for i, tag := range tags {
+				guids[i] = tag.GetGUID()
+			}
+			c.replaceAll(storeTags, guids, func(i int) { c.cache(storeTags, guids[i], tags[i]) })
+			return tags, nil
+		}
+		if !c.fallBack(err) {
+			return nil, err
+		}
+	}
+	return c.store.tags()
+}
+
+// ListSearches returns the saved searches from Evernote, or from the cache when offline.
+func (c *cachingNoteStore) ListSearches(ctx context.Context, authenticationToken string) ([]*edam.SavedSearch, error) {
+	if !c.offline {
+		searches, err := c.remote.ListSearches(ctx, authenticationToken)
+		if err == nil {
+			guids := make([]edam.GUID, len(searches))
+			for i, search := range searches {
+				guids[i] = search.GetGUID()
+			}
+			c.replaceAll(storeSearches, guids, func(i int) { c.cache(storeSearches, guids[i], searches[i]) })
+			return searches, nil
+		}
+		if !c.fallBack(err) {
+			return nil, err
+		}
+	}
+	return c.store.searches()
+}
+
+// FindNotesMetadata searches Evernote, or the cached notes when offline.
+func (c *cachingNoteStore) FindNotesMetadata(ctx context.Context, authenticationToken string, filter *edam.NoteFilter, offset int32, maxNotes int32, resultSpec *edam.NotesMetadataResultSpec) (*edam.NotesMetadataList, error) {
+	if !c.offline {
+		list, err := c.remote.FindNotesMetadata(ctx, authenticationToken, filter, offset, maxNotes, resultSpec)
+		if err == nil || !c.fallBack(err) {
+			return list, err
+		}
+	}
+	return c.findCachedNotes(filter, offset, maxNotes)
+}
+
+// GetNote returns a note from Evernote, caching it when its content was
+// requested, or returns the cached note when offline.
+func (c *cachingNoteStore) GetNote(ctx context.Context, authenticationToken string, guid edam.GUID, withContent bool, withResourcesData bool, withResourcesRecognition bool, withResourcesAlternateData bool) (*edam.Note, error) {
+	if !c.offline {
+		note, err := c.remote.GetNote(ctx, authenticationToken, guid, withContent, withResourcesData, withResourcesRecognition, withResourcesAlternateData)
+		if err == nil {
+			if withContent {
+				c.cacheNote(note)
+			}
+			return note, nil
+		}
+		if !c.fallBack(err) {
+			return nil, err
+		}
+	}
+
+	var note edam.Note
+	if err := c.store.get(storeNotes, guid, &note); err != nil {
+		return nil, offlineError(fmt.Sprintf("note %s", guid))
+	}
+	if !withContent {
+		note.Content = nil
+	}
+	if withResourcesData {
+		for i, res := range note.Resources {
+			if data, err := c.store.resourceData(res.GetGUID()); err == nil {
+				note.Resources[i] = withResourceBody(res, data)
+			}
+		}
+	}
+	return &note, nil
+}
+
+// cacheNote stores a note without its resource bodies.
+func (c *cachingNoteStore) cacheNote(note *edam.Note) {
+	stored := *note
+	stored.Resources = make([]*edam.Resource, len(note.Resources))
+	for i, res := range note.Resources {
+		stored.Resources[i] = withResourceBody(res, nil)
+	}
+	c.cache(storeNotes, note.GetGUID(), &stored)
+}
+
+// withResourceBody returns a copy of res with its body replaced.
+func withResourceBody(res *edam.Resource, body []byte) *edam.Resource {
+	copied := *res
+	if res.Data != nil {
+		data := *res.Data
+		data.Body = body
+		copied.Data = &data
+	}
+	return &copied
+}
+
+// GetResource returns a resource from Evernote, caching its metadata, or
+// returns the cached resource when offline.
+func (c *cachingNoteStore) GetResource(ctx context.Context, authenticationToken string, guid edam.GUID, withData bool, withRecognition bool, withAttributes bool, withAlternateData bool) (*edam.Resource, error) {
+	if !c.offline {
+		res, err := c.remote.GetResource(ctx, authenticationToken, guid, withData, withRecognition, withAttributes, withAlternateData)
+		if err == nil {
+			c.cache(storeResources, guid, withResourceBody(res, nil))
+			return res, nil
+		}
+		if !c.fallBack(err) {
+			return nil, err
+		}
+	}
+
+	res, err := c.cachedResource(guid)
+	if err != nil {
+		return nil, err
+	}
+	if withData {
+		data, err := c.store.resourceData(guid)
+		if err != nil {
+			return nil, offlineError(fmt.Sprintf("resource %s", guid))
+		}
+		res = withResourceBody(res, data)
+	}
+	return res, nil
+}
+
+// cachedResource returns the metadata of a cached resource, looking through
+// the cached notes when the resource itself was never fetched.
+func (c *cachingNoteStore) cachedResource(guid edam.GUID) (*edam.Resource, error) {
+	var res edam.Resource
+	if err := c.store.get(storeResources, guid, &res); err == nil {
+		return &res, nil
+	}
+	notes, err := c.store.notes()
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		for _, r := range note.GetResources() {
+			if r.GetGUID() == guid {
+				return r, nil
+			}
+		}
+	}
+	return nil, offlineError(fmt.Sprintf("resource %s", guid))
+}
+
+// CreateNote creates a note in Evernote.
+func (c *cachingNoteStore) CreateNote(ctx context.Context, authenticationToken string, note *edam.Note) (*edam.Note, error) {
+	if c.offline {
+		return nil, offlineError("creating notes")
+	}
+	return c.remote.CreateNote(ctx, authenticationToken, note)
+}
+
+// UpdateNote updates a note in Evernote and drops the cached copy.
+func (c *cachingNoteStore) UpdateNote(ctx context.Context, authenticationToken string, note *edam.Note) (*edam.Note, error) {
+	if c.offline {
+		return nil, offlineError("updating notes")
+	}
+	updated, err := c.remote.UpdateNote(ctx, authenticationToken, note)
+	if err == nil {
+		c.forget(storeNotes, note.GetGUID())
+	}
+	return updated, err
+}
+
+// DeleteNote moves a note to the trash in Evernote and drops the cached copy.
+func (c *cachingNoteStore) DeleteNote(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error) {
+	if c.offline {
+		return 0, offlineError("deleting notes")
+	}
+	usn, err := c.remote.DeleteNote(ctx, authenticationToken, guid)
+	if err == nil {
+		c.forget(storeNotes, guid)
+	}
+	return usn, err
+}
+
+// ExpungeNote permanently deletes a note in Evernote and drops the cached copy.
+func (c *cachingNoteStore) ExpungeNote(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error) {
+	if c.offline {
+		return 0, offlineError("deleting notes")
+	}
+	usn, err := c.remote.ExpungeNote(ctx, authenticationToken, guid)
+	if err == nil {
+		c.forget(storeNotes, guid)
+	}
+	return usn, err
+}
+
+// CreateNotebook creates a notebook in Evernote and caches it.
+func (c *cachingNoteStore) CreateNotebook(ctx context.Context, authenticationToken string, notebook *edam.Notebook) (*edam.Notebook, error) {
+	if c.offline {
+		return nil, offlineError("creating notebooks")
+	}
+	created, err := c.remote.CreateNotebook(ctx, authenticationToken, notebook)
+	if err == nil {
+		c.cache(storeNotebooks, created.GetGUID(), created)
+	}
+	return created, err
+}
+
+// UpdateNotebook updates a notebook in Evernote and caches it.
+func (c *cachingNoteStore) UpdateNotebook(ctx context.Context, authenticationToken string, notebook *edam.Notebook) (int32, error) {
+	if c.offline {
+		return 0, offlineError("updating notebooks")
+	}
+	usn, err := c.remote.UpdateNotebook(ctx, authenticationToken, notebook)
+	if err == nil {
+		c.cache(storeNotebooks, notebook.GetGUID(), notebook)
+	}
+	return usn, err
+}
+
+// ExpungeNotebook deletes a notebook in Evernote and drops the cached copy.
+func (c *cachingNoteStore) ExpungeNotebook(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error) {
+	if c.offline {
+		return 0, offlineError("deleting notebooks")
+	}
+	usn, err := c.remote.ExpungeNotebook(ctx, authenticationToken, guid)
+	if err == nil {
+		c.forget(storeNotebooks, guid)
+	}
+	return usn, err
+}
+
+// CreateTag creates a tag in Evernote and caches it.
+func (c *cachingNoteStore) CreateTag(ctx context.Context, authenticationToken string, tag *edam.Tag) (*edam.Tag, error) {
+	if c.offline {
+		return nil, offlineError("creating tags")
+	}
+	created, err := c.remote.CreateTag(ctx, authenticationToken, tag)
+	if err == nil {
+		c.cache(storeTags, created.GetGUID(), created)
+	}
+	return created, err
+}
+
+// UpdateTag updates a tag in Evernote and caches it.
+func (c *cachingNoteStore) UpdateTag(ctx context.Context, authenticationToken string, tag *edam.Tag) (int32, error) {
+	if c.offline {
+		return 0, offlineError("updating tags")
+	}
+	usn, err := c.remote.UpdateTag(ctx, authenticationToken, tag)
+	if err == nil {
+		c.cache(storeTags, tag.GetGUID(), tag)
+	}
+	return usn, err
+}
+
+// ExpungeTag deletes a tag in Evernote and drops the cached copy.
+func (c *cachingNoteStore) ExpungeTag(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error) {
+	if c.offline {
+		return 0, offlineError("deleting tags")
+	}
+	usn, err := c.remote.ExpungeTag(ctx, authenticationToken, guid)
+	if err == nil {
+		c.forget(storeTags, guid)
+	}
+	return usn, err
+}
+
+// CreateSearch creates a saved search in Evernote and caches it.
+func (c *cachingNoteStore) CreateSearch(ctx context.Context, authenticationToken string, search *edam.SavedSearch) (*edam.SavedSearch, error) {
+	if c.offline {
+		return nil, offlineError("creating saved searches")
+	}
+	created, err := c.remote.CreateSearch(ctx, authenticationToken, search)
+	if err == nil {
+		c.cache(storeSearches, created.GetGUID(), created)
+	}
+	return created, err
+}
+
+// UpdateSearch updates a saved search in Evernote and caches it.
+func (c *cachingNoteStore) UpdateSearch(ctx context.Context, authenticationToken string, search *edam.SavedSearch) (int32, error) {
+	if c.offline {
+		return 0, offlineError("updating saved searches")
+	}
+	usn, err := c.remote.UpdateSearch(ctx, authenticationToken, search)
+	if err == nil {
+		c.cache(storeSearches, search.GetGUID(), search)
+	}
+	return usn, err
+}
+
+// ExpungeSearch deletes a saved search in Evernote and drops the cached copy.
+func (c *cachingNoteStore) ExpungeSearch(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error) {
+	if c.offline {
+		return 0, offlineError("deleting saved searches")
+	}
+	usn, err := c.remote.ExpungeSearch(ctx, authenticationToken, guid)
+	if err == nil {
+		c.forget(storeSearches, guid)
+	}
+	return usn, err
+}
+
+// GetSyncState returns the account's sync state from Evernote.
+func (c *cachingNoteStore) GetSyncState(ctx context.Context, authenticationToken string) (*edam.SyncState, error) {
+	if c.offline {
+		return nil, offlineError("sync")
+	}
+	return c.remote.GetSyncState(ctx, authenticationToken)
+}
+
+// GetFilteredSyncChunk returns a sync chunk from Evernote.
+func (c *cachingNoteStore) GetFilteredSyncChunk(ctx context.Context, authenticationToken string, afterUSN int32, maxEntries int32, filter *edam.SyncChunkFilter) (*edam.SyncChunk, error) {
+	if c.offline {
+		return nil, offlineError("sync")
+	}
+	return c.remote.GetFilteredSyncChunk(ctx, authenticationToken, afterUSN, maxEntries, filter)
+}
+
+// findCachedNotes answers a search from the index of the cached notes.
+func (c *cachingNoteStore) findCachedNotes(filter *edam.NoteFilter, offset, maxNotes int32) (*edam.NotesMetadataList, error) {
+	if c.index == nil {
+		ix, err := loadSearchIndex(c.store)
+		if err != nil {
+			return nil, err
+		}
+		c.index = ix
+	}
+	results, _, err := searchLocalStore(c.store, c.index, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &edam.NotesMetadataList{StartIndex: offset, TotalNotes: int32(len(results))}
+	for i := int(offset); i < len(results) && i < int(offset)+int(maxNotes); i++ {
+		list.Notes = append(list.Notes, results[i].NoteMetadata)
+	}
+	return list, nil
+}
+
+// noteMetadata returns the search metadata for a note.
+func noteMetadata(note *edam.Note) *edam.NoteMetadata {
+	return &edam.NoteMetadata{
+		GUID:              note.GetGUID(),
+		Title:             note.Title,
+		ContentLength:     note.ContentLength,
+		Created:           note.Created,
+		Updated:           note.Updated,
+		Deleted:           note.Deleted,
+		UpdateSequenceNum: note.UpdateSequenceNum,
+		NotebookGuid:      note.NotebookGuid,
+		TagGuids:          note.TagGuids,
+		Attributes:        note.Attributes,
+	}
+}
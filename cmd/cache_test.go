@@ -0,0 +1,301 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// networkError returns the error the SDK reports when Evernote is unreachable.
+func networkError() error {
+	return thrift.NewTTransportExceptionFromError(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})
+}
+
+// newTestCache returns a cache over mock backed by a temporary store, with
+// warnings written to the returned buffer.
+func newTestCache(t *testing.T, mock noteStoreClient, offline bool) (*cachingNoteStore, *bytes.Buffer) {
+	t.Helper()
+	store, err := openLocalStore(t.TempDir())
+	require.NoError(t, err)
+	c := newCachingNoteStore(mock, store, offline)
+	var warn bytes.Buffer
+	c.warn = &warn
+	return c, &warn
+}
+
+func TestIsNetworkError(t *testing.T) {
+	assert.True(t, isNetworkError(networkError()))
+	assert.True(t, isNetworkError(&net.DNSError{Err: "no such host", Name: "www.evernote.com"}))
+	assert.False(t, isNetworkError(thrift.NewTTransportException(thrift.UNKNOWN_TRANSPORT_EXCEPTION, "HTTP Response code: 500")))
+	assert.False(t, isNetworkError(&edam.EDAMNotFoundException{}))
+	assert.False(t, isNetworkError(fmt.Errorf("boom")))
+}
+
+func TestCachingNoteStore(t *testing.T) {
+	ctx := context.Background()
+	nbGUID, nbName := edam.GUID("nb-1"), "Inbox"
+	tagGUID, tagName := edam.GUID("tag-1"), "Travel"
+	notebookGUID := string(nbGUID)
+	newNote := func(guid, title, body string, updated edam.Timestamp, tags ...edam.GUID) *edam.Note {
+		g, content := edam.GUID(guid), wrapENML(body)
+		return &edam.Note{GUID: &g, Title: &title, Content: &content, Updated: &updated, Created: &updated, NotebookGuid: &notebookGUID, TagGuids: tags}
+	}
+
+	t.Run("reads are cached and served offline", func(t *testing.T) {
+		resGUID, mime := edam.GUID("res-1"), "image/png"
+		note := newNote("n-1", "Packing list", "passport &amp; charger", 1000, tagGUID)
+		note.Resources = []*edam.Resource{{GUID: &resGUID, Mime: &mime, Data: &edam.Data{Body: []byte("png")}}}
+		mock := &mockNoteStore{
+			notebooks: []*edam.Notebook{{GUID: &nbGUID, Name: &nbName}},
+			tags:      []*edam.Tag{{GUID: &tagGUID, Name: &tagName}},
+			gotNote:   note,
+		}
+		c, warn := newTestCache(t, mock, false)
+
+		_, err := c.ListNotebooks(ctx, "token")
+		require.NoError(t, err)
+		_, err = c.ListTags(ctx, "token")
+		require.NoError(t, err)
+		_, err = c.GetNote(ctx, "token", "n-1", true, true, false, false)
+		require.NoError(t, err)
+
+		// The network goes down
+		mock.err = networkError()
+		notebooks, err := c.ListNotebooks(ctx, "token")
+		require.NoError(t, err)
+		require.Len(t, notebooks, 1)
+		assert.Equal(t, "Inbox", notebooks[0].GetName())
+		assert.Equal(t, "Evernote is unreachable, using the offline cache.\n", warn.String())
+
+		got, err := c.GetNote(ctx, "token", "n-1", true, true, false, false)
+		require.NoError(t, err)
+		assert.Equal(t, note.GetContent(), got.GetContent())
+		assert.Nil(t, got.Resources[0].GetData().GetBody(), "resource bodies are not cached on read")
+
+		_, err = c.GetResource(ctx, "token", resGUID, true, false, true, false)
+		require.Error(t, err)
+		assert.Equal(t, "resource res-1 is not available offline", err.Error())
+
+		// Bodies downloaded by 'sync --resources' are served
+		require.NoError(t, c.store.putResourceData(resGUID, []byte("png")))
+		got, err = c.GetNote(ctx, "token", "n-1", true, true, false, false)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("png"), got.Resources[0].GetData().GetBody())
+		res, err := c.GetResource(ctx, "token", resGUID, true, false, true, false)
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", res.GetMime())
+		assert.Equal(t, []byte("png"), res.GetData().GetBody())
+
+		_, err = c.GetNote(ctx, "token", "missing", true, false, false, false)
+		require.Error(t, err)
+		assert.Equal(t, "note missing is not available offline", err.Error())
+
+		_, err = c.CreateNote(ctx, "token", &edam.Note{})
+		require.Error(t, err)
+		assert.Equal(t, "creating notes is not available offline", err.Error())
+		assert.Equal(t, 1, bytes.Count(warn.Bytes(), []byte("\n")))
+	})
+
+	t.Run("other errors are returned", func(t *testing.T) {
+		mock := &mockNoteStore{err: fmt.Errorf("rate limited")}
+		c, warn := newTestCache(t, mock, false)
+		_, err := c.ListTags(ctx, "token")
+		require.Error(t, err)
+		assert.Equal(t, "rate limited", err.Error())
+		assert.False(t, c.offline)
+		assert.Empty(t, warn.String())
+	})
+
+	t.Run("list results replace the cache", func(t *testing.T) {
+		other := edam.GUID("tag-2")
+		mock := &mockNoteStore{tags: []*edam.Tag{{GUID: &tagGUID, Name: &tagName}, {GUID: &other, Name: &tagName}}}
+		c, _ := newTestCache(t, mock, false)
+		_, err := c.ListTags(ctx, "token")
+		require.NoError(t, err)
+
+		mock.tags = mock.tags[:1]
+		_, err = c.ListTags(ctx, "token")
+		require.NoError(t, err)
+		guids, err := c.store.list(storeTags)
+		require.NoError(t, err)
+		assert.Equal(t, []edam.GUID{tagGUID}, guids)
+	})
+
+	t.Run("writes update the cache", func(t *testing.T) {
+		mock := &mockNoteStore{}
+		c, _ := newTestCache(t, mock, false)
+		note := newNote("n-1", "Draft", "text", 1000)
+		require.NoError(t, c.store.put(storeNotes, "n-1", note))
+
+		_, err := c.UpdateNote(ctx, "token", note)
+		require.NoError(t, err)
+		guids, err := c.store.list(storeNotes)
+		require.NoError(t, err)
+		assert.Empty(t, guids)
+
+		created, err := c.CreateNotebook(ctx, "token", &edam.Notebook{Name: &nbName})
+		require.NoError(t, err)
+		guids, err = c.store.list(storeNotebooks)
+		require.NoError(t, err)
+		assert.Equal(t, []edam.GUID{created.GetGUID()}, guids)
+
+		_, err = c.ExpungeNotebook(ctx, "token", created.GetGUID())
+		require.NoError(t, err)
+		guids, err = c.store.list(storeNotebooks)
+		require.NoError(t, err)
+		assert.Empty(t, guids)
+	})
+
+	t.Run("offline search", func(t *testing.T) {
+		c, _ := newTestCache(t, nil, true)
+		require.NoError(t, c.store.put(storeTags, tagGUID, &edam.Tag{GUID: &tagGUID, Name: &tagName}))
+		notes := []*edam.Note{
+			newNote("n-1", "Packing list", "passport and charger", 3000, tagGUID),
+			newNote("n-2", "Rome trip", "book the train", 1000, tagGUID),
+			newNote("n-3", "Groceries", "milk, passport photos", 2000),
+		}
+		trashed := newNote("n-4", "Old passport", "expired", 4000)
+		inactive := false
+		trashed.Active = &inactive
+		for _, note := range append(notes, trashed) {
+			require.NoError(t, c.store.put(storeNotes, note.GetGUID(), note))
+		}
+
+		search := func(words string, order edam.NoteSortOrder, ascending bool) []string {
+			t.Helper()
+			o := int32(order)
+			filter := &edam.NoteFilter{Words: &words, Order: &o, Ascending: &ascending}
+			list, err := c.FindNotesMetadata(ctx, "", filter, 0, 10, nil)
+			require.NoError(t, err)
+			var titles []string
+			for _, n := range list.Notes {
+				titles = append(titles, n.GetTitle())
+			}
+			return titles
+		}
+
+		assert.Equal(t, []string{"Packing list", "Groceries"}, search("passport", edam.NoteSortOrder_UPDATED, false))
+		assert.Equal(t, []string{"Packing list"}, search("tag:travel passport", edam.NoteSortOrder_UPDATED, false))
+		assert.Equal(t, []string{"Groceries"}, search("passport -tag:travel", edam.NoteSortOrder_UPDATED, false))
+		assert.Equal(t, []string{"Packing list", "Rome trip"}, search(`tag:"travel"`, edam.NoteSortOrder_TITLE, true))
+		assert.Equal(t, []string{"Groceries", "Packing list"}, search("updated:19700101T000002Z", edam.NoteSortOrder_UPDATED, true))
+		assert.Equal(t, []string{"Rome trip"}, search(`"the train"`, edam.NoteSortOrder_UPDATED, false))
+
+		o := int32(edam.NoteSortOrder_UPDATED)
+		yes := true
+		list, err := c.FindNotesMetadata(ctx, "", &edam.NoteFilter{Order: &o, Inactive: &yes}, 0, 10, nil)
+		require.NoError(t, err)
+		require.Len(t, list.Notes, 1)
+		assert.Equal(t, "Old passport", list.Notes[0].GetTitle())
+
+		list, err = c.FindNotesMetadata(ctx, "", &edam.NoteFilter{Order: &o}, 1, 1, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), list.TotalNotes)
+		require.Len(t, list.Notes, 1)
+		assert.Equal(t, "Groceries", list.Notes[0].GetTitle())
+
+		words := "source:mobile"
+		_, err = c.FindNotesMetadata(ctx, "", &edam.NoteFilter{Words: &words}, 0, 10, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `search term "source:mobile" is not supported locally`)
+	})
+}
+
+func TestOfflineFlag(t *testing.T) {
+	assert.NotNil(t, rootCmd.PersistentFlags().Lookup("offline"))
+
+	setDataDir(t)
+	offlineFlag = true
+	defer func() { offlineFlag = false }()
+
+	ns, _, err := getDefaultNoteStore()
+	require.NoError(t, err)
+	c, ok := ns.(*cachingNoteStore)
+	require.True(t, ok)
+	assert.True(t, c.offline)
+}
+
+func TestRemoteNoteStore(t *testing.T) {
+	setConfigPath(t)
+	setDataDir(t)
+	require.NoError(t, saveConfig(&Config{AuthToken: "token", NoteStoreURL: "http://127.0.0.1:1/shard/s1/notestore"}))
+
+	ns, token, err := getRemoteNoteStore()
+	require.NoError(t, err)
+	assert.Equal(t, "token", token)
+	_, cached := ns.(*cachingNoteStore)
+	assert.False(t, cached, "sync and export do not fall back to the cache")
+	_, err = ns.GetSyncState(context.Background(), token)
+	assert.True(t, isNetworkError(err))
+
+	offlineFlag = true
+	defer func() { offlineFlag = false }()
+	ns, _, err = getRemoteNoteStore()
+	require.NoError(t, err)
+	_, cached = ns.(*cachingNoteStore)
+	assert.True(t, cached, "--offline still reads the cache")
+}
+
+func TestDefaultNoteStoreCache(t *testing.T) {
+	setConfigPath(t)
+	dir := setDataDir(t)
+	require.NoError(t, saveConfig(&Config{AuthToken: "token", NoteStoreURL: "http://127.0.0.1:1/shard/s1/notestore"}))
+	var warn bytes.Buffer
+	cacheWarnings = &warn
+	defer func() { cacheWarnings = os.Stderr }()
+
+	ns, _, err := getDefaultNoteStore()
+	require.NoError(t, err)
+	_, cached := ns.(*cachingNoteStore)
+	assert.True(t, cached)
+
+	t.Run("no-cache", func(t *testing.T) {
+		noCacheFlag = true
+		defer func() { noCacheFlag = false }()
+		ns, _, err := getDefaultNoteStore()
+		require.NoError(t, err)
+		_, cached := ns.(*cachingNoteStore)
+		assert.False(t, cached)
+	})
+
+	t.Run("store cannot be opened", func(t *testing.T) {
+		blocked := filepath.Join(dir, "file")
+		require.NoError(t, os.WriteFile(blocked, nil, 0600))
+		dataDir = filepath.Join(blocked, "data")
+		ns, token, err := getDefaultNoteStore()
+		require.NoError(t, err)
+		assert.Equal(t, "token", token)
+		_, cached := ns.(*cachingNoteStore)
+		assert.False(t, cached)
+		assert.Contains(t, warn.String(), "continuing without the local cache")
+	})
+
+	t.Run("store cannot be written", func(t *testing.T) {
+		guid := edam.GUID("nb-1")
+		mock := &mockNoteStore{notebooks: []*edam.Notebook{{GUID: &guid}}}
+		c, warn := newTestCache(t, mock, false)
+		notebooks := filepath.Join(c.store.dir, storeNotebooks)
+		require.NoError(t, os.RemoveAll(notebooks))
+		require.NoError(t, os.WriteFile(notebooks, nil, 0600))
+
+		for i := 0; i < 2; i++ {
+			list, err := c.ListNotebooks(context.Background(), "")
+			require.NoError(t, err)
+			assert.Len(t, list, 1)
+		}
+		assert.Equal(t, 1, strings.Count(warn.String(), "could not write the local cache"))
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/spf13/cobra"
+)
+
+var deleteQuery string
+
+// noteActionResult is the JSON output of bulk note commands.
+type noteActionResult struct {
+	GUID  string `json:"guid"`
+	Error string `json:"error,omitempty"`
+}
+
+// findAllNotes returns every note matching filter.
+func findAllNotes(ns noteStoreClient, token string, filter *edam.NoteFilter, spec *edam.NotesMetadataResultSpec) ([]*edam.NoteMetadata, error) {
+	var notes []*edam.NoteMetadata
+	_, err := pageNotes(ns, token, filter, spec, 0, -1, func(i int, note *edam.NoteMetadata) error {
+		notes = append(notes, note)
+		return nil
+	})
+	return notes, err
+}
+
+// noteGUIDsFromInput returns the GUIDs named on the command line, read from
+// stdin when the only argument is "-", or matched by a search query. Trashed
+// notes are searched when inactive is set.
+func noteGUIDsFromInput(cmd *cobra.Command, ns noteStoreClient, token string, args []string, query string, inactive bool) ([]edam.GUID, error) {
+	if query != "" && len(args) > 0 {
+		return nil, fmt.Errorf("GUID arguments cannot be used with --query")
+	}
+
+	var guids []edam.GUID
+	switch {
+	case query != "":
+		filter := &edam.NoteFilter{Words: &query}
+		if inactive {
+			filter.Inactive = &inactive
+		}
+		notes, err := findAllNotes(ns, token, filter, &edam.NotesMetadataResultSpec{})
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range notes {
+			guids = append(guids, note.GetGUID())
+		}
+	case len(args) == 1 && args[0] == "-":
+		scanner := bufio.NewScanner(cmd.InOrStdin())
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				guids = append(guids, edam.GUID(line))
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read GUIDs from stdin: %w", err)
+		}
+	default:
+		for _, arg := range args {
+			guids = append(guids, edam.GUID(arg))
+		}
+	}
+
+	if len(guids) == 0 && query == "" {
+		return nil, fmt.Errorf("no note GUIDs given")
+	}
+	return guids, nil
+}
+
+// applyToNotes runs fn for every GUID, reporting each result, and returns an
+// error if any of them failed.
+func applyToNotes(cmd *cobra.Command, guids []edam.GUID, done, action string, fn func(edam.GUID) error) error {
+	out := cmd.OutOrStdout()
+	results := make([]noteActionResult, 0, len(guids))
+	failed := 0
+	for _, guid := range guids {
+		result := noteActionResult{GUID: string(guid)}
+		if err := fn(guid); err != nil {
+			failed++
+			result.Error = formatAPIError(err).Error()
+			if !jsonFlag {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Failed to %s %s: %s\n", action, guid, result.Error)
+			}
+		} else if !jsonFlag {
+			fmt.Fprintf(out, "%s: %s\n", done, guid)
+		}
+		results = append(results, result)
+	}
+
+	if jsonFlag {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else if len(guids) == 0 {
+		fmt.Fprintln(out, "No notes found.")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d of %d note(s)", action, failed, len(guids))
+	}
+	return nil
+}
+
+// deleteCmd moves notes to the trash.
+var deleteCmd = &cobra.Command{
+	Use:   "delete [guid...]",
+	Short: "Move notes to the trash",
+	Long: `Move one or more notes to the trash. Trashed notes can be listed with
+"trash list" and brought back with "restore".
+
+Pass - to read GUIDs from stdin, one per line, or use --query to delete every
+note matching a search.
+
+Examples:
+  evernote-cli delete <guid>
+  evernote-cli delete <guid> <guid>
+  evernote-cli search "tag:scratch" --json | jq -r '.notes[].guid' | evernote-cli delete -
+  evernote-cli delete --query "tag:scratch"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		guids, err := noteGUIDsFromInput(cmd, ns, token, args, deleteQuery, false)
+		if err != nil {
+			return err
+		}
+
+		return applyToNotes(cmd, guids, "Moved to trash", "delete", func(guid edam.GUID) error {
+			_, err := ns.DeleteNote(context.Background(), token, guid)
+			return err
+		})
+	},
+}
+
+func init() {
+	deleteCmd.Flags().StringVar(&deleteQuery, "query", "", "delete every note matching this search query")
+	rootCmd.AddCommand(deleteCmd)
+}
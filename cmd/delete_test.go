@@ -0,0 +1,128 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteCommand(t *testing.T) {
+	t.Run("delete by guid", func(t *testing.T) {
+		mock := &mockNoteStore{}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		deleteCmd.SetOut(&buf)
+		err := deleteCmd.RunE(deleteCmd, []string{"note-1", "note-2"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []edam.GUID{"note-1", "note-2"}, mock.deleted)
+		assert.Contains(t, buf.String(), "Moved to trash: note-1")
+		assert.Contains(t, buf.String(), "Moved to trash: note-2")
+	})
+
+	t.Run("guids from stdin", func(t *testing.T) {
+		mock := &mockNoteStore{}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		deleteCmd.SetOut(&buf)
+		deleteCmd.SetIn(strings.NewReader("note-1\n\n  note-2  \n"))
+		defer deleteCmd.SetIn(nil)
+		err := deleteCmd.RunE(deleteCmd, []string{"-"})
+		require.NoError(t, err)
+		assert.Equal(t, []edam.GUID{"note-1", "note-2"}, mock.deleted)
+	})
+
+	t.Run("guids from query", func(t *testing.T) {
+		mock := &mockNoteStore{
+			notes: &edam.NotesMetadataList{
+				TotalNotes: 2,
+				Notes:      []*edam.NoteMetadata{{GUID: "note-1"}, {GUID: "note-2"}},
+			},
+		}
+		defer setMockNoteStore(mock)()
+
+		deleteQuery = "tag:scratch"
+		jsonFlag = true
+		defer func() { deleteQuery = ""; jsonFlag = false }()
+
+		var buf bytes.Buffer
+		deleteCmd.SetOut(&buf)
+		err := deleteCmd.RunE(deleteCmd, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "tag:scratch", mock.filter.GetWords())
+		assert.Nil(t, mock.filter.Inactive)
+		assert.Equal(t, []edam.GUID{"note-1", "note-2"}, mock.deleted)
+
+		var results []noteActionResult
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &results))
+		assert.Equal(t, []noteActionResult{{GUID: "note-1"}, {GUID: "note-2"}}, results)
+	})
+
+	t.Run("query and guids together", func(t *testing.T) {
+		defer setMockNoteStore(&mockNoteStore{})()
+
+		deleteQuery = "x"
+		defer func() { deleteQuery = "" }()
+		err := deleteCmd.RunE(deleteCmd, []string{"note-1"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot be used with --query")
+	})
+
+	t.Run("no guids", func(t *testing.T) {
+		defer setMockNoteStore(&mockNoteStore{})()
+
+		err := deleteCmd.RunE(deleteCmd, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no note GUIDs given")
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		defer setMockNoteStore(&mockNoteStore{err: fmt.Errorf("boom"), notebooks: []*edam.Notebook{}})()
+
+		var buf, errBuf bytes.Buffer
+		deleteCmd.SetOut(&buf)
+		deleteCmd.SetErr(&errBuf)
+		defer deleteCmd.SetErr(nil)
+		err := deleteCmd.RunE(deleteCmd, []string{"note-1"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to delete 1 of 1 note(s)")
+		assert.Contains(t, errBuf.String(), "Failed to delete note-1: boom")
+	})
+}
+
+func TestFindAllNotes(t *testing.T) {
+	mock := &mockNoteStore{
+		notes: &edam.NotesMetadataList{TotalNotes: 1, Notes: []*edam.NoteMetadata{{GUID: "note-1"}}},
+	}
+	notes, err := findAllNotes(mock, "token", &edam.NoteFilter{}, &edam.NotesMetadataResultSpec{})
+	require.NoError(t, err)
+	assert.Len(t, notes, 1)
+}
+
+func TestDeleteCmdConfiguration(t *testing.T) {
+	assert.Equal(t, "delete [guid...]", deleteCmd.Use)
+	assert.Equal(t, "Move notes to the trash", deleteCmd.Short)
+	assert.NotNil(t, deleteCmd.RunE)
+}
+
+func TestDeleteCmdRegistration(t *testing.T) {
+	found := false
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "delete" {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "delete command should be registered")
+}
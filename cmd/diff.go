@@ -0,0 +1,301 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffOp is a single line in an edit script: ' ' for a line kept from both
+// sides, '-' for a line only in the old text and '+' for one only in the new.
+type diffOp struct {
+	kind byte
+	line string
+	a, b int
+}
+
+// diffLines returns the shortest edit script turning a into b using the
+// linear-space variant of the Myers O(ND) algorithm: the middle snake of an
+// optimal path splits the problem in two, and each half is solved the same
+// way, so memory stays proportional to the length of the texts.
+func diffLines(a, b []string) []diffOp {
+	var ops []diffOp
+	diffRange(a, b, 0, len(a), 0, len(b), &ops)
+	return groupChanges(ops)
+}
+
+// diffRange appends the edit script turning a[x0:x1] into b[y0:y1] to ops.
+func diffRange(a, b []string, x0, x1, y0, y1 int, ops *[]diffOp) {
+	for x0 < x1 && y0 < y1 && a[x0] == b[y0] {
+		*ops = append(*ops, diffOp{kind: ' ', line: a[x0], a: x0, b: y0})
+		x0++
+		y0++
+	}
+	suffix := 0
+	for x1 > x0 && y1 > y0 && a[x1-1] == b[y1-1] {
+		x1--
+		y1--
+		suffix++
+	}
+
+	switch {
+	case x0 == x1:
+		for y := y0; y < y1; y++ {
+			*ops = append(*ops, diffOp{kind: '+', line: b[y], a: x0, b: y})
+		}
+	case y0 == y1:
+		for x := x0; x < x1; x++ {
+			*ops = append(*ops, diffOp{kind: '-', line: a[x], a: x, b: y0})
+		}
+	default:
+		// Both sides differ at their ends here, so the edit distance is at
+		// least 2 and each half is strictly smaller than the whole.
+		x, y, u, v := middleSnake(a[x0:x1], b[y0:y1])
+		diffRange(a, b, x0, x0+x, y0, y0+y, ops)
+		for i := 0; i < u-x; i++ {
+			*ops = append(*ops, diffOp{kind: ' ', line: a[x0+x+i], a: x0 + x + i, b: y0 + y + i})
+		}
+		diffRange(a, b, x0+u, x1, y0+v, y1, ops)
+	}
+
+	for i := 0; i < suffix; i++ {
+		*ops = append(*ops, diffOp{kind: ' ', line: a[x1+i], a: x1 + i, b: y1 + i})
+	}
+}
+
+// middleSnake finds the middle snake of a shortest edit script turning a
+// into b by searching forwards from the start and backwards from the end
+// until the two paths overlap. The snake runs from (x, y) to (u, v).
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	max := (n + m + 1) / 2
+	offset := max + 1
+	// forward[k] is the furthest x reached on diagonal k = x-y from the
+	// start; backward[k] the same counted from the end of both texts.
+	forward := make([]int, 2*max+3)
+	backward := make([]int, 2*max+3)
+
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			if back := delta - k; odd && back >= -(d-1) && back <= d-1 && x+backward[offset+back] >= n {
+				return startX, startY, x, y
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			if fwd := delta - k; !odd && fwd >= -d && fwd <= d && x+forward[offset+fwd] >= n {
+				return n - x, m - y, n - startX, m - startY
+			}
+		}
+	}
+	return 0, 0, 0, 0
+}
+
+// groupChanges puts the deletions of each run of changes before its
+// insertions, as unified diffs show them.
+func groupChanges(ops []diffOp) []diffOp {
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		j := i
+		var removed, added []diffOp
+		for ; j < len(ops) && ops[j].kind != ' '; j++ {
+			if ops[j].kind == '-' {
+				removed = append(removed, ops[j])
+			} else {
+				added = append(added, ops[j])
+			}
+		}
+		x, y := ops[i].a, ops[i].b
+		k := i
+		for n, op := range removed {
+			ops[k] = diffOp{kind: '-', line: op.line, a: x + n, b: y}
+			k++
+		}
+		for n, op := range added {
+			ops[k] = diffOp{kind: '+', line: op.line, a: x + len(removed), b: y + n}
+			k++
+		}
+		i = j
+	}
+	return ops
+}
+
+// splitLines splits text into lines without a trailing empty line.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// unifiedDiff renders the differences between two texts as a unified diff
+// with three lines of context. It returns "" when the texts are equal.
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+	const context = 3
+
+	var b strings.Builder
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// Extend the hunk while changes are within 2*context lines of each other
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end += context
+				if end > len(ops) {
+					end = len(ops)
+				}
+				break
+			}
+			end = run
+		}
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		oldCount, newCount := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", ops[start].a+1, oldCount, ops[start].b+1, newCount)
+		for _, op := range ops[start:end] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.line + "\n")
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// matchedLines maps each line index in base to its index in other for lines
+// the diff keeps unchanged.
+func matchedLines(base, other []string) map[int]int {
+	m := make(map[int]int)
+	for _, op := range diffLines(base, other) {
+		if op.kind == ' ' {
+			m[op.a] = op.b
+		}
+	}
+	return m
+}
+
+// mergeLines performs a line-based three-way merge of two edits of base. It
+// returns the merged text and whether any conflicts were marked in it.
+func mergeLines(baseText, oursText, theirsText, oursName, theirsName string) (string, bool) {
+	base, ours, theirs := splitLines(baseText), splitLines(oursText), splitLines(theirsText)
+	mo, mt := matchedLines(base, ours), matchedLines(base, theirs)
+
+	var out []string
+	conflict := false
+	i, o, t := 0, 0, 0
+	for i < len(base) || o < len(ours) || t < len(theirs) {
+		if oi, ok := mo[i]; ok && oi == o {
+			if ti, ok := mt[i]; ok && ti == t {
+				out = append(out, base[i])
+				i, o, t = i+1, o+1, t+1
+				continue
+			}
+		}
+
+		// Find the next base line that both sides still have, in order
+		j := i
+		for ; j < len(base); j++ {
+			oj, okO := mo[j]
+			tj, okT := mt[j]
+			if okO && okT && oj >= o && tj >= t {
+				break
+			}
+		}
+		oEnd, tEnd := len(ours), len(theirs)
+		if j < len(base) {
+			oEnd, tEnd = mo[j], mt[j]
+		}
+
+		b, oc, tc := base[i:j], ours[o:oEnd], theirs[t:tEnd]
+		switch {
+		case equalLines(oc, tc), equalLines(b, tc):
+			out = append(out, oc...)
+		case equalLines(b, oc):
+			out = append(out, tc...)
+		default:
+			conflict = true
+			out = append(out, "<<<<<<< "+oursName)
+			out = append(out, oc...)
+			out = append(out, "=======")
+			out = append(out, tc...)
+			out = append(out, ">>>>>>> "+theirsName)
+		}
+		i, o, t = j, oEnd, tEnd
+	}
+
+	merged := strings.Join(out, "\n")
+	if merged != "" {
+		merged += "\n"
+	}
+	return merged, conflict
+}
+
+// equalLines reports whether two line slices are identical.
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLines(t *testing.T) {
+	ops := diffLines([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+	var kinds string
+	for _, op := range ops {
+		kinds += string(op.kind)
+	}
+	assert.Equal(t, " -+ +", kinds)
+	assert.Nil(t, diffLines(nil, nil))
+}
+
+func TestDiffLinesIsShortest(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, rng.Intn(12))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(3)))
+		}
+		return lines
+	}
+	for i := 0; i < 500; i++ {
+		a, b := randomLines(), randomLines()
+		oldLines, newLines := []string{}, []string{}
+		edits := 0
+		for _, op := range diffLines(a, b) {
+			if op.kind != '+' {
+				oldLines = append(oldLines, op.line)
+			}
+			if op.kind != '-' {
+				newLines = append(newLines, op.line)
+			}
+			if op.kind != ' ' {
+				edits++
+			}
+		}
+		assert.Equal(t, a, oldLines, "%q -> %q", a, b)
+		assert.Equal(t, b, newLines, "%q -> %q", a, b)
+
+		// The shortest script keeps a longest common subsequence
+		lcs := make([][]int, len(a)+1)
+		for x := range lcs {
+			lcs[x] = make([]int, len(b)+1)
+		}
+		for x := len(a) - 1; x >= 0; x-- {
+			for y := len(b) - 1; y >= 0; y-- {
+				if a[x] == b[y] {
+					lcs[x][y] = lcs[x+1][y+1] + 1
+				} else {
+					lcs[x][y] = max(lcs[x+1][y], lcs[x][y+1])
+				}
+			}
+		}
+		assert.Equal(t, len(a)+len(b)-2*lcs[0][0], edits, "%q -> %q", a, b)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Run("equal texts", func(t *testing.T) {
+		assert.Equal(t, "", unifiedDiff("a", "b", "same\n", "same\n"))
+	})
+
+	t.Run("single change", func(t *testing.T) {
+		diff := unifiedDiff("old", "new", "1\n2\n3\n4\n5\n", "1\n2\nthree\n4\n5\n")
+		assert.Equal(t, "--- old\n+++ new\n@@ -1,5 +1,5 @@\n 1\n 2\n-3\n+three\n 4\n 5\n", diff)
+	})
+
+	t.Run("distant changes get separate hunks", func(t *testing.T) {
+		old := "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n"
+		diff := unifiedDiff("old", "new", old, "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n")
+		assert.Contains(t, diff, "@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n")
+		assert.Contains(t, diff, "@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n")
+	})
+}
+
+func TestMergeLines(t *testing.T) {
+	base := "one\ntwo\nthree\nfour\n"
+
+	t.Run("non-overlapping edits", func(t *testing.T) {
+		merged, conflict := mergeLines(base, "ONE\ntwo\nthree\nfour\n", "one\ntwo\nthree\nFOUR\n", "local", "remote")
+		assert.False(t, conflict)
+		assert.Equal(t, "ONE\ntwo\nthree\nFOUR\n", merged)
+	})
+
+	t.Run("same edit on both sides", func(t *testing.T) {
+		merged, conflict := mergeLines(base, "one\n2\nthree\nfour\n", "one\n2\nthree\nfour\n", "local", "remote")
+		assert.False(t, conflict)
+		assert.Equal(t, "one\n2\nthree\nfour\n", merged)
+	})
+
+	t.Run("conflicting edits", func(t *testing.T) {
+		merged, conflict := mergeLines(base, "one\nmine\nthree\nfour\n", "one\ntheirs\nthree\nfour\n", "local", "remote")
+		assert.True(t, conflict)
+		assert.Equal(t, "one\n<<<<<<< local\nmine\n=======\ntheirs\n>>>>>>> remote\nthree\nfour\n", merged)
+	})
+
+	t.Run("insertions and deletions", func(t *testing.T) {
+		merged, conflict := mergeLines(base, "zero\none\ntwo\nthree\nfour\n", "one\ntwo\nfour\n", "local", "remote")
+		assert.False(t, conflict)
+		assert.Equal(t, "zero\none\ntwo\nfour\n", merged)
+	})
+}
@@ -0,0 +1,371 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/spf13/cobra"
+)
+
+var (
+	editFormat   string
+	editSanitize bool
+)
+
+// runEditorFunc opens a file in the user's editor. It can be overridden for testing.
+var runEditorFunc = runEditor
+
+// editorCommand returns the user's preferred editor from $VISUAL or $EDITOR.
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// runEditor opens path in the user's editor and waits for it to exit.
+func runEditor(path string) error {
+	args := editorCommand()
+	c := exec.Command(args[0], append(args[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", args[0], err)
+	}
+	return nil
+}
+
+// markdownElements are the ENML elements the Markdown edit file keeps, with
+// the attributes it keeps on each.
+var markdownElements = map[string][]string{
+	"en-note": nil, "div": nil, "p": nil, "span": nil, "br": nil, "hr": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"blockquote": nil, "pre": nil, "ul": nil, "ol": {"start"}, "li": nil,
+	"table": nil, "thead": nil, "tbody": nil, "tfoot": nil, "tr": nil, "td": nil, "th": nil,
+	"b": nil, "strong": nil, "i": nil, "em": nil, "s": nil, "strike": nil, "del": nil,
+	"code": nil, "tt": nil, "a": {"href"}, "img": {"src", "alt"},
+	"en-todo": {"checked"}, "en-media": {"hash", "type"},
+}
+
+// markdownLoss returns what of the note content would be lost by editing
+// it as Markdown, or "" when it survives the round trip. Encrypted text
+// would be saved back as its placeholder, and inline styles and markup
+// Markdown has no syntax for would be dropped.
+func markdownLoss(content string) (string, error) {
+	dec := xml.NewDecoder(strings.NewReader(content))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	tables := 0
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid ENML: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			if strings.EqualFold(t.Name.Local, "table") {
+				tables--
+			}
+		case xml.StartElement:
+			tag := strings.ToLower(t.Name.Local)
+			if tag == "en-crypt" {
+				return "encrypted content", nil
+			}
+			kept, ok := markdownElements[tag]
+			if !ok {
+				return fmt.Sprintf("<%s> elements", tag), nil
+			}
+			if tag == "table" {
+				if tables++; tables > 1 {
+					return "nested tables", nil
+				}
+			}
+			for _, a := range t.Attr {
+				name := strings.ToLower(a.Name.Local)
+				if name == "style" && tag == "div" && isCodeBlock(t.Attr) {
+					continue
+				}
+				if !containsFold(kept, name) {
+					if name == "style" {
+						return "inline styles", nil
+					}
+					return fmt.Sprintf("%s attributes on <%s>", name, tag), nil
+				}
+			}
+		}
+	}
+}
+
+// containsFold reports whether list holds s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderEditable returns the note content in the form written to the edit file.
+func renderEditable(note *edam.Note, format string) (string, error) {
+	if format == "enml" {
+		return note.GetContent(), nil
+	}
+	return renderNoteBody(note, "markdown")
+}
+
+// parseEditable converts the edited file back into ENML. Markdown links to the
+// note's existing attachments are kept as attachments; new local images are
+// returned as resources to add.
+func parseEditable(text, format string, resources []*edam.Resource) (string, []*edam.Resource, error) {
+	if format == "enml" {
+		return strings.TrimSpace(text), nil, nil
+	}
+	conv := newMarkdownConverter(".")
+	conv.useResources(resources)
+	body := conv.convert(text)
+	if conv.err != nil {
+		return "", nil, conv.err
+	}
+	return wrapHTMLInENML(body), conv.resources, nil
+}
+
+// hasConflictMarkers reports whether text still contains merge conflict markers.
+func hasConflictMarkers(text string) bool {
+	for _, line := range splitLines(text) {
+		if strings.HasPrefix(line, "<<<<<<< ") || line == "=======" || strings.HasPrefix(line, ">>>>>>> ") {
+			return true
+		}
+	}
+	return false
+}
+
+// askConflict asks how to resolve a conflicting edit until it gets an
+// answer, and returns "overwrite", "merge" or "abort". The end of the input
+// counts as abort.
+func askConflict(out io.Writer, in *bufio.Reader) string {
+	for {
+		fmt.Fprint(out, "[o]verwrite, [m]erge or [a]bort? ")
+		line, err := in.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "o", "overwrite":
+			return "overwrite"
+		case "m", "merge":
+			return "merge"
+		case "a", "abort":
+			return "abort"
+		}
+		if err != nil {
+			fmt.Fprintln(out)
+			return "abort"
+		}
+	}
+}
+
+// editCmd opens a note in $EDITOR and saves the result back to Evernote.
+var editCmd = &cobra.Command{
+	Use:   "edit [guid]",
+	Short: "Edit a note in your editor",
+	Long: `Open a note in $VISUAL or $EDITOR and save the changes back to Evernote.
+
+The note is converted to Markdown by default, or to raw ENML with
+--format enml. Links to existing attachments are kept when the Markdown is
+converted back. Notes with content Markdown cannot keep, such as encrypted
+text, inline styles or underlining, are opened as ENML instead.
+
+If the note was changed in Evernote while you were editing, the differences
+are shown and you can overwrite the remote version, merge both sets of
+changes, or abort. Merge conflicts are marked in the file and the editor is
+opened again so you can resolve them.
+
+Examples:
+  evernote-cli edit <guid>
+  evernote-cli edit <guid> --format enml
+  EDITOR="code --wait" evernote-cli edit <guid>`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if editFormat != "markdown" && editFormat != "enml" {
+			return fmt.Errorf("invalid --format %q (use markdown or enml)", editFormat)
+		}
+
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		guid := edam.GUID(args[0])
+		note, err := ns.GetNote(context.Background(), token, guid, true, false, false, false)
+		if err != nil {
+			return fmt.Errorf("failed to get note: %w", formatAPIError(err))
+		}
+
+		out := cmd.OutOrStdout()
+		format := editFormat
+		if format == "markdown" {
+			loss, err := markdownLoss(note.GetContent())
+			if err != nil {
+				return err
+			}
+			if loss != "" {
+				if cmd.Flags().Changed("format") {
+					return fmt.Errorf("the note has %s, which Markdown cannot keep; edit it with --format enml", loss)
+				}
+				fmt.Fprintf(out, "The note has %s, which Markdown cannot keep; editing it as ENML.\n", loss)
+				format = "enml"
+			}
+		}
+
+		base, err := renderEditable(note, format)
+		if err != nil {
+			return err
+		}
+
+		ext := ".md"
+		if format == "enml" {
+			ext = ".enml"
+		}
+		file, err := os.CreateTemp("", "evernote-"+string(guid)+"-*"+ext)
+		if err != nil {
+			return fmt.Errorf("failed to create edit file: %w", err)
+		}
+		path := file.Name()
+		_, err = file.WriteString(base)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+			return fmt.Errorf("failed to write edit file: %w", err)
+		}
+
+		reader := bufio.NewReader(cmd.InOrStdin())
+		var edited string
+		for {
+			if err := runEditorFunc(path); err != nil {
+				return fmt.Errorf("%w (your changes are in %s)", err, path)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read edit file: %w", err)
+			}
+			edited = string(data)
+			if edited == base {
+				os.Remove(path)
+				fmt.Fprintln(out, "No changes.")
+				return nil
+			}
+			if hasConflictMarkers(edited) {
+				return fmt.Errorf("unresolved merge conflicts in %s", path)
+			}
+
+			// Make sure nobody changed the note while it was being edited
+			current, err := ns.GetNote(context.Background(), token, guid, true, false, false, false)
+			if err != nil {
+				return fmt.Errorf("failed to get note: %w (your changes are in %s)", formatAPIError(err), path)
+			}
+			if current.GetUpdateSequenceNum() == note.GetUpdateSequenceNum() {
+				break
+			}
+			if format == "markdown" {
+				loss, err := markdownLoss(current.GetContent())
+				if err != nil {
+					return err
+				}
+				if loss != "" {
+					return fmt.Errorf("the note was changed in Evernote and now has %s, which Markdown cannot keep (your changes are in %s)", loss, path)
+				}
+			}
+			remote, err := renderEditable(current, format)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(out, "The note was changed in Evernote while you were editing it:")
+			fmt.Fprint(out, unifiedDiff("evernote", "local", remote, edited))
+			answer := askConflict(out, reader)
+			if answer == "overwrite" {
+				note = current
+				break
+			}
+			if answer == "abort" {
+				return fmt.Errorf("edit aborted, your changes are in %s", path)
+			}
+
+			merged, conflict := mergeLines(base, edited, remote, "local", "evernote")
+			note, base = current, remote
+			if !conflict {
+				edited = merged
+				break
+			}
+			if err := os.WriteFile(path, []byte(merged), 0600); err != nil {
+				return fmt.Errorf("failed to write edit file: %w", err)
+			}
+			fmt.Fprintln(out, "Merge conflicts were marked in the file; opening the editor to resolve them.")
+		}
+
+		content, images, err := parseEditable(edited, format, note.GetResources())
+		if err != nil {
+			return err
+		}
+		resources := append(note.GetResources(), images...)
+		content, err = prepareENML(content, resources, editSanitize)
+		if err != nil {
+			return fmt.Errorf("%w\nyour changes are in %s", err, path)
+		}
+
+		title := note.GetTitle()
+		update := &edam.Note{
+			GUID:    &guid,
+			Title:   &title,
+			Content: &content,
+		}
+		if len(images) > 0 {
+			update.Resources = resources
+		}
+
+		updated, err := ns.UpdateNote(context.Background(), token, update)
+		if err != nil {
+			return fmt.Errorf("failed to update note: %w (your changes are in %s)", formatAPIError(err), path)
+		}
+		os.Remove(path)
+
+		if jsonFlag {
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			return enc.Encode(updated)
+		}
+
+		fmt.Fprintf(out, "Note updated: %s\n", updated.GetTitle())
+		fmt.Fprintf(out, "GUID: %s\n", updated.GetGUID())
+		return nil
+	},
+}
+
+func init() {
+	editCmd.Flags().StringVar(&editFormat, "format", "markdown", "format of the edit file: markdown or enml")
+	editCmd.Flags().BoolVar(&editSanitize, "sanitize", false, "strip markup that is not valid ENML instead of failing")
+	rootCmd.AddCommand(editCmd)
+}
@@ -0,0 +1,297 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// editTestNote builds a note with the given HTML body and update sequence number.
+func editTestNote(body string, usn int32) *edam.Note {
+	guid := edam.GUID("note-123")
+	title := "My Note"
+	content := wrapHTMLInENML(body)
+	return &edam.Note{GUID: &guid, Title: &title, Content: &content, UpdateSequenceNum: &usn}
+}
+
+// setEditor replaces the editor with fn, which receives the file contents and
+// returns the new contents. It returns a cleanup function.
+func setEditor(t *testing.T, fn func(string) string) func() {
+	original := runEditorFunc
+	runEditorFunc = func(path string) error {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return os.WriteFile(path, []byte(fn(string(data))), 0600)
+	}
+	return func() { runEditorFunc = original }
+}
+
+func TestEditCommand(t *testing.T) {
+	t.Run("saves edited markdown", func(t *testing.T) {
+		mock := &mockNoteStore{gotNote: editTestNote(`<div>Hello <b>world</b></div>`, 5)}
+		defer setMockNoteStore(mock)()
+		defer setEditor(t, func(s string) string {
+			assert.Equal(t, "Hello **world**", s)
+			return s + "\n\n- [ ] new task\n"
+		})()
+
+		var buf bytes.Buffer
+		editCmd.SetOut(&buf)
+		err := editCmd.RunE(editCmd, []string{"note-123"})
+		require.NoError(t, err)
+
+		assert.Contains(t, buf.String(), "Note updated: My Note")
+		require.NotNil(t, mock.savedNote)
+		assert.Equal(t, "My Note", mock.savedNote.GetTitle())
+		assert.Contains(t, mock.savedNote.GetContent(), "<p>Hello <strong>world</strong></p>")
+		assert.Contains(t, mock.savedNote.GetContent(), `<en-todo checked="false"/>new task`)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		mock := &mockNoteStore{gotNote: editTestNote(`<div>same</div>`, 5)}
+		defer setMockNoteStore(mock)()
+		defer setEditor(t, func(s string) string { return s })()
+
+		var buf bytes.Buffer
+		editCmd.SetOut(&buf)
+		err := editCmd.RunE(editCmd, []string{"note-123"})
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "No changes.")
+		assert.Nil(t, mock.savedNote)
+	})
+
+	t.Run("keeps existing attachments", func(t *testing.T) {
+		data := []byte("pdf data")
+		hash := md5.Sum(data)
+		mime := "application/pdf"
+		name := "report.pdf"
+		note := editTestNote("<div>See</div>"+buildMediaTag(hash[:], mime), 5)
+		note.Resources = []*edam.Resource{{
+			Mime:       &mime,
+			Data:       &edam.Data{BodyHash: hash[:]},
+			Attributes: &edam.ResourceAttributes{FileName: &name},
+		}}
+		mock := &mockNoteStore{gotNote: note}
+		defer setMockNoteStore(mock)()
+		defer setEditor(t, func(s string) string { return strings.Replace(s, "See", "See attached", 1) })()
+
+		var buf bytes.Buffer
+		editCmd.SetOut(&buf)
+		require.NoError(t, editCmd.RunE(editCmd, []string{"note-123"}))
+		require.NotNil(t, mock.savedNote)
+		assert.Contains(t, mock.savedNote.GetContent(), buildMediaTag(hash[:], mime))
+		assert.Nil(t, mock.savedNote.Resources)
+	})
+
+	t.Run("conflict abort keeps the file", func(t *testing.T) {
+		mock := &mockNoteStore{gotNotes: []*edam.Note{
+			editTestNote(`<div>one</div>`, 5),
+			editTestNote(`<div>remote</div>`, 6),
+		}}
+		defer setMockNoteStore(mock)()
+		defer setEditor(t, func(s string) string { return "local\n" })()
+
+		var buf bytes.Buffer
+		editCmd.SetOut(&buf)
+		editCmd.SetIn(strings.NewReader("a\n"))
+		defer editCmd.SetIn(nil)
+		err := editCmd.RunE(editCmd, []string{"note-123"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "edit aborted")
+
+		out := buf.String()
+		assert.Contains(t, out, "changed in Evernote")
+		assert.Contains(t, out, "-remote\n+local\n")
+		assert.Nil(t, mock.savedNote)
+
+		path := err.Error()[strings.LastIndex(err.Error(), " ")+1:]
+		assert.FileExists(t, path)
+		os.Remove(path)
+	})
+
+	t.Run("conflict prompt", func(t *testing.T) {
+		for _, tt := range []struct {
+			input string
+			saved bool
+		}{
+			{"x\n\noverwrite\n", true},
+			{"", false},
+			{"maybe\n", false},
+		} {
+			mock := &mockNoteStore{gotNotes: []*edam.Note{
+				editTestNote(`<div>one</div>`, 5),
+				editTestNote(`<div>remote</div>`, 6),
+			}}
+			restore := setMockNoteStore(mock)
+			restoreEditor := setEditor(t, func(s string) string { return "local\n" })
+
+			var buf bytes.Buffer
+			editCmd.SetOut(&buf)
+			editCmd.SetIn(strings.NewReader(tt.input))
+			err := editCmd.RunE(editCmd, []string{"note-123"})
+			editCmd.SetIn(nil)
+			restoreEditor()
+			restore()
+
+			if tt.saved {
+				require.NoError(t, err, tt.input)
+				assert.Equal(t, 3, strings.Count(buf.String(), "[o]verwrite, [m]erge or [a]bort? "), "asks again after an unknown answer")
+				assert.NotNil(t, mock.savedNote)
+				continue
+			}
+			require.Error(t, err, "the end of the input aborts")
+			assert.Contains(t, err.Error(), "edit aborted")
+			assert.Nil(t, mock.savedNote)
+			os.Remove(err.Error()[strings.LastIndex(err.Error(), " ")+1:])
+		}
+	})
+
+	t.Run("conflict overwrite", func(t *testing.T) {
+		mock := &mockNoteStore{gotNotes: []*edam.Note{
+			editTestNote(`<div>one</div>`, 5),
+			editTestNote(`<div>remote</div>`, 6),
+		}}
+		defer setMockNoteStore(mock)()
+		defer setEditor(t, func(s string) string { return "local\n" })()
+
+		var buf bytes.Buffer
+		editCmd.SetOut(&buf)
+		editCmd.SetIn(strings.NewReader("o\n"))
+		defer editCmd.SetIn(nil)
+		require.NoError(t, editCmd.RunE(editCmd, []string{"note-123"}))
+		require.NotNil(t, mock.savedNote)
+		assert.Equal(t, wrapHTMLInENML("<p>local</p>"), mock.savedNote.GetContent())
+	})
+
+	t.Run("conflict merge", func(t *testing.T) {
+		mock := &mockNoteStore{gotNotes: []*edam.Note{
+			editTestNote(`<div>one</div><div>two</div><div>three</div>`, 5),
+			editTestNote(`<div>one</div><div>two</div><div>THREE</div>`, 6),
+		}}
+		defer setMockNoteStore(mock)()
+		defer setEditor(t, func(s string) string { return strings.Replace(s, "one", "ONE", 1) })()
+
+		var buf bytes.Buffer
+		editCmd.SetOut(&buf)
+		editCmd.SetIn(strings.NewReader("m\n"))
+		defer editCmd.SetIn(nil)
+		require.NoError(t, editCmd.RunE(editCmd, []string{"note-123"}))
+		require.NotNil(t, mock.savedNote)
+		assert.Equal(t, wrapHTMLInENML("<p>ONE</p><p>two</p><p>THREE</p>"), mock.savedNote.GetContent())
+	})
+
+	t.Run("raw enml format", func(t *testing.T) {
+		mock := &mockNoteStore{gotNote: editTestNote(`<div>x</div>`, 5)}
+		defer setMockNoteStore(mock)()
+		defer setEditor(t, func(s string) string { return strings.Replace(s, "<div>x</div>", `<div class="c">y</div>`, 1) })()
+
+		editFormat = "enml"
+		defer func() { editFormat = "markdown" }()
+
+		var buf bytes.Buffer
+		editCmd.SetOut(&buf)
+		err := editCmd.RunE(editCmd, []string{"note-123"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--sanitize")
+		assert.Nil(t, mock.savedNote)
+	})
+
+	t.Run("encrypted content is edited as enml", func(t *testing.T) {
+		crypt := `<en-crypt cipher="AES" length="128">c2VjcmV0</en-crypt>`
+		mock := &mockNoteStore{gotNote: editTestNote(`<div>Secret:</div><div>`+crypt+`</div>`, 5)}
+		defer setMockNoteStore(mock)()
+		defer setEditor(t, func(s string) string {
+			assert.Contains(t, s, crypt, "the edit file holds the raw ENML")
+			return strings.Replace(s, "Secret:", "Password:", 1)
+		})()
+
+		var buf bytes.Buffer
+		editCmd.SetOut(&buf)
+		require.NoError(t, editCmd.RunE(editCmd, []string{"note-123"}))
+		assert.Contains(t, buf.String(), "The note has encrypted content, which Markdown cannot keep; editing it as ENML.")
+		require.NotNil(t, mock.savedNote)
+		assert.Contains(t, mock.savedNote.GetContent(), "<div>Password:</div>")
+		assert.Contains(t, mock.savedNote.GetContent(), crypt)
+	})
+
+	t.Run("markdown is refused when it would lose content", func(t *testing.T) {
+		mock := &mockNoteStore{gotNote: editTestNote(`<div><en-crypt>c2VjcmV0</en-crypt></div>`, 5)}
+		defer setMockNoteStore(mock)()
+		defer setEditor(t, func(s string) string {
+			t.Fatal("the editor should not be opened")
+			return s
+		})()
+
+		require.NoError(t, editCmd.Flags().Set("format", "markdown"))
+		defer func() { editCmd.Flags().Lookup("format").Changed = false }()
+
+		err := editCmd.RunE(editCmd, []string{"note-123"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--format enml")
+		assert.Nil(t, mock.savedNote)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		editFormat = "html"
+		defer func() { editFormat = "markdown" }()
+
+		err := editCmd.RunE(editCmd, []string{"note-123"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid --format")
+	})
+}
+
+func TestMarkdownLoss(t *testing.T) {
+	tests := []struct {
+		name, body, loss string
+	}{
+		{"plain markup", `<div>Hi <b>you</b>, see <a href="https://example.com">this</a></div><ul><li>x</li></ul>`, ""},
+		{"code block", `<div style="-en-codeblock: true; box-sizing: border-box"><div>x := 1</div></div>`, ""},
+		{"encrypted", `<div><en-crypt>c2VjcmV0</en-crypt></div>`, "encrypted content"},
+		{"inline style", `<div><span style="color: red">red</span></div>`, "inline styles"},
+		{"underline", `<div><u>under</u></div>`, "<u> elements"},
+		{"sized image", `<en-media hash="00" type="image/png" width="100"/>`, "width attributes on <en-media>"},
+		{"nested table", `<table><tr><td><table><tr><td>x</td></tr></table></td></tr></table>`, "nested tables"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loss, err := markdownLoss(wrapHTMLInENML(tt.body))
+			require.NoError(t, err)
+			assert.Equal(t, tt.loss, loss)
+		})
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "code --wait")
+	assert.Equal(t, []string{"code", "--wait"}, editorCommand())
+
+	t.Setenv("VISUAL", "nano")
+	assert.Equal(t, []string{"nano"}, editorCommand())
+}
+
+func TestEditCmdConfiguration(t *testing.T) {
+	assert.Equal(t, "edit [guid]", editCmd.Use)
+	assert.Equal(t, "Edit a note in your editor", editCmd.Short)
+	assert.NotNil(t, editCmd.RunE)
+}
+
+func TestEditCmdRegistration(t *testing.T) {
+	found := false
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "edit" {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "edit command should be registered")
+}
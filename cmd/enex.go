@@ -0,0 +1,454 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+)
+
+// enexTimeLayout is the timestamp format used in ENEX files.
+const enexTimeLayout = "20060102T150405Z"
+
+// enexTime formats an Evernote timestamp for ENEX.
+func enexTime(ts edam.Timestamp) string {
+	return time.UnixMilli(int64(ts)).UTC().Format(enexTimeLayout)
+}
+
+// enexWriter streams notes to an ENEX file one at a time, so an export never
+// holds more than one note in memory.
+type enexWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// newENEXWriter writes the ENEX header to w.
+func newENEXWriter(w io.Writer, exported time.Time) *enexWriter {
+	e := &enexWriter{w: bufio.NewWriter(w)}
+	e.raw(xml.Header)
+	e.raw(`<!DOCTYPE en-export SYSTEM "http://xml.evernote.com/pub/evernote-export4.dtd">` + "\n")
+	e.raw(`<en-export export-date="` + exported.UTC().Format(enexTimeLayout) + `" application="evernote-cli" version="` + Version + `">` + "\n")
+	return e
+}
+
+// raw writes s unescaped, remembering the first error.
+func (e *enexWriter) raw(s string) {
+	if e.err == nil {
+		_, e.err = e.w.WriteString(s)
+	}
+}
+
+// text writes an element with escaped text content.
+func (e *enexWriter) text(indent, name, value string) {
+	e.raw(indent + "<" + name + ">")
+	if e.err == nil {
+		e.err = xml.EscapeText(e.w, []byte(value))
+	}
+	e.raw("</" + name + ">\n")
+}
+
+// optional writes an element only when value is set.
+func (e *enexWriter) optional(indent, name string, value *string) {
+	if value != nil {
+		e.text(indent, name, *value)
+	}
+}
+
+// cdata writes an element whose content is wrapped in CDATA.
+func (e *enexWriter) cdata(indent, name, value string) {
+	e.raw(indent + "<" + name + "><![CDATA[")
+	e.raw(strings.ReplaceAll(value, "]]>", "]]]]><![CDATA[>"))
+	e.raw("]]></" + name + ">\n")
+}
+
+// optionalTime writes a timestamp element when ts is set.
+func (e *enexWriter) optionalTime(indent, name string, ts *edam.Timestamp) {
+	if ts != nil {
+		e.text(indent, name, enexTime(*ts))
+	}
+}
+
+// optionalFloat writes a number element when v is set.
+func (e *enexWriter) optionalFloat(indent, name string, v *float64) {
+	if v != nil {
+		e.text(indent, name, strconv.FormatFloat(*v, 'f', -1, 64))
+	}
+}
+
+// applicationData writes application-data elements for a LazyMap.
+func (e *enexWriter) applicationData(indent string, m *edam.LazyMap) {
+	if m == nil {
+		return
+	}
+	keys := make([]string, 0, len(m.GetFullMap()))
+	for k := range m.GetFullMap() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		e.raw(indent + `<application-data key="`)
+		if e.err == nil {
+			e.err = xml.EscapeText(e.w, []byte(k))
+		}
+		e.raw(`">`)
+		if e.err == nil {
+			e.err = xml.EscapeText(e.w, []byte(m.GetFullMap()[k]))
+		}
+		e.raw("</application-data>\n")
+	}
+}
+
+// writeNote writes one note with its tag names and resources, in the
+// element order of the ENEX DTD.
+func (e *enexWriter) writeNote(note *edam.Note, tagNames []string) error {
+	e.raw("  <note>\n")
+	e.text("    ", "title", note.GetTitle())
+	e.cdata("    ", "content", note.GetContent())
+	e.optionalTime("    ", "created", note.Created)
+	e.optionalTime("    ", "updated", note.Updated)
+	for _, tag := range tagNames {
+		e.text("    ", "tag", tag)
+	}
+
+	if a := note.GetAttributes(); a != nil {
+		const in = "      "
+		e.raw("    <note-attributes>\n")
+		e.optionalTime(in, "subject-date", a.SubjectDate)
+		e.optionalFloat(in, "latitude", a.Latitude)
+		e.optionalFloat(in, "longitude", a.Longitude)
+		e.optionalFloat(in, "altitude", a.Altitude)
+		e.optional(in, "author", a.Author)
+		e.optional(in, "source", a.Source)
+		e.optional(in, "source-url", a.SourceURL)
+		e.optional(in, "source-application", a.SourceApplication)
+		if a.ReminderOrder != nil {
+			e.text(in, "reminder-order", strconv.FormatInt(*a.ReminderOrder, 10))
+		}
+		e.optionalTime(in, "reminder-time", a.ReminderTime)
+		e.optionalTime(in, "reminder-done-time", a.ReminderDoneTime)
+		e.optional(in, "place-name", a.PlaceName)
+		e.optional(in, "content-class", a.ContentClass)
+		e.applicationData(in, a.ApplicationData)
+		e.raw("    </note-attributes>\n")
+	}
+
+	for _, res := range note.GetResources() {
+		e.writeResource(res)
+	}
+	e.raw("  </note>\n")
+	return e.err
+}
+
+// writeResource writes a resource with its body base64 encoded in lines of
+// 76 characters.
+func (e *enexWriter) writeResource(res *edam.Resource) {
+	e.raw("    <resource>\n")
+	e.raw(`      <data encoding="base64">` + "\n")
+	if e.err == nil && res.GetData() != nil {
+		enc := base64.NewEncoder(base64.StdEncoding, &lineWriter{w: e.w, width: 76})
+		if _, err := enc.Write(res.GetData().GetBody()); err != nil {
+			e.err = err
+		} else {
+			e.err = enc.Close()
+		}
+	}
+	e.raw("\n      </data>\n")
+	e.text("      ", "mime", res.GetMime())
+	if res.Width != nil {
+		e.text("      ", "width", strconv.Itoa(int(*res.Width)))
+	}
+	if res.Height != nil {
+		e.text("      ", "height", strconv.Itoa(int(*res.Height)))
+	}
+	if res.Duration != nil {
+		e.text("      ", "duration", strconv.Itoa(int(*res.Duration)))
+	}
+	if res.GetRecognition() != nil && len(res.GetRecognition().GetBody()) > 0 {
+		e.cdata("      ", "recognition", string(res.GetRecognition().GetBody()))
+	}
+
+	if a := res.GetAttributes(); a != nil {
+		const in = "        "
+		e.raw("      <resource-attributes>\n")
+		e.optional(in, "source-url", a.SourceURL)
+		e.optionalTime(in, "timestamp", a.Timestamp)
+		e.optionalFloat(in, "latitude", a.Latitude)
+		e.optionalFloat(in, "longitude", a.Longitude)
+		e.optionalFloat(in, "altitude", a.Altitude)
+		e.optional(in, "camera-make", a.CameraMake)
+		e.optional(in, "camera-model", a.CameraModel)
+		e.optional(in, "reco-type", a.RecoType)
+		e.optional(in, "file-name", a.FileName)
+		if a.Attachment != nil {
+			e.text(in, "attachment", strconv.FormatBool(*a.Attachment))
+		}
+		e.applicationData(in, a.ApplicationData)
+		e.raw("      </resource-attributes>\n")
+	}
+	e.raw("    </resource>\n")
+}
+
+// close writes the closing tag and flushes the output.
+func (e *enexWriter) close() error {
+	e.raw("</en-export>\n")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// lineWriter breaks its output into lines of a fixed width.
+type lineWriter struct {
+	w     io.Writer
+	width int
+	col   int
+}
+
+// Write writes p, inserting a newline every width bytes.
+func (l *lineWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if l.col == l.width {
+			if _, err := l.w.Write([]byte("\n")); err != nil {
+				return written, err
+			}
+			l.col = 0
+		}
+		n := l.width - l.col
+		if n > len(p) {
+			n = len(p)
+		}
+		if _, err := l.w.Write(p[:n]); err != nil {
+			return written, err
+		}
+		l.col += n
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// enexReader reads notes from an ENEX file one at a time, so an import never
+// holds more than one note in memory.
+type enexReader struct {
+	d       *xml.Decoder
+	started bool
+}
+
+// newENEXReader returns a reader for the ENEX file in r.
+func newENEXReader(r io.Reader) *enexReader {
+	d := xml.NewDecoder(bufio.NewReader(r))
+	d.Entity = xml.HTMLEntity
+	return &enexReader{d: d}
+}
+
+// enexApplicationData is an application-data element.
+type enexApplicationData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// enexNote mirrors a <note> element of the ENEX DTD.
+type enexNote struct {
+	Title      string   `xml:"title"`
+	Content    string   `xml:"content"`
+	Created    *string  `xml:"created"`
+	Updated    *string  `xml:"updated"`
+	Tags       []string `xml:"tag"`
+	Attributes *struct {
+		SubjectDate       *string               `xml:"subject-date"`
+		Latitude          *float64              `xml:"latitude"`
+		Longitude         *float64              `xml:"longitude"`
+		Altitude          *float64              `xml:"altitude"`
+		Author            *string               `xml:"author"`
+		Source            *string               `xml:"source"`
+		SourceURL         *string               `xml:"source-url"`
+		SourceApplication *string               `xml:"source-application"`
+		ReminderOrder     *int64                `xml:"reminder-order"`
+		ReminderTime      *string               `xml:"reminder-time"`
+		ReminderDoneTime  *string               `xml:"reminder-done-time"`
+		PlaceName         *string               `xml:"place-name"`
+		ContentClass      *string               `xml:"content-class"`
+		ApplicationData   []enexApplicationData `xml:"application-data"`
+	} `xml:"note-attributes"`
+	Resources []enexResource `xml:"resource"`
+}
+
+// enexResource mirrors a <resource> element of the ENEX DTD. Recognition
+// data is left out because Evernote generates it again on upload.
+type enexResource struct {
+	Data struct {
+		Encoding string `xml:"encoding,attr"`
+		Body     string `xml:",chardata"`
+	} `xml:"data"`
+	Mime       string `xml:"mime"`
+	Width      *int16 `xml:"width"`
+	Height     *int16 `xml:"height"`
+	Duration   *int16 `xml:"duration"`
+	Attributes *struct {
+		SourceURL       *string               `xml:"source-url"`
+		Timestamp       *string               `xml:"timestamp"`
+		Latitude        *float64              `xml:"latitude"`
+		Longitude       *float64              `xml:"longitude"`
+		Altitude        *float64              `xml:"altitude"`
+		CameraMake      *string               `xml:"camera-make"`
+		CameraModel     *string               `xml:"camera-model"`
+		RecoType        *string               `xml:"reco-type"`
+		FileName        *string               `xml:"file-name"`
+		Attachment      *bool                 `xml:"attachment"`
+		ApplicationData []enexApplicationData `xml:"application-data"`
+	} `xml:"resource-attributes"`
+}
+
+// next returns the next note, with its tag names in TagNames, or io.EOF
+// after the last one.
+func (r *enexReader) next() (*edam.Note, error) {
+	for {
+		tok, err := r.d.Token()
+		if err == io.EOF {
+			if !r.started {
+				return nil, fmt.Errorf("not an ENEX file: no <en-export> element")
+			}
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid ENEX: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !r.started {
+			if start.Name.Local != "en-export" {
+				return nil, fmt.Errorf("not an ENEX file: root element is <%s>", start.Name.Local)
+			}
+			r.started = true
+			continue
+		}
+		if start.Name.Local != "note" {
+			if err := r.d.Skip(); err != nil {
+				return nil, fmt.Errorf("invalid ENEX: %w", err)
+			}
+			continue
+		}
+
+		var n enexNote
+		if err := r.d.DecodeElement(&n, &start); err != nil {
+			return nil, fmt.Errorf("invalid ENEX: %w", err)
+		}
+		return n.toNote()
+	}
+}
+
+// parseENEXTime parses an optional ENEX timestamp.
+func parseENEXTime(value *string) (*edam.Timestamp, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(enexTimeLayout, strings.TrimSpace(*value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp %q", *value)
+	}
+	ts := edam.Timestamp(t.UnixMilli())
+	return &ts, nil
+}
+
+// parseApplicationData converts application-data elements to a LazyMap.
+func parseApplicationData(data []enexApplicationData) *edam.LazyMap {
+	if len(data) == 0 {
+		return nil
+	}
+	m := &edam.LazyMap{FullMap: map[string]string{}}
+	for _, d := range data {
+		m.FullMap[d.Key] = d.Value
+	}
+	return m
+}
+
+// toNote converts a decoded note to an Evernote note ready to create. Each
+// resource's MD5 hash is computed from its decoded body, the same way
+// buildResource does, so <en-media> references in the content still match.
+func (n *enexNote) toNote() (*edam.Note, error) {
+	title, content := strings.TrimSpace(n.Title), n.Content
+	note := &edam.Note{Title: &title, Content: &content, TagNames: n.Tags}
+
+	var err error
+	parse := func(value *string) *edam.Timestamp {
+		ts, tsErr := parseENEXTime(value)
+		if tsErr != nil && err == nil {
+			err = fmt.Errorf("note %q: %w", title, tsErr)
+		}
+		return ts
+	}
+	note.Created = parse(n.Created)
+	note.Updated = parse(n.Updated)
+
+	if a := n.Attributes; a != nil {
+		note.Attributes = &edam.NoteAttributes{
+			SubjectDate:       parse(a.SubjectDate),
+			Latitude:          a.Latitude,
+			Longitude:         a.Longitude,
+			Altitude:          a.Altitude,
+			Author:            a.Author,
+			Source:            a.Source,
+			SourceURL:         a.SourceURL,
+			SourceApplication: a.SourceApplication,
+			ReminderOrder:     a.ReminderOrder,
+			ReminderTime:      parse(a.ReminderTime),
+			ReminderDoneTime:  parse(a.ReminderDoneTime),
+			PlaceName:         a.PlaceName,
+			ContentClass:      a.ContentClass,
+			ApplicationData:   parseApplicationData(a.ApplicationData),
+		}
+	}
+
+	for i, res := range n.Resources {
+		if res.Data.Encoding != "" && res.Data.Encoding != "base64" {
+			return nil, fmt.Errorf("note %q: resource %d has unsupported encoding %q", title, i+1, res.Data.Encoding)
+		}
+		body, decodeErr := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(res.Data.Body), ""))
+		if decodeErr != nil {
+			return nil, fmt.Errorf("note %q: resource %d has invalid data: %w", title, i+1, decodeErr)
+		}
+		mimeType := strings.TrimSpace(res.Mime)
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		r := &edam.Resource{
+			Data:     newResourceData(body),
+			Mime:     &mimeType,
+			Width:    res.Width,
+			Height:   res.Height,
+			Duration: res.Duration,
+		}
+		if a := res.Attributes; a != nil {
+			r.Attributes = &edam.ResourceAttributes{
+				SourceURL:       a.SourceURL,
+				Timestamp:       parse(a.Timestamp),
+				Latitude:        a.Latitude,
+				Longitude:       a.Longitude,
+				Altitude:        a.Altitude,
+				CameraMake:      a.CameraMake,
+				CameraModel:     a.CameraModel,
+				RecoType:        a.RecoType,
+				FileName:        a.FileName,
+				Attachment:      a.Attachment,
+				ApplicationData: parseApplicationData(a.ApplicationData),
+			}
+		}
+		note.Resources = append(note.Resources, r)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return note, nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// enexTestNote returns a note with attributes and one attachment.
+func enexTestNote() *edam.Note {
+	title, content := "Fish & Chips", wrapHTMLInENML(`<div>Recipe ]]> notes</div><en-media type="image/png" hash="0cc175b9c0f1b6a831c399e269772661"/>`)
+	created, updated := edam.Timestamp(1700000000000), edam.Timestamp(1700003600000)
+	author, sourceURL, lat := "Ana", "https://example.com/fish", 51.5
+	mime, fileName, attachment := "image/png", "photo.png", false
+	return &edam.Note{
+		Title:   &title,
+		Content: &content,
+		Created: &created,
+		Updated: &updated,
+		Attributes: &edam.NoteAttributes{
+			Author:          &author,
+			SourceURL:       &sourceURL,
+			Latitude:        &lat,
+			ApplicationData: &edam.LazyMap{FullMap: map[string]string{"app": "x<y"}},
+		},
+		Resources: []*edam.Resource{{
+			Mime: &mime,
+			Data: &edam.Data{Body: []byte("a")},
+			Attributes: &edam.ResourceAttributes{
+				FileName:   &fileName,
+				Attachment: &attachment,
+			},
+		}},
+	}
+}
+
+func TestENEXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	enex := newENEXWriter(&buf, time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, enex.writeNote(enexTestNote(), []string{"food", "uk"}))
+	require.NoError(t, enex.close())
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE en-export SYSTEM "http://xml.evernote.com/pub/evernote-export4.dtd">
+<en-export export-date="20261016T090000Z" application="evernote-cli" version="` + Version + `">
+  <note>
+    <title>Fish &amp; Chips</title>
+    <content><![CDATA[` + strings.ReplaceAll(enexTestNote().GetContent(), "]]>", "]]]]><![CDATA[>") + `]]></content>
+    <created>20231114T221320Z</created>
+    <updated>20231114T231320Z</updated>
+    <tag>food</tag>
+    <tag>uk</tag>
+    <note-attributes>
+      <latitude>51.5</latitude>
+      <author>Ana</author>
+      <source-url>https://example.com/fish</source-url>
+      <application-data key="app">x&lt;y</application-data>
+    </note-attributes>
+    <resource>
+      <data encoding="base64">
+YQ==
+      </data>
+      <mime>image/png</mime>
+      <resource-attributes>
+        <file-name>photo.png</file-name>
+        <attachment>false</attachment>
+      </resource-attributes>
+    </resource>
+  </note>
+</en-export>
+`
+	assert.Equal(t, want, buf.String())
+
+	// The output is well-formed XML and the content survives the CDATA split
+	dec := xml.NewDecoder(&buf)
+	dec.Strict = false
+	var content string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local == "content" {
+			require.NoError(t, dec.DecodeElement(&content, &start))
+		}
+	}
+	assert.Equal(t, enexTestNote().GetContent(), content)
+}
+
+func TestLineWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &lineWriter{w: &buf, width: 4}
+	_, err := w.Write([]byte("abcdef"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("gh"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("i"))
+	require.NoError(t, err)
+	assert.Equal(t, "abcd\nefgh\ni", buf.String())
+}
+
+func TestENEXReader(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		enex := newENEXWriter(&buf, time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+		require.NoError(t, enex.writeNote(enexTestNote(), []string{"food", "uk"}))
+		second := &edam.Note{Title: strRef("Empty")}
+		require.NoError(t, enex.writeNote(second, nil))
+		require.NoError(t, enex.close())
+
+		r := newENEXReader(&buf)
+		note, err := r.next()
+		require.NoError(t, err)
+		want := enexTestNote()
+		assert.Equal(t, want.GetTitle(), note.GetTitle())
+		assert.Equal(t, want.GetContent(), note.GetContent())
+		assert.Equal(t, want.GetCreated(), note.GetCreated())
+		assert.Equal(t, want.GetUpdated(), note.GetUpdated())
+		assert.Equal(t, []string{"food", "uk"}, note.TagNames)
+		assert.Equal(t, "Ana", note.GetAttributes().GetAuthor())
+		assert.Equal(t, 51.5, note.GetAttributes().GetLatitude())
+		assert.Equal(t, map[string]string{"app": "x<y"}, note.GetAttributes().GetApplicationData().GetFullMap())
+
+		require.Len(t, note.Resources, 1)
+		res := note.Resources[0]
+		assert.Equal(t, []byte("a"), res.GetData().GetBody())
+		assert.Equal(t, int32(1), res.GetData().GetSize())
+		// The hash is recomputed so it matches the <en-media> reference
+		assert.Equal(t, "0cc175b9c0f1b6a831c399e269772661", fmt.Sprintf("%x", res.GetData().GetBodyHash()))
+		assert.Equal(t, "image/png", res.GetMime())
+		assert.Equal(t, "photo.png", res.GetAttributes().GetFileName())
+		assert.False(t, res.GetAttributes().GetAttachment())
+
+		note, err = r.next()
+		require.NoError(t, err)
+		assert.Equal(t, "Empty", note.GetTitle())
+		assert.Nil(t, note.Created)
+		assert.Nil(t, note.Attributes)
+
+		_, err = r.next()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("multi-line base64", func(t *testing.T) {
+		body := bytes.Repeat([]byte("evernote"), 40)
+		var buf bytes.Buffer
+		enex := newENEXWriter(&buf, time.Now())
+		note := &edam.Note{Title: strRef("Big"), Resources: []*edam.Resource{{Data: &edam.Data{Body: body}}}}
+		require.NoError(t, enex.writeNote(note, nil))
+		require.NoError(t, enex.close())
+
+		got, err := newENEXReader(&buf).next()
+		require.NoError(t, err)
+		assert.Equal(t, body, got.Resources[0].GetData().GetBody())
+		assert.Equal(t, "application/octet-stream", got.Resources[0].GetMime())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name, input, want string
+		}{
+			{"not ENEX", `<html></html>`, "not an ENEX file: root element is <html>"},
+			{"empty", ``, "not an ENEX file: no <en-export> element"},
+			{"bad timestamp", `<en-export><note><title>A</title><created>yesterday</created></note></en-export>`, `note "A": invalid timestamp "yesterday"`},
+			{"bad data", `<en-export><note><title>A</title><resource><data encoding="base64">!!</data></resource></note></en-export>`, `note "A": resource 1 has invalid data`},
+			{"bad encoding", `<en-export><note><title>A</title><resource><data encoding="hex">00</data></resource></note></en-export>`, `unsupported encoding "hex"`},
+			{"truncated", `<en-export><note><title>A</title>`, "invalid ENEX"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := newENEXReader(strings.NewReader(tt.input)).next()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.want)
+			})
+		}
+	})
+}
@@ -0,0 +1,363 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+)
+
+// enmlAllowedElements lists the XHTML elements permitted by the ENML2 DTD
+// in addition to the Evernote-specific en-* elements.
+var enmlAllowedElements = map[string]bool{
+	"a": true, "abbr": true, "acronym": true, "address": true, "area": true, "b": true,
+	"bdo": true, "big": true, "blockquote": true, "br": true, "caption": true, "center": true,
+	"cite": true, "code": true, "col": true, "colgroup": true, "dd": true, "del": true,
+	"dfn": true, "div": true, "dl": true, "dt": true, "em": true, "font": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"hr": true, "i": true, "img": true, "ins": true, "kbd": true, "li": true,
+	"map": true, "ol": true, "p": true, "pre": true, "q": true, "s": true,
+	"samp": true, "small": true, "span": true, "strike": true, "strong": true, "sub": true,
+	"sup": true, "table": true, "tbody": true, "td": true, "tfoot": true, "th": true,
+	"thead": true, "title": true, "tr": true, "tt": true, "u": true, "ul": true,
+	"var": true, "xmp": true,
+	"en-note": true, "en-media": true, "en-todo": true, "en-crypt": true,
+}
+
+// enmlDroppedElements are removed together with their content when sanitizing.
+// Any other disallowed element is unwrapped and its content kept.
+var enmlDroppedElements = map[string]bool{
+	"script": true, "style": true, "head": true, "object": true, "embed": true, "applet": true,
+	"iframe": true, "noscript": true, "frame": true, "frameset": true, "noframes": true, "param": true,
+	"meta": true, "link": true, "base": true, "basefont": true, "bgsound": true, "input": true,
+	"select": true, "option": true, "optgroup": true, "textarea": true, "isindex": true, "xml": true,
+}
+
+// enmlVoidElements are written as self-closing tags.
+var enmlVoidElements = map[string]bool{
+	"br": true, "hr": true, "img": true, "area": true, "col": true, "en-media": true, "en-todo": true,
+}
+
+// enmlProhibitedAttrs lists attributes that ENML rejects on every element.
+var enmlProhibitedAttrs = map[string]bool{
+	"id": true, "class": true, "accesskey": true, "data": true, "dynsrc": true, "tabindex": true,
+}
+
+// enmlProblem describes a single ENML rule violation and where it occurred.
+type enmlProblem struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// enmlError is returned when content fails ENML validation.
+type enmlError struct {
+	Problems []enmlProblem
+}
+
+// Error lists every problem with its line and column.
+func (e *enmlError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = fmt.Sprintf("  line %d, column %d: %s", p.Line, p.Column, p.Message)
+	}
+	return "note content is not valid ENML:\n" + strings.Join(lines, "\n")
+}
+
+// resourceHashes returns the set of hex MD5 hashes of the given resources.
+func resourceHashes(resources []*edam.Resource) map[string]bool {
+	hashes := make(map[string]bool)
+	for _, res := range resources {
+		if res.GetData() != nil && len(res.GetData().GetBodyHash()) > 0 {
+			hashes[hex.EncodeToString(res.GetData().GetBodyHash())] = true
+		}
+	}
+	return hashes
+}
+
+// prohibitedAttr reports why an attribute is not allowed, or "" if it is.
+func prohibitedAttr(tag string, a xml.Attr) string {
+	name := strings.ToLower(a.Name.Local)
+	switch {
+	case enmlProhibitedAttrs[name]:
+		return fmt.Sprintf("attribute %q is not allowed on <%s>", name, tag)
+	case strings.HasPrefix(name, "on"):
+		return fmt.Sprintf("event attribute %q is not allowed on <%s>", name, tag)
+	case (name == "href" || name == "src") && unsafeURL(a.Value):
+		return fmt.Sprintf("unsafe URL in %q on <%s>", name, tag)
+	}
+	return ""
+}
+
+// unsafeURL reports whether a link target uses a scripting scheme.
+func unsafeURL(u string) bool {
+	u = strings.ToLower(strings.Join(strings.Fields(u), ""))
+	return strings.HasPrefix(u, "javascript:") || strings.HasPrefix(u, "vbscript:") || strings.HasPrefix(u, "data:")
+}
+
+// validateENML checks content against the ENML2 rules: well-formed XML with
+// an <en-note> root, only permitted elements and attributes, and <en-media>
+// hashes that match one of the note's resources.
+func validateENML(content string, resources []*edam.Resource) error {
+	hashes := resourceHashes(resources)
+	dec := xml.NewDecoder(strings.NewReader(content))
+	dec.Entity = xml.HTMLEntity
+
+	var problems []enmlProblem
+	add := func(line, col int, format string, args ...interface{}) {
+		problems = append(problems, enmlProblem{Line: line, Column: col, Message: fmt.Sprintf(format, args...)})
+	}
+
+	depth := 0
+	sawRoot := false
+	for {
+		line, col := dec.InputPos()
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				err = errors.New(syntaxErr.Msg)
+			}
+			line, col = dec.InputPos()
+			add(line, col, "malformed XML: %v", err)
+			break
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			tag := t.Name.Local
+			if depth == 0 {
+				if tag != "en-note" || sawRoot {
+					add(line, col, "root element must be <en-note>, found <%s>", tag)
+				}
+				sawRoot = true
+			} else if tag == "en-note" {
+				add(line, col, "<en-note> cannot be nested")
+			}
+			depth++
+
+			if !enmlAllowedElements[tag] {
+				add(line, col, "element <%s> is not allowed", tag)
+				continue
+			}
+			for _, a := range t.Attr {
+				if msg := prohibitedAttr(tag, a); msg != "" {
+					add(line, col, "%s", msg)
+				}
+			}
+			switch tag {
+			case "en-media":
+				hash := strings.ToLower(attr(t.Attr, "hash"))
+				if attr(t.Attr, "type") == "" {
+					add(line, col, "<en-media> is missing the type attribute")
+				}
+				if hash == "" {
+					add(line, col, "<en-media> is missing the hash attribute")
+				} else if !hashes[hash] {
+					add(line, col, "<en-media> hash %s does not match any attached resource", hash)
+				}
+			case "en-todo":
+				if v := attr(t.Attr, "checked"); v != "" && v != "true" && v != "false" {
+					add(line, col, "<en-todo> checked must be \"true\" or \"false\", found %q", v)
+				}
+			}
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && strings.TrimSpace(string(t)) != "" {
+				add(line, col, "text outside <en-note>")
+			}
+		}
+	}
+	if !sawRoot && len(problems) == 0 {
+		add(1, 1, "missing <en-note> root element")
+	}
+
+	if len(problems) > 0 {
+		return &enmlError{Problems: problems}
+	}
+	return nil
+}
+
+// sanitizeENML rewrites content into valid ENML. It tolerates HTML-style
+// markup, drops scripts and other forbidden elements with their content,
+// unwraps remaining unknown elements, strips prohibited attributes and
+// removes <en-media> tags that do not match an attached resource.
+func sanitizeENML(content string, resources []*edam.Resource) (string, error) {
+	hashes := resourceHashes(resources)
+	dec := xml.NewDecoder(strings.NewReader(content))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	var b strings.Builder
+	var open []string
+	skip := 0
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) && strings.Contains(syntaxErr.Msg, "unexpected EOF") {
+				break
+			}
+			line, col := dec.InputPos()
+			return "", fmt.Errorf("cannot sanitize note content: line %d, column %d: %w", line, col, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			tag := strings.ToLower(t.Name.Local)
+			if skip > 0 || enmlDroppedElements[tag] {
+				skip++
+				continue
+			}
+			if tag == "en-media" && !hashes[strings.ToLower(attr(t.Attr, "hash"))] {
+				open = append(open, "")
+				continue
+			}
+			if tag == "en-note" || !enmlAllowedElements[tag] {
+				open = append(open, "")
+				continue
+			}
+
+			b.WriteString("<" + tag)
+			for _, a := range t.Attr {
+				if prohibitedAttr(tag, a) != "" || a.Name.Space != "" {
+					continue
+				}
+				b.WriteString(" " + strings.ToLower(a.Name.Local) + `="` + html.EscapeString(a.Value) + `"`)
+			}
+			if enmlVoidElements[tag] {
+				b.WriteString("/>")
+				open = append(open, "")
+				continue
+			}
+			b.WriteString(">")
+			open = append(open, tag)
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if len(open) == 0 {
+				continue
+			}
+			tag := open[len(open)-1]
+			open = open[:len(open)-1]
+			if tag != "" {
+				b.WriteString("</" + tag + ">")
+			}
+		case xml.CharData:
+			if skip == 0 {
+				b.WriteString(html.EscapeString(string(t)))
+			}
+		}
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i] != "" {
+			b.WriteString("</" + open[i] + ">")
+		}
+	}
+
+	return wrapHTMLInENML(strings.TrimSpace(b.String())), nil
+}
+
+// prepareENML validates content before it is sent to Evernote. With sanitize
+// set, offending markup is removed first instead of being reported.
+func prepareENML(content string, resources []*edam.Resource, sanitize bool) (string, error) {
+	if sanitize {
+		var err error
+		content, err = sanitizeENML(content, resources)
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := validateENML(content, resources); err != nil {
+		if sanitize {
+			return "", err
+		}
+		return "", fmt.Errorf("%w\nuse --sanitize to remove the offending markup", err)
+	}
+	return content, nil
+}
+
+// textFragmentENML converts plain text into escaped ENML paragraphs, one
+// <div> per line, with empty lines kept as blank paragraphs.
+func textFragmentENML(text string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			b.WriteString("<div><br/></div>")
+			continue
+		}
+		b.WriteString("<div>" + html.EscapeString(line) + "</div>")
+	}
+	return b.String()
+}
+
+// insertENML inserts an ENML fragment at the start or end of the <en-note>
+// element. The note is parsed to find the element's boundaries and the
+// fragment is spliced in at those offsets, so existing markup is kept
+// byte-for-byte.
+func insertENML(content, fragment string, prepend bool) (string, error) {
+	if strings.TrimSpace(content) == "" {
+		return wrapHTMLInENML(fragment), nil
+	}
+
+	dec := xml.NewDecoder(strings.NewReader(content))
+	dec.Entity = xml.HTMLEntity
+
+	depth := 0
+	openStart, openEnd, closeStart, closeEnd := -1, -1, -1, -1
+	for {
+		before := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			line, col := dec.InputPos()
+			return "", fmt.Errorf("existing note content is not valid ENML: line %d, column %d: %w", line, col, err)
+		}
+		after := int(dec.InputOffset())
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 && t.Name.Local == "en-note" {
+				openStart, openEnd = before, after
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+			if depth == 0 && t.Name.Local == "en-note" {
+				closeStart, closeEnd = before, after
+			}
+		}
+	}
+	if openStart < 0 || closeStart < 0 {
+		return "", fmt.Errorf("existing note content has no <en-note> element")
+	}
+
+	// A self-closing <en-note/> has no room for content, so expand it.
+	if closeStart == openEnd && strings.HasSuffix(content[openStart:openEnd], "/>") {
+		openTag := strings.TrimRight(strings.TrimSuffix(content[openStart:openEnd], "/>"), " ") + ">"
+		return content[:openStart] + openTag + fragment + "</en-note>" + content[closeEnd:], nil
+	}
+	if prepend {
+		return content[:openEnd] + fragment + content[openEnd:], nil
+	}
+	return content[:closeStart] + fragment + content[closeStart:], nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateENML(t *testing.T) {
+	t.Run("valid content", func(t *testing.T) {
+		content := wrapHTMLInENML(`<div style="color:red">Hi &nbsp;<b>there</b><br/><en-todo checked="true"/></div>`)
+		assert.NoError(t, validateENML(content, nil))
+	})
+
+	t.Run("plain wrapped text is valid", func(t *testing.T) {
+		assert.NoError(t, validateENML(wrapENML("a < b & c"), nil))
+	})
+
+	t.Run("forbidden element and attributes", func(t *testing.T) {
+		content := wrapHTMLInENML(`<div id="x" onclick="go()">a</div><script>alert(1)</script>`)
+		err := validateENML(content, nil)
+		require.Error(t, err)
+
+		var enmlErr *enmlError
+		require.True(t, errors.As(err, &enmlErr))
+		require.Len(t, enmlErr.Problems, 3)
+		assert.Contains(t, enmlErr.Problems[0].Message, `attribute "id"`)
+		assert.Contains(t, enmlErr.Problems[1].Message, `event attribute "onclick"`)
+		assert.Contains(t, enmlErr.Problems[2].Message, "element <script> is not allowed")
+		assert.Equal(t, 1, enmlErr.Problems[2].Line)
+		assert.Greater(t, enmlErr.Problems[2].Column, 1)
+	})
+
+	t.Run("unsafe link", func(t *testing.T) {
+		err := validateENML(wrapHTMLInENML(`<a href="javascript:alert(1)">x</a>`), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsafe URL")
+	})
+
+	t.Run("malformed xml reports position", func(t *testing.T) {
+		content := "<en-note>\n<div>\n<b>bold</div>\n</en-note>"
+		err := validateENML(content, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 3")
+		assert.Contains(t, err.Error(), "malformed XML")
+	})
+
+	t.Run("wrong root", func(t *testing.T) {
+		err := validateENML(`<div>text</div>`, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "root element must be <en-note>")
+	})
+
+	t.Run("en-media hash must match a resource", func(t *testing.T) {
+		hash := md5.Sum([]byte("file"))
+		content := wrapHTMLInENML(buildMediaTag(hash[:], "text/plain"))
+
+		err := validateENML(content, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), fmt.Sprintf("hash %x does not match", hash[:]))
+
+		resources := []*edam.Resource{{Data: &edam.Data{BodyHash: hash[:]}}}
+		assert.NoError(t, validateENML(content, resources))
+	})
+}
+
+func TestSanitizeENML(t *testing.T) {
+	t.Run("strips forbidden markup", func(t *testing.T) {
+		content := wrapHTMLInENML(`<div id="x" class="y" style="color:red" onclick="go()">keep<script>alert(1)</script></div><form><input name="q"/><b>bold</b></form>`)
+		result, err := sanitizeENML(content, nil)
+		require.NoError(t, err)
+		assert.Equal(t, wrapHTMLInENML(`<div style="color:red">keep</div><b>bold</b>`), result)
+		assert.NoError(t, validateENML(result, nil))
+	})
+
+	t.Run("closes unclosed tags and fixes void elements", func(t *testing.T) {
+		result, err := sanitizeENML(wrapHTMLInENML(`<div><b>bold<br>line</div><p>open`), nil)
+		require.NoError(t, err)
+		assert.NoError(t, validateENML(result, nil))
+		assert.Contains(t, result, "<br/>")
+	})
+
+	t.Run("removes media without a resource", func(t *testing.T) {
+		hash := md5.Sum([]byte("gone"))
+		result, err := sanitizeENML(wrapHTMLInENML("a"+buildMediaTag(hash[:], "image/png")), nil)
+		require.NoError(t, err)
+		assert.Equal(t, wrapHTMLInENML("a"), result)
+	})
+
+	t.Run("unsafe links lose their href", func(t *testing.T) {
+		result, err := sanitizeENML(wrapHTMLInENML(`<a href="javascript:x()">link</a>`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, wrapHTMLInENML(`<a>link</a>`), result)
+	})
+}
+
+func TestPrepareENML(t *testing.T) {
+	content := wrapHTMLInENML(`<div class="c">x</div>`)
+
+	_, err := prepareENML(content, nil, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--sanitize")
+
+	result, err := prepareENML(content, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, wrapHTMLInENML(`<div>x</div>`), result)
+}
+
+func TestInsertENML(t *testing.T) {
+	existing := wrapHTMLInENML(`<ul><li><a href="https://x.io">link</a></li></ul><div><en-todo checked="true"/>done</div>`)
+
+	t.Run("append keeps existing markup", func(t *testing.T) {
+		result, err := insertENML(existing, "<div>new</div>", false)
+		require.NoError(t, err)
+		assert.Equal(t, wrapHTMLInENML(`<ul><li><a href="https://x.io">link</a></li></ul><div><en-todo checked="true"/>done</div><div>new</div>`), result)
+	})
+
+	t.Run("prepend keeps existing markup", func(t *testing.T) {
+		result, err := insertENML(existing, "<div>first</div>", true)
+		require.NoError(t, err)
+		assert.Equal(t, wrapHTMLInENML(`<div>first</div><ul><li><a href="https://x.io">link</a></li></ul><div><en-todo checked="true"/>done</div>`), result)
+	})
+
+	t.Run("self-closing en-note", func(t *testing.T) {
+		result, err := insertENML(`<?xml version="1.0"?><en-note style="x" />`, "<div>a</div>", false)
+		require.NoError(t, err)
+		assert.Equal(t, `<?xml version="1.0"?><en-note style="x"><div>a</div></en-note>`, result)
+	})
+
+	t.Run("empty content", func(t *testing.T) {
+		result, err := insertENML("", "<div>a</div>", false)
+		require.NoError(t, err)
+		assert.Equal(t, wrapHTMLInENML("<div>a</div>"), result)
+	})
+
+	t.Run("missing en-note", func(t *testing.T) {
+		_, err := insertENML("<div>a</div>", "<div>b</div>", false)
+		assert.Error(t, err)
+	})
+}
+
+func TestTextFragmentENML(t *testing.T) {
+	assert.Equal(t, "<div>a &lt;b&gt;</div><div><br/></div><div>c</div>", textFragmentENML("a <b>\n\nc"))
+}
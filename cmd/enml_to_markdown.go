@@ -0,0 +1,710 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+)
+
+// markdownLineStart matches text at the start of a line that Markdown would
+// otherwise read as a heading, quote, list item or thematic break.
+var markdownLineStart = regexp.MustCompile(`^(#{1,6}(\s|$)|>|[-+]\s|\d+[.)]\s|-+\s*$|=+\s*$)`)
+
+// mdInline is a buffer for inline content. Emphasis, links and table cells
+// each get their own frame so that surrounding whitespace can be moved outside
+// the Markdown delimiters when the frame is closed.
+type mdInline struct {
+	tag  string
+	href string
+	buf  strings.Builder
+}
+
+// lastIsSpace reports whether the frame is empty or ends in whitespace.
+func (f *mdInline) lastIsSpace() bool {
+	s := f.buf.String()
+	return s == "" || strings.HasSuffix(s, " ") || strings.HasSuffix(s, "\n")
+}
+
+// writeText appends text with HTML whitespace collapsing applied.
+func (f *mdInline) writeText(s string) {
+	s = collapseSpace(s)
+	if strings.HasPrefix(s, " ") && f.lastIsSpace() {
+		s = s[1:]
+	}
+	f.buf.WriteString(s)
+}
+
+// mdList tracks an open <ul> or <ol> and the width of its current marker.
+type mdList struct {
+	ordered bool
+	next    int
+	width   int
+}
+
+// mdTable collects the rows of a table until it can be written as a GFM table.
+type mdTable struct {
+	rows [][]string
+	row  []string
+}
+
+// markdownRenderer converts a stream of ENML tokens into Markdown.
+type markdownRenderer struct {
+	out       strings.Builder
+	inline    []*mdInline
+	lists     []*mdList
+	marker    string
+	itemBlock bool
+	heading   int
+	quote     int
+	lastQuote int
+	lastItem  bool
+	code      int
+	table     *mdTable
+	tables    int
+	skip      int
+	resources map[string]*edam.Resource
+	links     exportLinks
+}
+
+// exportLinks customises the links written for attachments and other
+// notes, for exports where they are saved as files next to each other.
+type exportLinks struct {
+	// media returns the link target for the attachment with the given hash,
+	// or "" to link to its file name.
+	media func(hash string) string
+	// note returns the target for a link to another note (a wikilink target
+	// in Markdown, a page in HTML), or false when the note is not exported.
+	note func(href string) (string, bool)
+}
+
+// enmlToMarkdown converts ENML note content into GitHub-flavoured Markdown.
+// Resources are used to name <en-media> references after their attachment files.
+func enmlToMarkdown(content string, resources []*edam.Resource) (string, error) {
+	return enmlToMarkdownLinks(content, resources, exportLinks{})
+}
+
+// enmlToMarkdownLinks converts ENML note content into Markdown like
+// enmlToMarkdown, with the targets of attachment and note links chosen by
+// links.
+func enmlToMarkdownLinks(content string, resources []*edam.Resource, links exportLinks) (string, error) {
+	r := &markdownRenderer{
+		inline:    []*mdInline{{}},
+		resources: make(map[string]*edam.Resource),
+		links:     links,
+	}
+	for _, res := range resources {
+		if res.GetData() != nil && len(res.GetData().GetBodyHash()) > 0 {
+			r.resources[hex.EncodeToString(res.GetData().GetBodyHash())] = res
+		}
+	}
+
+	dec := xml.NewDecoder(strings.NewReader(content))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid ENML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			r.start(strings.ToLower(t.Name.Local), t.Attr)
+		case xml.EndElement:
+			r.end(strings.ToLower(t.Name.Local))
+		case xml.CharData:
+			r.text(string(t))
+		}
+	}
+	for len(r.inline) > 1 {
+		r.closeTop()
+	}
+	r.flush()
+
+	return r.out.String(), nil
+}
+
+// top returns the innermost open inline frame.
+func (r *markdownRenderer) top() *mdInline {
+	return r.inline[len(r.inline)-1]
+}
+
+// push opens a new inline frame for the given tag.
+func (r *markdownRenderer) push(tag, href string) {
+	r.inline = append(r.inline, &mdInline{tag: tag, href: href})
+}
+
+// pop closes the innermost inline frame and returns it.
+func (r *markdownRenderer) pop() *mdInline {
+	f := r.top()
+	r.inline = r.inline[:len(r.inline)-1]
+	return f
+}
+
+// delimiters returns the Markdown delimiters written around an inline frame.
+func delimiters(f *mdInline) (string, string) {
+	switch f.tag {
+	case "b", "strong":
+		return "**", "**"
+	case "i", "em", "cite", "var", "dfn":
+		return "*", "*"
+	case "s", "strike", "del":
+		return "~~", "~~"
+	case "code", "tt", "kbd", "samp":
+		return "`", "`"
+	case "a":
+		if f.href != "" {
+			return "[", "](" + markdownURL(f.href) + ")"
+		}
+	}
+	return "", ""
+}
+
+// closeInline closes the innermost frame opened by tag, closing any frames
+// left open inside it first so that mis-nested markup still renders.
+func (r *markdownRenderer) closeInline(tag string) {
+	i := len(r.inline) - 1
+	for i > 0 && r.inline[i].tag != tag {
+		if r.inline[i].tag == "cell" || r.inline[i].tag == "pre" {
+			return
+		}
+		i--
+	}
+	if i == 0 {
+		return
+	}
+	for len(r.inline) > i {
+		r.closeTop()
+	}
+}
+
+// closeTop pops the innermost frame and writes its content into the parent
+// wrapped in its delimiters, keeping surrounding whitespace outside them.
+func (r *markdownRenderer) closeTop() {
+	f := r.pop()
+	parent := r.top()
+	s := f.buf.String()
+	if f.tag == "a" && f.href != "" && strings.TrimSpace(s) == "" {
+		s = escapeMarkdown(f.href)
+	}
+	core := strings.TrimSpace(s)
+	if core == "" {
+		parent.writeText(s)
+		return
+	}
+	open, close := delimiters(f)
+	if f.tag == "a" && r.links.note != nil {
+		if target, ok := r.links.note(f.href); ok {
+			open, close = "[["+target+"|", "]]"
+			if core == target {
+				open = "[["
+			}
+		}
+	}
+	lead := s[:strings.Index(s, core)]
+	parent.writeText(lead)
+	parent.buf.WriteString(open + core + close)
+	parent.writeText(s[len(lead)+len(core):])
+}
+
+// breakLine ends the current line inside a block without ending the block.
+func (r *markdownRenderer) breakLine() {
+	f := r.top()
+	if f.buf.Len() > 0 && !strings.HasSuffix(f.buf.String(), "\n") {
+		f.buf.WriteString("\n")
+	}
+}
+
+// boundary is called at the start and end of block elements. Inside inline
+// frames, table cells and code blocks it only breaks the line.
+func (r *markdownRenderer) boundary() {
+	if len(r.inline) > 1 || r.code > 0 {
+		r.breakLine()
+		return
+	}
+	r.flush()
+}
+
+// start handles an opening tag.
+func (r *markdownRenderer) start(tag string, attrs []xml.Attr) {
+	if r.skip > 0 {
+		r.skip++
+		return
+	}
+
+	if r.code > 0 {
+		switch tag {
+		case "br":
+			r.top().buf.WriteString("\n")
+		case "div", "pre":
+			r.breakLine()
+			r.code++
+		case "p", "li", "tr":
+			r.breakLine()
+		}
+		return
+	}
+
+	switch tag {
+	case "script", "style", "head", "title", "object", "embed", "applet", "iframe":
+		r.skip = 1
+	case "en-crypt":
+		r.top().writeText("[encrypted content]")
+		r.skip = 1
+	case "en-note", "div", "p", "center", "address", "section", "article", "header", "footer", "dl", "dt", "dd":
+		if tag == "div" && isCodeBlock(attrs) {
+			r.boundary()
+			r.code++
+			r.push("pre", "")
+			return
+		}
+		r.boundary()
+	case "pre":
+		r.boundary()
+		r.code++
+		r.push("pre", "")
+	case "br":
+		r.breakLine()
+	case "hr":
+		r.boundary()
+		if len(r.inline) == 1 {
+			r.emit([]string{"---"}, r.prefix(), r.prefix(), false)
+		}
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		r.boundary()
+		r.heading = int(tag[1] - '0')
+	case "blockquote":
+		r.boundary()
+		r.quote++
+	case "ul", "ol":
+		r.boundary()
+		if r.marker != "" && len(r.inline) == 1 {
+			r.emit([]string{""}, r.prefix(), r.prefix(), true)
+		}
+		l := &mdList{ordered: tag == "ol", next: 1}
+		if n, err := strconv.Atoi(attr(attrs, "start")); err == nil {
+			l.next = n
+		}
+		r.lists = append(r.lists, l)
+	case "li":
+		r.boundary()
+		if len(r.lists) == 0 {
+			r.lists = append(r.lists, &mdList{})
+		}
+		l := r.lists[len(r.lists)-1]
+		if l.ordered {
+			r.marker = fmt.Sprintf("%d. ", l.next)
+			l.next++
+		} else {
+			r.marker = "- "
+		}
+		l.width = len(r.marker)
+		r.itemBlock = true
+	case "table":
+		r.tables++
+		if r.tables > 1 {
+			r.boundary()
+			return
+		}
+		r.boundary()
+		r.table = &mdTable{}
+	case "tr":
+		if r.tables > 1 {
+			r.boundary()
+			return
+		}
+		if r.table != nil {
+			r.table.row = nil
+		}
+	case "td", "th":
+		if r.tables > 1 {
+			r.top().writeText(" ")
+			return
+		}
+		r.push("cell", "")
+	case "b", "strong":
+		r.push(tag, "")
+	case "i", "em", "cite", "var", "dfn":
+		r.push(tag, "")
+	case "s", "strike", "del":
+		r.push(tag, "")
+	case "code", "tt", "kbd", "samp":
+		r.push(tag, "")
+	case "a":
+		r.push(tag, attr(attrs, "href"))
+	case "img":
+		alt := attr(attrs, "alt")
+		src := attr(attrs, "src")
+		if src != "" {
+			r.top().buf.WriteString("![" + escapeMarkdown(alt) + "](" + markdownURL(src) + ")")
+		}
+	case "en-todo":
+		box := "[ ] "
+		if strings.EqualFold(attr(attrs, "checked"), "true") {
+			box = "[x] "
+		}
+		f := r.top()
+		switch {
+		case len(r.inline) == 1 && strings.TrimSpace(f.buf.String()) == "" && r.marker == "- ":
+			r.marker = "- " + box
+		case len(r.inline) == 1 && strings.TrimSpace(f.buf.String()) == "" && r.marker == "":
+			r.marker = "- " + box
+			r.itemBlock = true
+		default:
+			if !f.lastIsSpace() {
+				f.buf.WriteString(" ")
+			}
+			f.buf.WriteString(box)
+		}
+	case "en-media":
+		r.top().buf.WriteString(r.mediaLink(attr(attrs, "hash"), attr(attrs, "type")))
+	}
+}
+
+// end handles a closing tag.
+func (r *markdownRenderer) end(tag string) {
+	if r.skip > 0 {
+		r.skip--
+		return
+	}
+
+	if r.code > 0 {
+		switch tag {
+		case "div", "pre":
+			r.breakLine()
+		case "p", "li", "tr":
+			r.breakLine()
+			return
+		default:
+			return
+		}
+		r.code--
+		if r.code == 0 && r.top().tag == "pre" {
+			f := r.pop()
+			body := strings.Trim(f.buf.String(), "\n")
+			lines := append([]string{"```"}, strings.Split(body, "\n")...)
+			lines = append(lines, "```")
+			r.emit(lines, r.prefix(), r.contPrefix(), false)
+		}
+		return
+	}
+
+	switch tag {
+	case "en-note", "div", "p", "center", "address", "section", "article", "header", "footer", "dl", "dt", "dd":
+		r.boundary()
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		r.boundary()
+		r.heading = 0
+	case "blockquote":
+		r.boundary()
+		if r.quote > 0 {
+			r.quote--
+		}
+	case "ul", "ol":
+		r.boundary()
+		if len(r.lists) > 0 {
+			r.lists = r.lists[:len(r.lists)-1]
+		}
+		r.itemBlock = len(r.lists) > 0
+	case "li":
+		r.boundary()
+		if r.marker != "" && len(r.inline) == 1 {
+			r.emit([]string{""}, r.prefix(), r.prefix(), true)
+		}
+	case "table":
+		r.tables--
+		if r.tables > 0 {
+			r.boundary()
+			return
+		}
+		r.writeTable()
+	case "tr":
+		if r.tables > 1 {
+			r.boundary()
+			return
+		}
+		if r.table != nil && len(r.table.row) > 0 {
+			r.table.rows = append(r.table.rows, r.table.row)
+			r.table.row = nil
+		}
+	case "td", "th":
+		if r.tables > 1 || len(r.inline) < 2 || r.top().tag != "cell" {
+			return
+		}
+		f := r.pop()
+		var lines []string
+		for _, line := range strings.Split(f.buf.String(), "\n") {
+			if line = strings.Trim(line, htmlSpace); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		cell := strings.ReplaceAll(strings.Join(lines, "<br>"), "|", `\|`)
+		if r.table != nil {
+			r.table.row = append(r.table.row, cell)
+		} else {
+			r.top().writeText(cell + " ")
+		}
+	case "b", "strong", "i", "em", "cite", "var", "dfn", "s", "strike", "del", "code", "tt", "kbd", "samp", "a":
+		r.closeInline(tag)
+	}
+}
+
+// text handles character data.
+func (r *markdownRenderer) text(s string) {
+	if r.skip > 0 {
+		return
+	}
+	if r.code > 0 {
+		r.top().buf.WriteString(s)
+		return
+	}
+	if r.table != nil && r.top().tag != "cell" && r.tables == 1 {
+		return
+	}
+	if r.top().tag == "code" || r.top().tag == "tt" || r.top().tag == "kbd" || r.top().tag == "samp" {
+		r.top().writeText(strings.ReplaceAll(s, "`", "'"))
+		return
+	}
+	r.top().writeText(escapeMarkdown(s))
+}
+
+// flush writes the pending paragraph, heading or list item to the output.
+func (r *markdownRenderer) flush() {
+	f := r.inline[0]
+	text := f.buf.String()
+	f.buf.Reset()
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, strings.Trim(line, htmlSpace))
+	}
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	for i, line := range lines {
+		if markdownLineStart.MatchString(line) {
+			lines[i] = `\` + line
+		}
+		if i < len(lines)-1 && line != "" && lines[i+1] != "" && r.heading == 0 {
+			lines[i] += "  "
+		}
+	}
+	if r.heading > 0 {
+		lines = []string{strings.Repeat("#", r.heading) + " " + strings.Join(lines, " ")}
+	}
+
+	r.emit(lines, r.prefix(), r.contPrefix(), r.itemBlock)
+}
+
+// emit writes a block of lines, separating it from the previous block.
+// Consecutive list items are kept tight with a single newline.
+func (r *markdownRenderer) emit(lines []string, first, cont string, item bool) {
+	if r.out.Len() > 0 {
+		if item && r.lastItem {
+			r.out.WriteString("\n")
+		} else {
+			q := r.quote
+			if r.lastQuote < q {
+				q = r.lastQuote
+			}
+			r.out.WriteString("\n" + strings.TrimRight(strings.Repeat("> ", q), " ") + "\n")
+		}
+	}
+	for i, line := range lines {
+		p := cont
+		if i == 0 {
+			p = first
+		}
+		if i > 0 {
+			r.out.WriteString("\n")
+		}
+		r.out.WriteString(strings.TrimRight(p+line, " \t"))
+		if strings.HasSuffix(line, "  ") {
+			r.out.WriteString("  ")
+		}
+	}
+	r.marker = ""
+	r.lastItem = item
+	r.lastQuote = r.quote
+	r.itemBlock = len(r.lists) > 0
+}
+
+// listIndent returns the indentation for content nested n list levels deep.
+func (r *markdownRenderer) listIndent(n int) string {
+	width := 0
+	for _, l := range r.lists[:n] {
+		width += l.width
+	}
+	return strings.Repeat(" ", width)
+}
+
+// prefix returns the prefix for the first line of the next block.
+func (r *markdownRenderer) prefix() string {
+	q := strings.Repeat("> ", r.quote)
+	if r.marker == "" {
+		return q + r.listIndent(len(r.lists))
+	}
+	if len(r.lists) == 0 {
+		return q + r.marker
+	}
+	return q + r.listIndent(len(r.lists)-1) + r.marker
+}
+
+// contPrefix returns the prefix for continuation lines of the next block.
+func (r *markdownRenderer) contPrefix() string {
+	q := strings.Repeat("> ", r.quote)
+	if r.marker != "" && len(r.lists) == 0 {
+		return q + "  "
+	}
+	return q + r.listIndent(len(r.lists))
+}
+
+// writeTable writes the collected table as a GFM table. The first row is
+// used as the header because GFM tables require one.
+func (r *markdownRenderer) writeTable() {
+	t := r.table
+	r.table = nil
+	if t == nil {
+		return
+	}
+	if len(t.row) > 0 {
+		t.rows = append(t.rows, t.row)
+	}
+	if len(t.rows) == 0 {
+		return
+	}
+
+	cols := 0
+	for _, row := range t.rows {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+
+	lines := make([]string, 0, len(t.rows)+1)
+	for i, row := range t.rows {
+		cells := make([]string, cols)
+		copy(cells, row)
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			sep := make([]string, cols)
+			for j := range sep {
+				sep[j] = "---"
+			}
+			lines = append(lines, "| "+strings.Join(sep, " | ")+" |")
+		}
+	}
+	r.emit(lines, r.prefix(), r.contPrefix(), false)
+}
+
+// mediaLink returns a Markdown link for an <en-media> reference named after
+// the attachment file. Images are rendered as image links.
+func (r *markdownRenderer) mediaLink(hash, mimeType string) string {
+	hash = strings.ToLower(hash)
+	name := resourceFileName(r.resources[hash], hash, mimeType)
+	target := name
+	if r.links.media != nil {
+		if t := r.links.media(hash); t != "" {
+			target = t
+		}
+	}
+	link := "[" + escapeMarkdown(name) + "](" + markdownURL(target) + ")"
+	if strings.HasPrefix(mimeType, "image/") {
+		return "!" + link
+	}
+	return link
+}
+
+// resourceFileName returns the file name for a resource, falling back to the
+// hash with an extension derived from the MIME type.
+func resourceFileName(res *edam.Resource, hash, mimeType string) string {
+	if res != nil && res.GetAttributes() != nil && res.GetAttributes().GetFileName() != "" {
+		return res.GetAttributes().GetFileName()
+	}
+	if res != nil && res.GetMime() != "" {
+		mimeType = res.GetMime()
+	}
+	if exts, _ := mime.ExtensionsByType(mimeType); len(exts) > 0 {
+		return hash + exts[0]
+	}
+	return hash
+}
+
+// isCodeBlock reports whether a div carries Evernote's code block style.
+func isCodeBlock(attrs []xml.Attr) bool {
+	style := strings.ReplaceAll(attr(attrs, "style"), " ", "")
+	return strings.Contains(style, "-en-codeblock:true")
+}
+
+// attr returns the value of the named attribute, or "" if it is not set.
+func attr(attrs []xml.Attr, name string) string {
+	for _, a := range attrs {
+		if strings.EqualFold(a.Name.Local, name) {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// htmlSpace is the whitespace HTML collapses. A no-break space is not part
+// of it: Evernote writes runs of them for indentation and repeated spaces.
+const htmlSpace = " \t\n\r\f"
+
+// collapseSpace replaces runs of HTML whitespace with a single space.
+func collapseSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, c := range s {
+		if strings.ContainsRune(htmlSpace, c) {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// escapeMarkdown escapes characters that Markdown treats as inline syntax.
+func escapeMarkdown(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch c {
+		case '\\', '*', '_', '`', '[', ']', '<':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// markdownURL returns a link destination, percent-encoding characters that
+// would otherwise end the destination early.
+func markdownURL(u string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E").Replace(u)
+}
@@ -0,0 +1,111 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"crypto/md5"
+	"fmt"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestENMLToMarkdown(t *testing.T) {
+	t.Run("paragraphs from divs", func(t *testing.T) {
+		md, err := enmlToMarkdown(wrapHTMLInENML(`<div>First line</div><div><br/></div><div>Second line</div>`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "First line\n\nSecond line", md)
+	})
+
+	t.Run("headings and inline formatting", func(t *testing.T) {
+		md, err := enmlToMarkdown(wrapHTMLInENML(`<h2>Title</h2><p>Some <b>bold</b>, <i>italic </i>and <s>gone</s> text</p>`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "## Title\n\nSome **bold**, *italic* and ~~gone~~ text", md)
+	})
+
+	t.Run("no-break spaces are kept", func(t *testing.T) {
+		md, err := enmlToMarkdown(wrapHTMLInENML(`<div>&nbsp;&nbsp;indented</div><div>a&nbsp;&nbsp; b <b>&nbsp;c</b></div>`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "\u00a0\u00a0indented\n\na\u00a0\u00a0 b \u00a0**c**", md)
+	})
+
+	t.Run("links", func(t *testing.T) {
+		md, err := enmlToMarkdown(wrapHTMLInENML(`<div>See <a href="https://example.com/a b">the docs</a></div>`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "See [the docs](https://example.com/a%20b)", md)
+	})
+
+	t.Run("nested lists", func(t *testing.T) {
+		md, err := enmlToMarkdown(wrapHTMLInENML(`<ul><li>One</li><li>Two<ol><li>Sub</li><li>Sub two</li></ol></li></ul><div>After</div>`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "- One\n- Two\n  1. Sub\n  2. Sub two\n\nAfter", md)
+	})
+
+	t.Run("todo checkboxes", func(t *testing.T) {
+		md, err := enmlToMarkdown(wrapHTMLInENML(`<div><en-todo checked="true"/>Done</div><div><en-todo checked="false"/>Open</div><ul><li><en-todo/>In list</li></ul>`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "- [x] Done\n- [ ] Open\n- [ ] In list", md)
+	})
+
+	t.Run("tables", func(t *testing.T) {
+		md, err := enmlToMarkdown(wrapHTMLInENML(`<table><tr><th>Name</th><th>Value</th></tr><tr><td>a|b</td><td><div>1</div><div>2</div></td></tr></table>`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "| Name | Value |\n| --- | --- |\n| a\\|b | 1<br>2 |", md)
+	})
+
+	t.Run("media references name the attachment", func(t *testing.T) {
+		data := []byte("pdf data")
+		hash := md5.Sum(data)
+		fileName := "report final.pdf"
+		mimeType := "application/pdf"
+		resources := []*edam.Resource{{
+			Data:       &edam.Data{BodyHash: hash[:]},
+			Mime:       &mimeType,
+			Attributes: &edam.ResourceAttributes{FileName: &fileName},
+		}}
+		content := wrapHTMLInENML(`<div>Attached:</div>` + buildMediaTag(hash[:], mimeType))
+
+		md, err := enmlToMarkdown(content, resources)
+		require.NoError(t, err)
+		assert.Equal(t, "Attached:\n\n[report final.pdf](report%20final.pdf)", md)
+	})
+
+	t.Run("unknown image media falls back to hash", func(t *testing.T) {
+		hash := md5.Sum([]byte("img"))
+		md, err := enmlToMarkdown(wrapHTMLInENML(buildMediaTag(hash[:], "image/png")), nil)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("![%x.png](%x.png)", hash[:], hash[:]), md)
+	})
+
+	t.Run("code block", func(t *testing.T) {
+		md, err := enmlToMarkdown(wrapHTMLInENML(`<div style="-en-codeblock:true;"><div>if x {</div><div><br/></div><div>  *y</div></div>`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "```\nif x {\n\n  *y\n```", md)
+	})
+
+	t.Run("blockquote and line breaks", func(t *testing.T) {
+		md, err := enmlToMarkdown(wrapHTMLInENML(`<blockquote><div>one<br/>two</div><div>three</div></blockquote>`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "> one  \n> two\n>\n> three", md)
+	})
+
+	t.Run("escapes markdown syntax in text", func(t *testing.T) {
+		md, err := enmlToMarkdown(wrapHTMLInENML(`<div># not a heading</div><div>a *star* &amp; [link]</div>`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "\\# not a heading\n\na \\*star\\* & \\[link\\]", md)
+	})
+
+	t.Run("encrypted content is not exposed", func(t *testing.T) {
+		md, err := enmlToMarkdown(wrapHTMLInENML(`<div>Secret: <en-crypt cipher="AES">abc</en-crypt></div>`), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Secret: [encrypted content]", md)
+	})
+
+	t.Run("empty note", func(t *testing.T) {
+		md, err := enmlToMarkdown(wrapENML(""), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "", md)
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/dreampuf/evernote-sdk-golang/client"
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/mrjones/oauth"
+)
+
+// evernoteClient is the part of the Evernote SDK client used to sign in and
+// reach the NoteStore.
+type evernoteClient interface {
+	GetRequestToken(callbackURL string) (*oauth.RequestToken, string, error)
+	GetAuthorizedToken(requestToken *oauth.RequestToken, verifier string) (*oauth.AccessToken, error)
+	GetUserStore() (*edam.UserStoreClient, error)
+	GetNoteStore(ctx context.Context, authenticationToken string) (*edam.NoteStoreClient, error)
+	GetNoteStoreWithURL(noteStoreURL string) (*edam.NoteStoreClient, error)
+}
+
+// knownEnvironments maps the environment names to the SDK's services.
+var knownEnvironments = map[string]client.EnvironmentType{
+	"production":       client.PRODUCTION,
+	"sandbox":          client.SANDBOX,
+	"yinxiang":         client.YINXIANG,
+	"yinxiang-sandbox": client.YINXIANGSANDBOX,
+}
+
+// environment is the Evernote service an account lives on: one the SDK
+// knows by name, or a custom host.
+type environment struct {
+	// name is the environment as saved in the config, e.g. "sandbox" or
+	// "https://evernote.example.com".
+	name string
+	// baseURL is the scheme and host of a custom service, or empty.
+	baseURL string
+}
+
+// parseEnvironment reads an environment name or the URL of a custom host.
+// An empty name is production. Custom hosts must use https, except on the
+// loopback interface: the consumer secret, the verifier and every auth token
+// would otherwise travel in cleartext.
+func parseEnvironment(s string) (environment, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if name == "" {
+		return environment{name: "production"}, nil
+	}
+	if _, ok := knownEnvironments[name]; ok {
+		return environment{name: name}, nil
+	}
+
+	raw := strings.TrimSpace(s)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") || strings.Trim(u.Path, "/") != "" || u.RawQuery != "" {
+		return environment{}, fmt.Errorf("invalid environment %q (use production, sandbox, yinxiang or a host URL such as https://evernote.example.com)", s)
+	}
+	if u.Scheme == "http" && !isLoopbackHost(u.Hostname()) {
+		return environment{}, fmt.Errorf("invalid environment %q: custom hosts must use https, as credentials would be sent in cleartext (http is only allowed for localhost)", s)
+	}
+	base := u.Scheme + "://" + u.Host
+	return environment{name: base, baseURL: base}, nil
+}
+
+// isLoopbackHost reports whether host names this machine.
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// newClient returns a client for the environment signed with the API key.
+func (e environment) newClient(clientID, clientSecret string) evernoteClient {
+	if e.baseURL == "" {
+		return client.NewClient(clientID, clientSecret, knownEnvironments[e.name])
+	}
+	return &hostClient{
+		baseURL: e.baseURL,
+		consumer: oauth.NewConsumer(clientID, clientSecret, oauth.ServiceProvider{
+			RequestTokenUrl:   e.baseURL + "/oauth",
+			AuthorizeTokenUrl: e.baseURL + "/OAuth.action",
+			AccessTokenUrl:    e.baseURL + "/oauth",
+		}),
+	}
+}
+
+// hostClient talks to a custom Evernote host. The SDK client only takes one
+// of its fixed environments, so this builds the OAuth consumer and UserStore
+// the same way for any host.
+type hostClient struct {
+	baseURL  string
+	consumer *oauth.Consumer
+}
+
+// GetRequestToken starts the OAuth flow and returns the authorization URL.
+func (c *hostClient) GetRequestToken(callbackURL string) (*oauth.RequestToken, string, error) {
+	return c.consumer.GetRequestTokenAndUrl(callbackURL)
+}
+
+// GetAuthorizedToken exchanges the verifier for an access token.
+func (c *hostClient) GetAuthorizedToken(requestToken *oauth.RequestToken, verifier string) (*oauth.AccessToken, error) {
+	return c.consumer.AuthorizeToken(requestToken, verifier)
+}
+
+// GetUserStore connects to the host's UserStore.
+func (c *hostClient) GetUserStore() (*edam.UserStoreClient, error) {
+	transport, err := thrift.NewTHttpClient(c.baseURL + "/edam/user")
+	if err != nil {
+		return nil, err
+	}
+	return edam.NewUserStoreClient(thrift.NewTStandardClient(
+		thrift.NewTBinaryProtocolFactoryDefault().GetProtocol(transport),
+		thrift.NewTBinaryProtocolFactory(true, true).GetProtocol(transport),
+	)), nil
+}
+
+// GetNoteStore asks the host's UserStore where the account's NoteStore is.
+func (c *hostClient) GetNoteStore(ctx context.Context, authenticationToken string) (*edam.NoteStoreClient, error) {
+	us, err := c.GetUserStore()
+	if err != nil {
+		return nil, err
+	}
+	urls, err := us.GetUserUrls(ctx, authenticationToken)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetNoteStoreWithURL(urls.GetNoteStoreUrl())
+}
+
+// GetNoteStoreWithURL connects to a NoteStore by URL.
+func (c *hostClient) GetNoteStoreWithURL(noteStoreURL string) (*edam.NoteStoreClient, error) {
+	transport, err := thrift.NewTHttpClient(noteStoreURL)
+	if err != nil {
+		return nil, err
+	}
+	return edam.NewNoteStoreClient(thrift.NewTStandardClient(
+		thrift.NewTBinaryProtocolFactoryDefault().GetProtocol(transport),
+		thrift.NewTBinaryProtocolFactory(true, true).GetProtocol(transport),
+	)), nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvironment(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantName string
+		wantBase string
+	}{
+		{"", "production", ""},
+		{"Sandbox", "sandbox", ""},
+		{"yinxiang", "yinxiang", ""},
+		{"https://evernote.example.com", "https://evernote.example.com", "https://evernote.example.com"},
+		{"https://evernote.example.com/", "https://evernote.example.com", "https://evernote.example.com"},
+		{"evernote.example.com:8443", "https://evernote.example.com:8443", "https://evernote.example.com:8443"},
+		{"http://localhost:9000", "http://localhost:9000", "http://localhost:9000"},
+		{"http://127.0.0.1:9000", "http://127.0.0.1:9000", "http://127.0.0.1:9000"},
+		{"http://[::1]:9000", "http://[::1]:9000", "http://[::1]:9000"},
+	}
+	for _, tt := range tests {
+		env, err := parseEnvironment(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.wantName, env.name, tt.in)
+		assert.Equal(t, tt.wantBase, env.baseURL, tt.in)
+	}
+
+	for _, bad := range []string{"ftp://example.com", "https://example.com/oauth", "https://"} {
+		_, err := parseEnvironment(bad)
+		assert.Error(t, err, bad)
+	}
+
+	_, err := parseEnvironment("http://evernote.example.com")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must use https")
+}
+
+func TestEnvironmentNewClient(t *testing.T) {
+	env, err := parseEnvironment("sandbox")
+	require.NoError(t, err)
+	assert.IsType(t, &client.EvernoteClient{}, env.newClient("id", "secret"))
+
+	var requested string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		fmt.Fprint(w, "oauth_token=request-token&oauth_token_secret=s&oauth_callback_confirmed=true")
+	}))
+	defer srv.Close()
+
+	env, err = parseEnvironment(srv.URL)
+	require.NoError(t, err)
+	c := env.newClient("id", "secret")
+	require.IsType(t, &hostClient{}, c)
+
+	token, authURL, err := c.GetRequestToken("http://localhost:8080/callback")
+	require.NoError(t, err)
+	assert.Equal(t, "/oauth", requested)
+	assert.Equal(t, "request-token", token.Token)
+	assert.Equal(t, srv.URL+"/OAuth.action?oauth_token=request-token", authURL)
+}
+
+func TestGetNoteStoreFunc_InvalidEnvironment(t *testing.T) {
+	originalConfigPath := configPath
+	defer func() { configPath = originalConfigPath }()
+	originalDataDir := dataDir
+	defer func() { dataDir = originalDataDir }()
+	dataDir = t.TempDir()
+
+	configPath = filepath.Join(t.TempDir(), "auth.json")
+	require.NoError(t, saveConfig(&Config{AuthToken: "token", Environment: "ftp://example.com"}))
+
+	_, _, err := getDefaultNoteStore()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid environment "ftp://example.com"`)
+}
@@ -0,0 +1,214 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat   string
+	exportOut      string
+	exportNotebook string
+	exportQuery    string
+)
+
+// exportSummary is the JSON output of the export command.
+type exportSummary struct {
+	Format    string `json:"format"`
+	Out       string `json:"out"`
+	Notes     int    `json:"notes"`
+	Resources int    `json:"resources"`
+}
+
+// exportNotes returns the notes to export: the GUIDs given as arguments (or
+// on stdin with "-"), or the notes in --notebook and/or matching --query.
+// Notes found by --notebook or --query come with their title, notebook and
+// creation time; notes given by GUID only have their GUID.
+func exportNotes(cmd *cobra.Command, ns noteStoreClient, token string, r *nameResolver, args []string) ([]*edam.NoteMetadata, error) {
+	if len(args) > 0 {
+		if exportNotebook != "" {
+			return nil, fmt.Errorf("GUID arguments cannot be used with --notebook")
+		}
+		if exportQuery != "" {
+			return nil, fmt.Errorf("GUID arguments cannot be used with --query")
+		}
+		guids, err := noteGUIDsFromInput(cmd, ns, token, args, "", false)
+		if err != nil {
+			return nil, err
+		}
+		notes := make([]*edam.NoteMetadata, len(guids))
+		for i, guid := range guids {
+			notes[i] = &edam.NoteMetadata{GUID: guid}
+		}
+		return notes, nil
+	}
+	if exportNotebook == "" && exportQuery == "" {
+		return nil, fmt.Errorf("give note GUIDs, --notebook or --query")
+	}
+
+	filter := &edam.NoteFilter{}
+	if exportNotebook != "" {
+		nb, err := r.notebook(exportNotebook)
+		if err != nil {
+			return nil, err
+		}
+		guid := nb.GetGUID()
+		filter.NotebookGuid = &guid
+	}
+	if exportQuery != "" {
+		filter.Words = &exportQuery
+	}
+	include := true
+	spec := &edam.NotesMetadataResultSpec{IncludeTitle: &include, IncludeNotebookGuid: &include, IncludeCreated: &include}
+	return findAllNotes(ns, token, filter, spec)
+}
+
+// exportENEX streams the notes to w as ENEX, fetching one note at a time.
+func exportENEX(w io.Writer, ns noteStoreClient, token string, r *nameResolver, notes []*edam.NoteMetadata, summary *exportSummary) error {
+	enex := newENEXWriter(w, timeNow())
+	for _, meta := range notes {
+		note, err := ns.GetNote(context.Background(), token, meta.GetGUID(), true, true, true, false)
+		if err != nil {
+			return fmt.Errorf("failed to get note %s: %w", meta.GetGUID(), formatAPIError(err))
+		}
+		if err := enex.writeNote(note, r.tagNames(note.GetTagGuids())); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+		summary.Notes++
+		summary.Resources += len(note.GetResources())
+	}
+	if err := enex.close(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// writeExportFile streams an export into path through a temporary file that
+// is renamed into place once complete, so a failed export never leaves a
+// truncated file behind.
+func writeExportFile(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// exportCmd exports notes to files that other applications can read.
+var exportCmd = &cobra.Command{
+	Use:   "export [guid...]",
+	Short: "Export notes to ENEX, Markdown or HTML",
+	Long: `Export notes with their content, attributes, tags and attachments.
+
+Choose the notes by GUID (or "-" to read GUIDs from stdin), by notebook with
+--notebook, or with a search --query; --notebook and --query can be combined.
+
+--format enex writes a standard Evernote export file that other applications
+such as Joplin or Obsidian's importers can read. Notes are fetched and
+written one at a time, so large notebooks never sit in memory. The file is
+written to --out, or to stdout when --out is not given.
+
+--format markdown writes a folder of Markdown files that Obsidian and other
+plain-text tools can open, into the --out directory. Each notebook gets a
+folder, inside a folder for its stack, and each note a .md file with YAML
+front matter holding its GUID, notebook, tags, dates and source URL.
+Attachments are saved under _resources/ next to the note and linked
+relative to it, and links between exported notes become [[wikilinks]].
+Exporting again into the same directory overwrites the same files.
+
+--format html writes a static site into the --out directory, ready to
+publish as it is: a standalone page per note under notes/, with images and
+attachments saved next to it, an index page per notebook and per tag, and
+index.html listing them all. Checkboxes are shown ticked or not but cannot
+be changed, and links between exported notes point to their pages.
+
+Examples:
+  evernote-cli export --notebook Journal --out journal.enex
+  evernote-cli export --query "tag:recipes" > recipes.enex
+  evernote-cli export <guid> <guid> --out notes.enex
+  evernote-cli export --format markdown --notebook Journal --out vault/
+  evernote-cli export --format html --notebook Handbook --out site/`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if exportFormat != "enex" && exportFormat != "markdown" && exportFormat != "html" {
+			return fmt.Errorf("invalid --format %q (use enex, markdown or html)", exportFormat)
+		}
+		if exportFormat != "enex" && exportOut == "" {
+			return fmt.Errorf("--out is required for --format %s", exportFormat)
+		}
+
+		ns, token, err := getRemoteNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+		resolver := newNameResolver(ns, token)
+		notes, err := exportNotes(cmd, ns, token, resolver, args)
+		if err != nil {
+			return err
+		}
+
+		summary := &exportSummary{Format: exportFormat, Out: exportOut}
+		write := func(w io.Writer) error {
+			return exportENEX(w, ns, token, resolver, notes, summary)
+		}
+
+		// The export itself goes to stdout without --out, so report on stderr
+		report := cmd.OutOrStdout()
+		switch {
+		case exportFormat == "markdown":
+			err = exportMarkdown(exportOut, ns, token, resolver, notes, summary)
+		case exportFormat == "html":
+			err = exportHTML(exportOut, ns, token, resolver, notes, summary)
+		case exportOut == "":
+			summary.Out = "-"
+			report = cmd.ErrOrStderr()
+			err = write(cmd.OutOrStdout())
+		default:
+			err = writeExportFile(exportOut, write)
+		}
+		if err != nil {
+			return err
+		}
+
+		if jsonFlag {
+			enc := json.NewEncoder(report)
+			enc.SetIndent("", "  ")
+			return enc.Encode(summary)
+		}
+		if exportOut == "" {
+			fmt.Fprintf(report, "Exported %d note(s) with %d attachment(s).\n", summary.Notes, summary.Resources)
+		} else {
+			fmt.Fprintf(report, "Exported %d note(s) with %d attachment(s) to %s\n", summary.Notes, summary.Resources, exportOut)
+		}
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "enex", "export format: enex, markdown or html")
+	exportCmd.Flags().StringVarP(&exportOut, "out", "o", "", "output file, or directory for markdown and html (enex defaults to stdout)")
+	exportCmd.Flags().StringVar(&exportNotebook, "notebook", "", "export the notes in this notebook (name or GUID)")
+	exportCmd.Flags().StringVar(&exportQuery, "query", "", "export the notes matching this search")
+	rootCmd.AddCommand(exportCmd)
+}
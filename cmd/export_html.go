@@ -0,0 +1,526 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+)
+
+// siteSlug turns a name into a lower-case file name that is safe in URLs.
+func siteSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimRight(b.String(), "-")
+	const max = 80
+	if len(slug) > max {
+		cut := max
+		for cut > 0 && !utf8.RuneStart(slug[cut]) {
+			cut--
+		}
+		slug = strings.TrimRight(slug[:cut], "-")
+	}
+	if slug == "" {
+		return "untitled"
+	}
+	return slug
+}
+
+// siteSlugs hands out slugs that are unique within one kind of page.
+type siteSlugs map[string]bool
+
+// unique returns the slug for name, numbered when it is already taken.
+func (s siteSlugs) unique(name string) string {
+	base := siteSlug(name)
+	slug := base
+	for i := 2; s[slug]; i++ {
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+	s[slug] = true
+	return slug
+}
+
+// siteURL escapes a slash-separated relative path for use in a link.
+func siteURL(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if p != ".." {
+			parts[i] = url.PathEscape(p)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// enmlToHTML renders ENML note content as HTML. Attachments are shown from
+// the files links.media names, images inline and other files as links;
+// checkboxes are shown disabled; links to other notes point to the pages
+// links.note names, or become plain text when the note is not exported.
+func enmlToHTML(content string, resources []*edam.Resource, links exportLinks) (string, error) {
+	byHash := map[string]*edam.Resource{}
+	for _, res := range resources {
+		if res.GetData() != nil && len(res.GetData().GetBodyHash()) > 0 {
+			byHash[hex.EncodeToString(res.GetData().GetBodyHash())] = res
+		}
+	}
+
+	dec := xml.NewDecoder(strings.NewReader(content))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	var b strings.Builder
+	var open []string
+	skip := 0
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid ENML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			tag := strings.ToLower(t.Name.Local)
+			if skip > 0 || enmlDroppedElements[tag] {
+				skip++
+				continue
+			}
+			switch tag {
+			case "en-crypt":
+				b.WriteString(`<span class="encrypted">[encrypted content]</span>`)
+				skip = 1
+				continue
+			case "en-todo":
+				b.WriteString(`<input type="checkbox" disabled`)
+				if strings.EqualFold(attr(t.Attr, "checked"), "true") {
+					b.WriteString(" checked")
+				}
+				b.WriteString(">")
+				open = append(open, "")
+				continue
+			case "en-media":
+				hash := strings.ToLower(attr(t.Attr, "hash"))
+				mimeType := attr(t.Attr, "type")
+				var target string
+				if links.media != nil {
+					target = links.media(hash)
+				}
+				if target != "" {
+					name := html.EscapeString(resourceFileName(byHash[hash], hash, mimeType))
+					href := html.EscapeString(siteURL(target))
+					if strings.HasPrefix(mimeType, "image/") {
+						b.WriteString(`<img src="` + href + `" alt="` + name + `">`)
+					} else {
+						b.WriteString(`<a class="attachment" href="` + href + `">` + name + `</a>`)
+					}
+				}
+				open = append(open, "")
+				continue
+			case "a":
+				href := attr(t.Attr, "href")
+				if _, ok := noteLinkGUID(href); ok {
+					page, ok := "", false
+					if links.note != nil {
+						page, ok = links.note(href)
+					}
+					if ok {
+						b.WriteString(`<a href="` + html.EscapeString(siteURL(page)) + `">`)
+						open = append(open, "a")
+					} else {
+						open = append(open, "")
+					}
+					continue
+				}
+			}
+			if tag == "en-note" || !enmlAllowedElements[tag] {
+				open = append(open, "")
+				continue
+			}
+
+			b.WriteString("<" + tag)
+			for _, a := range t.Attr {
+				if prohibitedAttr(tag, a) != "" || a.Name.Space != "" {
+					continue
+				}
+				b.WriteString(" " + strings.ToLower(a.Name.Local) + `="` + html.EscapeString(a.Value) + `"`)
+			}
+			b.WriteString(">")
+			if enmlVoidElements[tag] {
+				open = append(open, "")
+				continue
+			}
+			open = append(open, tag)
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if len(open) == 0 {
+				continue
+			}
+			tag := open[len(open)-1]
+			open = open[:len(open)-1]
+			if tag != "" {
+				b.WriteString("</" + tag + ">")
+			}
+		case xml.CharData:
+			if skip == 0 {
+				b.WriteString(html.EscapeString(string(t)))
+			}
+		}
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i] != "" {
+			b.WriteString("</" + open[i] + ">")
+		}
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// siteLink is a link on a site page.
+type siteLink struct {
+	Name  string
+	Href  string
+	Count int
+}
+
+// siteList is a headed list of links on an index page.
+type siteList struct {
+	Heading string
+	Links   []siteLink
+}
+
+// sitePage is the data for a site page: either a note, with Body and its
+// details, or an index, with Lists.
+type sitePage struct {
+	Title string
+	// Root is the relative path back to the top of the site.
+	Root     string
+	Notebook *siteLink
+	Tags     []siteLink
+	Created  string
+	Updated  string
+	Source   string
+	Body     template.HTML
+	Lists    []siteList
+}
+
+// sitePageTemplate renders every page of an HTML export. Pages are
+// standalone: the stylesheet is included in each.
+var sitePageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.5; color: #222; max-width: 50rem; margin: 0 auto; padding: 1rem 1.5rem 3rem; }
+nav { font-size: 0.9rem; margin-bottom: 1.5rem; }
+a { color: #0b6e4f; }
+.details { color: #666; font-size: 0.9rem; margin-bottom: 2rem; }
+.details span + span::before { content: " · "; }
+.note img { max-width: 100%; height: auto; }
+.note table { border-collapse: collapse; }
+.note td, .note th { border: 1px solid #ccc; padding: 0.25rem 0.5rem; }
+.note pre { background: #f5f5f5; padding: 0.75rem; overflow-x: auto; }
+.count { color: #666; }
+</style>
+</head>
+<body>
+<nav><a href="{{.Root}}index.html">Index</a>{{with .Notebook}} › <a href="{{.Href}}">{{.Name}}</a>{{end}}</nav>
+<h1>{{.Title}}</h1>
+{{- if or .Tags .Created .Updated .Source}}
+<div class="details">
+{{- if .Created}}<span>Created {{.Created}}</span>{{end}}
+{{- if .Updated}}<span>Updated {{.Updated}}</span>{{end}}
+{{- if .Tags}}<span>Tags: {{range $i, $t := .Tags}}{{if $i}}, {{end}}<a href="{{$t.Href}}">{{$t.Name}}</a>{{end}}</span>{{end}}
+{{- if .Source}}<span><a href="{{.Source}}">Source</a></span>{{end}}
+</div>
+{{- end}}
+{{- if .Body}}
+<div class="note">
+{{.Body}}
+</div>
+{{- end}}
+{{- range .Lists}}
+<h2>{{.Heading}}</h2>
+<ul>
+{{- range .Links}}
+<li><a href="{{.Href}}">{{.Name}}</a>{{if .Count}} <span class="count">({{.Count}})</span>{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+// siteNote is what the site keeps about each written note for its indexes.
+type siteNote struct {
+	title    string
+	page     string
+	notebook string
+	tags     []string
+}
+
+// htmlSite lays out an HTML export: index.html, a page per note in notes/
+// with its attachments in notes/<page>_files/, and index pages per notebook
+// in notebooks/ and per tag in tags/.
+type htmlSite struct {
+	dir       string
+	notebooks map[string]*edam.Notebook
+	// pages holds the slug of each note's page.
+	pages map[edam.GUID]string
+	// written lists the notes written so far, for the index pages.
+	written []siteNote
+}
+
+// newHTMLSite plans the page of each note. Slugs are handed out oldest note
+// first, so exporting the same notes again gives the same pages.
+func newHTMLSite(dir string, notes []*edam.NoteMetadata, notebooks []*edam.Notebook) *htmlSite {
+	s := &htmlSite{dir: dir, notebooks: map[string]*edam.Notebook{}, pages: map[edam.GUID]string{}}
+	for _, nb := range notebooks {
+		s.notebooks[string(nb.GetGUID())] = nb
+	}
+	sorted := append([]*edam.NoteMetadata(nil), notes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].GetCreated() != sorted[j].GetCreated() {
+			return sorted[i].GetCreated() < sorted[j].GetCreated()
+		}
+		return sorted[i].GetGUID() < sorted[j].GetGUID()
+	})
+	slugs := siteSlugs{}
+	for _, note := range sorted {
+		s.pages[note.GetGUID()] = slugs.unique(note.GetTitle())
+	}
+	return s
+}
+
+// noteLink returns the page for a link to an exported note, relative to
+// the notes/ folder.
+func (s *htmlSite) noteLink(href string) (string, bool) {
+	guid, ok := noteLinkGUID(href)
+	if !ok {
+		return "", false
+	}
+	slug, ok := s.pages[guid]
+	return slug + ".html", ok
+}
+
+// writePage renders a page to a file in the site.
+func (s *htmlSite) writePage(rel string, page *sitePage) error {
+	var buf bytes.Buffer
+	if err := sitePageTemplate.Execute(&buf, page); err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	return writeExportFile(path, func(w io.Writer) error {
+		_, err := w.Write(buf.Bytes())
+		return err
+	})
+}
+
+// siteDate formats a timestamp for a page.
+func siteDate(ts edam.Timestamp) string {
+	return time.UnixMilli(int64(ts)).UTC().Format("2006-01-02")
+}
+
+// writeNote writes a note's page and attachments. Notebook and tag pages are
+// linked by slug; writeIndexes hands out the same slugs.
+func (s *htmlSite) writeNote(note *edam.Note, tagNames []string, notebookSlugs, tagSlugs map[string]string) error {
+	slug := s.pages[note.GetGUID()]
+	notesDir := filepath.Join(s.dir, "notes")
+	targets, err := saveAttachments(notesDir, slug+"_files", note)
+	if err != nil {
+		return err
+	}
+	body, err := enmlToHTML(note.GetContent(), note.GetResources(), exportLinks{
+		media: func(hash string) string { return targets[hash] },
+		note:  s.noteLink,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render note %s as HTML: %w", note.GetGUID(), err)
+	}
+
+	page := &sitePage{Title: note.GetTitle(), Root: "../", Body: template.HTML(body)}
+	if nb, ok := s.notebooks[note.GetNotebookGuid()]; ok {
+		page.Notebook = &siteLink{Name: nb.GetName(), Href: "../notebooks/" + siteURL(notebookSlugs[note.GetNotebookGuid()]) + ".html"}
+	}
+	for _, tag := range tagNames {
+		page.Tags = append(page.Tags, siteLink{Name: tag, Href: "../tags/" + siteURL(tagSlugs[tag]) + ".html"})
+	}
+	if note.Created != nil {
+		page.Created = siteDate(note.GetCreated())
+	}
+	if note.Updated != nil {
+		page.Updated = siteDate(note.GetUpdated())
+	}
+	if note.GetAttributes() != nil && !unsafeURL(note.GetAttributes().GetSourceURL()) {
+		page.Source = note.GetAttributes().GetSourceURL()
+	}
+	if err := s.writePage("notes/"+slug+".html", page); err != nil {
+		return err
+	}
+	s.written = append(s.written, siteNote{title: note.GetTitle(), page: slug + ".html", notebook: note.GetNotebookGuid(), tags: tagNames})
+	return nil
+}
+
+// noteLinks returns links to the written notes matching keep, sorted by
+// title, from a page one folder down.
+func (s *htmlSite) noteLinks(keep func(siteNote) bool) []siteLink {
+	var links []siteLink
+	for _, n := range s.written {
+		if keep(n) {
+			links = append(links, siteLink{Name: n.title, Href: "../notes/" + siteURL(n.page)})
+		}
+	}
+	sort.SliceStable(links, func(i, j int) bool { return strings.ToLower(links[i].Name) < strings.ToLower(links[j].Name) })
+	return links
+}
+
+// writeIndexes writes a page per notebook and per tag listing their notes,
+// and index.html listing the notebooks, grouped by stack, and the tags.
+func (s *htmlSite) writeIndexes(notebookSlugs, tagSlugs map[string]string) error {
+	stacks := map[string][]siteLink{}
+	for guid, slug := range notebookSlugs {
+		nb := s.notebooks[guid]
+		links := s.noteLinks(func(n siteNote) bool { return n.notebook == guid })
+		if err := s.writePage("notebooks/"+slug+".html", &sitePage{Title: nb.GetName(), Root: "../", Lists: []siteList{{Heading: "Notes", Links: links}}}); err != nil {
+			return err
+		}
+		stacks[nb.GetStack()] = append(stacks[nb.GetStack()], siteLink{Name: nb.GetName(), Href: "notebooks/" + siteURL(slug) + ".html", Count: len(links)})
+	}
+	var tags []siteLink
+	for name, slug := range tagSlugs {
+		links := s.noteLinks(func(n siteNote) bool {
+			for _, t := range n.tags {
+				if t == name {
+					return true
+				}
+			}
+			return false
+		})
+		if err := s.writePage("tags/"+slug+".html", &sitePage{Title: name, Root: "../", Lists: []siteList{{Heading: "Notes", Links: links}}}); err != nil {
+			return err
+		}
+		tags = append(tags, siteLink{Name: name, Href: "tags/" + siteURL(slug) + ".html", Count: len(links)})
+	}
+
+	byName := func(links []siteLink) []siteLink {
+		sort.Slice(links, func(i, j int) bool { return strings.ToLower(links[i].Name) < strings.ToLower(links[j].Name) })
+		return links
+	}
+	index := &sitePage{Title: "Index"}
+	stackNames := make([]string, 0, len(stacks))
+	for stack := range stacks {
+		if stack != "" {
+			stackNames = append(stackNames, stack)
+		}
+	}
+	sort.Strings(stackNames)
+	if links, ok := stacks[""]; ok {
+		index.Lists = append(index.Lists, siteList{Heading: "Notebooks", Links: byName(links)})
+	}
+	for _, stack := range stackNames {
+		index.Lists = append(index.Lists, siteList{Heading: stack, Links: byName(stacks[stack])})
+	}
+	if len(tags) > 0 {
+		index.Lists = append(index.Lists, siteList{Heading: "Tags", Links: byName(tags)})
+	}
+	return s.writePage("index.html", index)
+}
+
+// exportHTML writes the notes as a static HTML site in dir, fetching one note
+// at a time.
+func exportHTML(dir string, ns noteStoreClient, token string, r *nameResolver, notes []*edam.NoteMetadata, summary *exportSummary) error {
+	if err := completeNoteMetadata(ns, token, notes); err != nil {
+		return err
+	}
+	notebooks, err := r.listNotebooks()
+	if err != nil {
+		return err
+	}
+	site := newHTMLSite(dir, notes, notebooks)
+
+	// Notebook and tag slugs are handed out in name order so that they are
+	// stable between exports.
+	notebookSlugs := map[string]string{}
+	slugs := siteSlugs{}
+	sortedNotebooks := append([]*edam.Notebook(nil), notebooks...)
+	sort.SliceStable(sortedNotebooks, func(i, j int) bool { return sortedNotebooks[i].GetName() < sortedNotebooks[j].GetName() })
+	used := map[string]bool{}
+	for _, meta := range notes {
+		used[meta.GetNotebookGuid()] = true
+	}
+	for _, nb := range sortedNotebooks {
+		if used[string(nb.GetGUID())] {
+			notebookSlugs[string(nb.GetGUID())] = slugs.unique(nb.GetName())
+		}
+	}
+	tags, err := r.listTags()
+	if err != nil {
+		return err
+	}
+	tagNames := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tagNames = append(tagNames, tag.GetName())
+	}
+	sort.Strings(tagNames)
+	allTagSlugs := map[string]string{}
+	tagPages := siteSlugs{}
+	for _, name := range tagNames {
+		allTagSlugs[name] = tagPages.unique(name)
+	}
+
+	// Only tags that are on an exported note get a page.
+	tagSlugs := map[string]string{}
+	for _, meta := range notes {
+		note, err := ns.GetNote(context.Background(), token, meta.GetGUID(), true, true, false, false)
+		if err != nil {
+			return fmt.Errorf("failed to get note %s: %w", meta.GetGUID(), formatAPIError(err))
+		}
+		names := r.tagNames(note.GetTagGuids())
+		for _, name := range names {
+			if _, ok := allTagSlugs[name]; !ok {
+				allTagSlugs[name] = tagPages.unique(name)
+			}
+			tagSlugs[name] = allTagSlugs[name]
+		}
+		if err := site.writeNote(note, names, notebookSlugs, tagSlugs); err != nil {
+			return err
+		}
+		summary.Notes++
+		summary.Resources += len(note.GetResources())
+	}
+	return site.writeIndexes(notebookSlugs, tagSlugs)
+}
@@ -0,0 +1,145 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiteSlug(t *testing.T) {
+	assert.Equal(t, "q3-plans-goals", siteSlug("Q3: Plans / Goals"))
+	assert.Equal(t, "café-menu", siteSlug("Café menu!"))
+	assert.Equal(t, "untitled", siteSlug(" ?? "))
+
+	slugs := siteSlugs{}
+	assert.Equal(t, "plan", slugs.unique("Plan"))
+	assert.Equal(t, "plan-2", slugs.unique("plan"))
+}
+
+func TestENMLToHTML(t *testing.T) {
+	mime, fileName := "image/png", "chart.png"
+	resources := []*edam.Resource{{
+		Mime:       &mime,
+		Data:       newResourceData([]byte("a")),
+		Attributes: &edam.ResourceAttributes{FileName: &fileName},
+	}}
+	links := exportLinks{
+		media: func(hash string) string { return "n_files/" + hash[:4] + " x.png" },
+		note: func(href string) (string, bool) {
+			if guid, _ := noteLinkGUID(href); guid == "11111111-1111-1111-1111-111111111111" {
+				return "ideas.html", true
+			}
+			return "", false
+		},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"formatting", `<div style="color:red" onclick="x()">a &amp; <b>b</b><br/></div>`, `<div style="color:red">a &amp; <b>b</b><br></div>`},
+		{"checkboxes", `<div><en-todo checked="true"/>done <en-todo/>open</div>`, `<div><input type="checkbox" disabled checked>done <input type="checkbox" disabled>open</div>`},
+		{"image", `<en-media type="image/png" hash="0cc175b9c0f1b6a831c399e269772661"/>`, `<img src="n_files/0cc1%20x.png" alt="chart.png">`},
+		{"attachment", `<en-media type="application/pdf" hash="0cc175b9c0f1b6a831c399e269772661"/>`, `<a class="attachment" href="n_files/0cc1%20x.png">chart.png</a>`},
+		{"exported note", `<a href="evernote:///view/1/s1/11111111-1111-1111-1111-111111111111/11111111-1111-1111-1111-111111111111/">Ideas</a>`, `<a href="ideas.html">Ideas</a>`},
+		{"other note", `<a href="evernote:///view/1/s1/33333333-3333-3333-3333-333333333333/33333333-3333-3333-3333-333333333333/">old</a>`, `old`},
+		{"web link", `<a href="https://example.com/?a=1&amp;b=2">web</a>`, `<a href="https://example.com/?a=1&amp;b=2">web</a>`},
+		{"unsafe link", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"encrypted", `<div><en-crypt>c2VjcmV0</en-crypt></div>`, `<div><span class="encrypted">[encrypted content]</span></div>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := enmlToHTML(wrapHTMLInENML(tt.content), resources, links)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExportHTML(t *testing.T) {
+	first, second := "11111111-1111-1111-1111-111111111111", "22222222-2222-2222-2222-222222222222"
+	handbook, stack := "Handbook", "Company"
+	tagGUID, tagName := edam.GUID("tag-1"), "On Call"
+	created, updated := edam.Timestamp(1700000000000), edam.Timestamp(1700003600000)
+	mime, fileName := "image/png", "chart.png"
+
+	firstNote := &edam.Note{
+		GUID:         guidRef(first),
+		Title:        strRef("Deploys"),
+		NotebookGuid: strRef("nb-1"),
+		TagGuids:     []edam.GUID{tagGUID},
+		Created:      &created,
+		Updated:      &updated,
+		Content: strRef(wrapHTMLInENML(`<div><en-todo checked="true"/>Tag the release, then read <a href="evernote:///view/1/s1/` + second + `/` + second + `/">Rollbacks</a>.</div>` +
+			`<en-media type="image/png" hash="0cc175b9c0f1b6a831c399e269772661"/>`)),
+		Resources: []*edam.Resource{{
+			Mime:       &mime,
+			Data:       newResourceData([]byte("a")),
+			Attributes: &edam.ResourceAttributes{FileName: &fileName},
+		}},
+	}
+	secondNote := &edam.Note{
+		GUID:         guidRef(second),
+		Title:        strRef("Rollbacks"),
+		NotebookGuid: strRef("nb-1"),
+		Content:      strRef(wrapHTMLInENML(`<div>Undo a deploy.</div>`)),
+	}
+	mock := &mockNoteStore{
+		notebooks:   []*edam.Notebook{{GUID: guidRef("nb-1"), Name: &handbook, Stack: &stack}},
+		tags:        []*edam.Tag{{GUID: &tagGUID, Name: &tagName}},
+		notesByGUID: map[edam.GUID]*edam.Note{edam.GUID(first): firstNote, edam.GUID(second): secondNote},
+	}
+	cleanup := setMockNoteStore(mock)
+	defer cleanup()
+	defer resetExportFlags()
+
+	dir := t.TempDir()
+	exportFormat, exportOut = "html", dir
+	var buf bytes.Buffer
+	exportCmd.SetOut(&buf)
+	require.NoError(t, exportCmd.RunE(exportCmd, []string{first, second}))
+	assert.Equal(t, "Exported 2 note(s) with 1 attachment(s) to "+dir+"\n", buf.String())
+
+	read := func(rel string) string {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	page := read("notes/deploys.html")
+	assert.Contains(t, page, "<title>Deploys</title>")
+	assert.Contains(t, page, `<a href="../index.html">Index</a> › <a href="../notebooks/handbook.html">Handbook</a>`)
+	assert.Contains(t, page, `<span>Created 2023-11-14</span>`)
+	assert.Contains(t, page, `Tags: <a href="../tags/on-call.html">On Call</a>`)
+	assert.Contains(t, page, `<div><input type="checkbox" disabled checked>Tag the release, then read <a href="rollbacks.html">Rollbacks</a>.</div><img src="deploys_files/chart.png" alt="chart.png">`)
+	assert.Equal(t, "a", read("notes/deploys_files/chart.png"))
+	assert.Contains(t, read("notes/rollbacks.html"), "<div>Undo a deploy.</div>")
+
+	notebook := read("notebooks/handbook.html")
+	assert.Contains(t, notebook, "<h1>Handbook</h1>")
+	assert.Contains(t, notebook, "<li><a href=\"../notes/deploys.html\">Deploys</a></li>\n<li><a href=\"../notes/rollbacks.html\">Rollbacks</a></li>")
+
+	tag := read("tags/on-call.html")
+	assert.Contains(t, tag, `<a href="../notes/deploys.html">Deploys</a>`)
+	assert.NotContains(t, tag, "rollbacks.html")
+
+	index := read("index.html")
+	assert.Contains(t, index, "<h2>Company</h2>\n<ul>\n<li><a href=\"notebooks/handbook.html\">Handbook</a> <span class=\"count\">(2)</span></li>")
+	assert.Contains(t, index, "<h2>Tags</h2>\n<ul>\n<li><a href=\"tags/on-call.html\">On Call</a> <span class=\"count\">(1)</span></li>")
+
+	t.Run("requires --out", func(t *testing.T) {
+		exportOut = ""
+		defer func() { exportOut = dir }()
+		err := exportCmd.RunE(exportCmd, []string{first})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--out is required for --format html")
+	})
+}
@@ -0,0 +1,291 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+)
+
+// noteLinkPattern matches links to notes: evernote:///view/... links from the
+// Evernote apps and the note links Evernote shares on the web.
+var noteLinkPattern = regexp.MustCompile(`(?i)^(?:evernote:///view/\d+/s\d+/([0-9a-f-]{36})/|https?://[^/]+/shard/s\d+/nl/\d+/([0-9a-f-]{36}))`)
+
+// noteLinkGUID returns the GUID of the note a link points to.
+func noteLinkGUID(href string) (edam.GUID, bool) {
+	m := noteLinkPattern.FindStringSubmatch(href)
+	if m == nil {
+		return "", false
+	}
+	if m[1] != "" {
+		return edam.GUID(strings.ToLower(m[1])), true
+	}
+	return edam.GUID(strings.ToLower(m[2])), true
+}
+
+// vaultNameReplacer replaces characters that cannot be used in file names or
+// in Obsidian wikilinks.
+var vaultNameReplacer = strings.NewReplacer(
+	"/", "-", `\`, "-", ":", "-", "*", "-", "?", "-", "<", "-", ">", "-", "|", "-",
+	"#", "-", "^", "-", `"`, "'", "[", "(", "]", ")",
+)
+
+// vaultName turns a note, notebook or attachment name into a safe file name.
+func vaultName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, vaultNameReplacer.Replace(name))
+	name = strings.TrimSpace(strings.Trim(strings.TrimSpace(name), "."))
+	const max = 200
+	if len(name) > max {
+		cut := max
+		for cut > 0 && !utf8.RuneStart(name[cut]) {
+			cut--
+		}
+		name = strings.TrimSpace(name[:cut])
+	}
+	if name == "" {
+		return "Untitled"
+	}
+	return name
+}
+
+// markdownVault lays out a Markdown export: a folder per notebook, inside a
+// folder for its stack, with note file names unique across the whole export
+// so that a wikilink resolves to exactly one note.
+type markdownVault struct {
+	dir       string
+	notebooks map[string]*edam.Notebook
+	// files holds the path of each note's file relative to dir.
+	files map[edam.GUID]string
+	// names holds each note's file name without .md, used in wikilinks.
+	names map[edam.GUID]string
+}
+
+// newMarkdownVault plans where each note is written. Names are handed out
+// oldest note first, so exporting the same notes again gives the same files.
+func newMarkdownVault(dir string, notes []*edam.NoteMetadata, notebooks []*edam.Notebook) *markdownVault {
+	v := &markdownVault{
+		dir:       dir,
+		notebooks: map[string]*edam.Notebook{},
+		files:     map[edam.GUID]string{},
+		names:     map[edam.GUID]string{},
+	}
+	for _, nb := range notebooks {
+		v.notebooks[string(nb.GetGUID())] = nb
+	}
+
+	sorted := append([]*edam.NoteMetadata(nil), notes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].GetCreated() != sorted[j].GetCreated() {
+			return sorted[i].GetCreated() < sorted[j].GetCreated()
+		}
+		return sorted[i].GetGUID() < sorted[j].GetGUID()
+	})
+	used := map[string]bool{}
+	for _, note := range sorted {
+		base := vaultName(note.GetTitle())
+		name := base
+		for i := 2; used[strings.ToLower(name)]; i++ {
+			name = fmt.Sprintf("%s (%d)", base, i)
+		}
+		used[strings.ToLower(name)] = true
+		v.names[note.GetGUID()] = name
+		v.files[note.GetGUID()] = filepath.Join(v.folder(note.GetNotebookGuid()), name+".md")
+	}
+	return v
+}
+
+// folder returns the folder for a notebook relative to the vault:
+// Stack/Notebook, or just Notebook when it is not in a stack.
+func (v *markdownVault) folder(notebookGUID string) string {
+	nb, ok := v.notebooks[notebookGUID]
+	if !ok {
+		return vaultName(notebookGUID)
+	}
+	if nb.GetStack() != "" {
+		return filepath.Join(vaultName(nb.GetStack()), vaultName(nb.GetName()))
+	}
+	return vaultName(nb.GetName())
+}
+
+// noteLink returns the wikilink target for a link to an exported note.
+func (v *markdownVault) noteLink(href string) (string, bool) {
+	guid, ok := noteLinkGUID(href)
+	if !ok {
+		return "", false
+	}
+	name, ok := v.names[guid]
+	return name, ok
+}
+
+// writeNote writes a note and its attachments. Attachments are saved under
+// _resources/<note name>/ next to the note and linked relative to it.
+func (v *markdownVault) writeNote(note *edam.Note, tagNames []string) error {
+	rel := v.files[note.GetGUID()]
+	folder := filepath.Join(v.dir, filepath.Dir(rel))
+	resourceDir := filepath.Join("_resources", v.names[note.GetGUID()])
+
+	targets, err := saveAttachments(folder, resourceDir, note)
+	if err != nil {
+		return err
+	}
+
+	body, err := enmlToMarkdownLinks(note.GetContent(), note.GetResources(), exportLinks{
+		media: func(hash string) string { return targets[hash] },
+		note:  v.noteLink,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render note %s as markdown: %w", note.GetGUID(), err)
+	}
+
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", folder, err)
+	}
+	path := filepath.Join(v.dir, rel)
+	text := markdownFrontMatter(note, v.notebooks[note.GetNotebookGuid()], tagNames) + body
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if err := writeExportFile(path, func(w io.Writer) error {
+		_, err := io.WriteString(w, text)
+		return err
+	}); err != nil {
+		return err
+	}
+	if note.Updated != nil {
+		updated := time.UnixMilli(int64(note.GetUpdated()))
+		if err := os.Chtimes(path, updated, updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveAttachments writes a note's attachments into dir/sub, named after their
+// files, and returns the link target of each relative to dir, by hash.
+func saveAttachments(dir, sub string, note *edam.Note) (map[string]string, error) {
+	targets := map[string]string{}
+	used := map[string]bool{}
+	for _, res := range note.GetResources() {
+		if res.GetData() == nil {
+			continue
+		}
+		hash := hex.EncodeToString(res.GetData().GetBodyHash())
+		if _, ok := targets[hash]; ok {
+			continue
+		}
+		base := vaultName(resourceFileName(res, hash, res.GetMime()))
+		ext := filepath.Ext(base)
+		name := base
+		for i := 2; used[strings.ToLower(name)]; i++ {
+			name = fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(base, ext), i, ext)
+		}
+		used[strings.ToLower(name)] = true
+
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", sub, err)
+		}
+		body := res.GetData().GetBody()
+		if err := writeExportFile(filepath.Join(dir, sub, name), func(w io.Writer) error {
+			_, err := w.Write(body)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		targets[hash] = filepath.ToSlash(filepath.Join(sub, name))
+	}
+	return targets, nil
+}
+
+// markdownFrontMatter returns the YAML front matter for an exported note.
+func markdownFrontMatter(note *edam.Note, nb *edam.Notebook, tagNames []string) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	b.WriteString("title: " + strconv.Quote(note.GetTitle()) + "\n")
+	b.WriteString("guid: " + string(note.GetGUID()) + "\n")
+	if nb != nil {
+		b.WriteString("notebook: " + strconv.Quote(nb.GetName()) + "\n")
+		if nb.GetStack() != "" {
+			b.WriteString("stack: " + strconv.Quote(nb.GetStack()) + "\n")
+		}
+	}
+	if len(tagNames) == 0 {
+		b.WriteString("tags: []\n")
+	} else {
+		b.WriteString("tags:\n")
+		for _, tag := range tagNames {
+			b.WriteString("  - " + strconv.Quote(tag) + "\n")
+		}
+	}
+	if note.Created != nil {
+		b.WriteString("created: " + time.UnixMilli(int64(note.GetCreated())).UTC().Format(time.RFC3339) + "\n")
+	}
+	if note.Updated != nil {
+		b.WriteString("updated: " + time.UnixMilli(int64(note.GetUpdated())).UTC().Format(time.RFC3339) + "\n")
+	}
+	if note.GetAttributes() != nil && note.GetAttributes().GetSourceURL() != "" {
+		b.WriteString("source: " + strconv.Quote(note.GetAttributes().GetSourceURL()) + "\n")
+	}
+	b.WriteString("---\n\n")
+	return b.String()
+}
+
+// completeNoteMetadata looks up the title, notebook and creation time of
+// notes given by GUID, so that every note's file name is known before links
+// to it are written.
+func completeNoteMetadata(ns noteStoreClient, token string, notes []*edam.NoteMetadata) error {
+	for i, meta := range notes {
+		if meta.Title != nil {
+			continue
+		}
+		note, err := ns.GetNote(context.Background(), token, meta.GetGUID(), false, false, false, false)
+		if err != nil {
+			return fmt.Errorf("failed to get note %s: %w", meta.GetGUID(), formatAPIError(err))
+		}
+		notes[i] = noteMetadata(note)
+	}
+	return nil
+}
+
+// exportMarkdown writes the notes as a Markdown vault in dir, fetching one
+// note at a time.
+func exportMarkdown(dir string, ns noteStoreClient, token string, r *nameResolver, notes []*edam.NoteMetadata, summary *exportSummary) error {
+	if err := completeNoteMetadata(ns, token, notes); err != nil {
+		return err
+	}
+	notebooks, err := r.listNotebooks()
+	if err != nil {
+		return err
+	}
+
+	vault := newMarkdownVault(dir, notes, notebooks)
+	for _, meta := range notes {
+		note, err := ns.GetNote(context.Background(), token, meta.GetGUID(), true, true, false, false)
+		if err != nil {
+			return fmt.Errorf("failed to get note %s: %w", meta.GetGUID(), formatAPIError(err))
+		}
+		if err := vault.writeNote(note, r.tagNames(note.GetTagGuids())); err != nil {
+			return err
+		}
+		summary.Notes++
+		summary.Resources += len(note.GetResources())
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteLinkGUID(t *testing.T) {
+	tests := []struct {
+		href string
+		want edam.GUID
+		ok   bool
+	}{
+		{"evernote:///view/123/s1/9f0e6c7a-1b2c-4d5e-8f90-0123456789ab/9f0e6c7a-1b2c-4d5e-8f90-0123456789ab/", "9f0e6c7a-1b2c-4d5e-8f90-0123456789ab", true},
+		{"https://www.evernote.com/shard/s1/nl/123/9F0E6C7A-1B2C-4D5E-8F90-0123456789AB/", "9f0e6c7a-1b2c-4d5e-8f90-0123456789ab", true},
+		{"https://example.com/view/123", "", false},
+		{"evernote:///view/123/s1/not-a-guid/", "", false},
+	}
+	for _, tt := range tests {
+		guid, ok := noteLinkGUID(tt.href)
+		assert.Equal(t, tt.ok, ok, tt.href)
+		assert.Equal(t, tt.want, guid, tt.href)
+	}
+}
+
+func TestVaultName(t *testing.T) {
+	assert.Equal(t, "Q3- plans - goals", vaultName("Q3: plans / goals"))
+	assert.Equal(t, "Untitled", vaultName(" .. "))
+	assert.Equal(t, "(draft) 'v2'", vaultName(`[draft] "v2"`))
+	assert.Equal(t, "a-b", vaultName("a#b\x00"))
+	long := vaultName(string(bytes.Repeat([]byte("é"), 150)))
+	assert.Len(t, long, 200)
+}
+
+func TestMarkdownVaultLayout(t *testing.T) {
+	work, stack := "Work", "Projects"
+	notebooks := []*edam.Notebook{
+		{GUID: guidRef("nb-1"), Name: &work, Stack: &stack},
+		{GUID: guidRef("nb-2"), Name: strRef("Home")},
+	}
+	created := func(ts int64) *edam.Timestamp { t := edam.Timestamp(ts); return &t }
+	notes := []*edam.NoteMetadata{
+		{GUID: "n-3", Title: strRef("Plan"), NotebookGuid: strRef("nb-2"), Created: created(3)},
+		{GUID: "n-1", Title: strRef("plan"), NotebookGuid: strRef("nb-1"), Created: created(1)},
+		{GUID: "n-2", Title: strRef("Ideas?"), NotebookGuid: strRef("nb-1"), Created: created(2)},
+	}
+	v := newMarkdownVault("out", notes, notebooks)
+	assert.Equal(t, filepath.Join("Projects", "Work", "plan.md"), v.files["n-1"])
+	assert.Equal(t, filepath.Join("Projects", "Work", "Ideas-.md"), v.files["n-2"])
+	assert.Equal(t, filepath.Join("Home", "Plan (2).md"), v.files["n-3"])
+}
+
+// guidRef returns a pointer to a GUID.
+func guidRef(s string) *edam.GUID {
+	g := edam.GUID(s)
+	return &g
+}
+
+func TestExportMarkdown(t *testing.T) {
+	first, second := "11111111-1111-1111-1111-111111111111", "22222222-2222-2222-2222-222222222222"
+	work, stack := "Work", "Projects"
+	tagGUID, tagName := edam.GUID("tag-1"), "plans"
+	created, updated := edam.Timestamp(1700000000000), edam.Timestamp(1700003600000)
+	sourceURL := "https://example.com/a"
+	mime, fileName := "image/png", "chart.png"
+
+	firstNote := &edam.Note{
+		GUID:         guidRef(first),
+		Title:        strRef("Roadmap: 2026"),
+		NotebookGuid: strRef("nb-1"),
+		TagGuids:     []edam.GUID{tagGUID},
+		Created:      &created,
+		Updated:      &updated,
+		Attributes:   &edam.NoteAttributes{SourceURL: &sourceURL},
+		Content: strRef(wrapHTMLInENML(`<div>See <a href="evernote:///view/1/s1/` + second + `/` + second + `/">Ideas</a> and <a href="evernote:///view/1/s1/33333333-3333-3333-3333-333333333333/33333333-3333-3333-3333-333333333333/">old</a>.</div>` +
+			`<en-media type="image/png" hash="0cc175b9c0f1b6a831c399e269772661"/>`)),
+		Resources: []*edam.Resource{{
+			Mime:       &mime,
+			Data:       newResourceData([]byte("a")),
+			Attributes: &edam.ResourceAttributes{FileName: &fileName},
+		}},
+	}
+	secondNote := &edam.Note{
+		GUID:         guidRef(second),
+		Title:        strRef("Ideas"),
+		NotebookGuid: strRef("nb-2"),
+		Content:      strRef(wrapHTMLInENML(`<div>Back to <a href="evernote:///view/1/s1/` + first + `/` + first + `/">the roadmap</a></div>`)),
+	}
+	mock := &mockNoteStore{
+		notebooks:   []*edam.Notebook{{GUID: guidRef("nb-1"), Name: &work, Stack: &stack}, {GUID: guidRef("nb-2"), Name: strRef("Home")}},
+		tags:        []*edam.Tag{{GUID: &tagGUID, Name: &tagName}},
+		notesByGUID: map[edam.GUID]*edam.Note{edam.GUID(first): firstNote, edam.GUID(second): secondNote},
+	}
+	cleanup := setMockNoteStore(mock)
+	defer cleanup()
+	defer resetExportFlags()
+
+	dir := t.TempDir()
+	exportFormat, exportOut = "markdown", dir
+	var buf bytes.Buffer
+	exportCmd.SetOut(&buf)
+	require.NoError(t, exportCmd.RunE(exportCmd, []string{first, second}))
+	assert.Equal(t, "Exported 2 note(s) with 1 attachment(s) to "+dir+"\n", buf.String())
+
+	path := filepath.Join(dir, "Projects", "Work", "Roadmap- 2026.md")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `---
+title: "Roadmap: 2026"
+guid: 11111111-1111-1111-1111-111111111111
+notebook: "Work"
+stack: "Projects"
+tags:
+  - "plans"
+created: 2023-11-14T22:13:20Z
+updated: 2023-11-14T23:13:20Z
+source: "https://example.com/a"
+---
+
+See [[Ideas]] and [old](evernote:///view/1/s1/33333333-3333-3333-3333-333333333333/33333333-3333-3333-3333-333333333333/).
+
+![chart.png](_resources/Roadmap-%202026/chart.png)
+`, string(data))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, time.UnixMilli(int64(updated)), info.ModTime())
+
+	attachment, err := os.ReadFile(filepath.Join(dir, "Projects", "Work", "_resources", "Roadmap- 2026", "chart.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(attachment))
+
+	data, err = os.ReadFile(filepath.Join(dir, "Home", "Ideas.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "notebook: \"Home\"\ntags: []\n")
+	assert.Contains(t, string(data), "Back to [[Roadmap- 2026|the roadmap]]")
+
+	t.Run("requires --out", func(t *testing.T) {
+		exportOut = ""
+		defer func() { exportOut = dir }()
+		err := exportCmd.RunE(exportCmd, []string{first})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--out is required for --format markdown")
+	})
+}
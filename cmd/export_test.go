@@ -0,0 +1,162 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetExportFlags restores the export flags after a test.
+func resetExportFlags() {
+	exportFormat, exportOut, exportNotebook, exportQuery = "enex", "", "", ""
+	jsonFlag = false
+}
+
+// exportTestStore returns a mock with two notes in one notebook.
+func exportTestStore() *mockNoteStore {
+	nbGUID, nbName := edam.GUID("nb-1"), "Recipes"
+	tagGUID, tagName := edam.GUID("tag-1"), "dinner"
+	first, second := enexTestNote(), enexTestNote()
+	g1, g2, title2 := edam.GUID("n-1"), edam.GUID("n-2"), "Second"
+	first.GUID, first.TagGuids = &g1, []edam.GUID{tagGUID}
+	second.GUID, second.Title, second.Resources = &g2, &title2, nil
+	return &mockNoteStore{
+		notebooks:   []*edam.Notebook{{GUID: &nbGUID, Name: &nbName}},
+		tags:        []*edam.Tag{{GUID: &tagGUID, Name: &tagName}},
+		notes:       &edam.NotesMetadataList{TotalNotes: 2, Notes: []*edam.NoteMetadata{{GUID: g1}, {GUID: g2}}},
+		notesByGUID: map[edam.GUID]*edam.Note{g1: first, g2: second},
+	}
+}
+
+func TestExportCommand(t *testing.T) {
+	defer setTimeNow(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))()
+
+	t.Run("notebook to file", func(t *testing.T) {
+		mock := exportTestStore()
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+		defer resetExportFlags()
+
+		exportOut = filepath.Join(t.TempDir(), "recipes.enex")
+		exportNotebook = "recipes"
+
+		var buf bytes.Buffer
+		exportCmd.SetOut(&buf)
+		require.NoError(t, exportCmd.RunE(exportCmd, nil))
+		assert.Equal(t, fmt.Sprintf("Exported 2 note(s) with 1 attachment(s) to %s\n", exportOut), buf.String())
+		assert.Equal(t, edam.GUID("nb-1"), mock.filter.GetNotebookGuid())
+
+		data, err := os.ReadFile(exportOut)
+		require.NoError(t, err)
+		enex := string(data)
+		assert.Equal(t, 2, strings.Count(enex, "<note>"))
+		assert.Contains(t, enex, "<tag>dinner</tag>")
+		assert.Contains(t, enex, "<title>Second</title>")
+		assert.True(t, strings.HasSuffix(enex, "</en-export>\n"))
+
+		entries, err := os.ReadDir(filepath.Dir(exportOut))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "no temporary files are left behind")
+	})
+
+	t.Run("GUIDs to stdout", func(t *testing.T) {
+		mock := exportTestStore()
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+		defer resetExportFlags()
+
+		var out, errOut bytes.Buffer
+		exportCmd.SetOut(&out)
+		exportCmd.SetErr(&errOut)
+		require.NoError(t, exportCmd.RunE(exportCmd, []string{"n-2"}))
+		assert.Equal(t, 1, strings.Count(out.String(), "<note>"))
+		assert.Equal(t, "Exported 1 note(s) with 0 attachment(s).\n", errOut.String())
+	})
+
+	t.Run("query with JSON summary", func(t *testing.T) {
+		mock := exportTestStore()
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+		defer resetExportFlags()
+
+		exportOut = filepath.Join(t.TempDir(), "out.enex")
+		exportQuery = "tag:dinner"
+		jsonFlag = true
+
+		var buf bytes.Buffer
+		exportCmd.SetOut(&buf)
+		require.NoError(t, exportCmd.RunE(exportCmd, nil))
+		assert.Equal(t, "tag:dinner", mock.filter.GetWords())
+
+		var summary exportSummary
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &summary))
+		assert.Equal(t, exportSummary{Format: "enex", Out: exportOut, Notes: 2, Resources: 1}, summary)
+	})
+
+	t.Run("failed export leaves no file", func(t *testing.T) {
+		mock := exportTestStore()
+		mock.notesByGUID = nil
+		mock.gotNotes = nil
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+		defer resetExportFlags()
+
+		exportOut = filepath.Join(t.TempDir(), "out.enex")
+		mock.err = fmt.Errorf("rate limited")
+		err := exportCmd.RunE(exportCmd, []string{"n-1"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get note n-1: rate limited")
+		assert.NoFileExists(t, exportOut)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		cleanup := setMockNoteStore(exportTestStore())
+		defer cleanup()
+		defer resetExportFlags()
+
+		err := exportCmd.RunE(exportCmd, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "give note GUIDs, --notebook or --query")
+
+		exportNotebook = "recipes"
+		err = exportCmd.RunE(exportCmd, []string{"n-1"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "GUID arguments cannot be used with --notebook")
+
+		exportFormat = "pdf"
+		err = exportCmd.RunE(exportCmd, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid --format "pdf"`)
+	})
+}
+
+func TestExportCmdConfiguration(t *testing.T) {
+	assert.Equal(t, "export [guid...]", exportCmd.Use)
+	assert.Equal(t, "Export notes to ENEX, Markdown or HTML", exportCmd.Short)
+	assert.NotNil(t, exportCmd.RunE)
+	for _, name := range []string{"format", "out", "notebook", "query"} {
+		assert.NotNil(t, exportCmd.Flags().Lookup(name), name)
+	}
+}
+
+func TestExportCmdRegistration(t *testing.T) {
+	found := false
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "export" {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "export command should be registered")
+}
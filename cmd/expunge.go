@@ -0,0 +1,77 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/spf13/cobra"
+)
+
+var (
+	expungeQuery string
+	expungeYes   bool
+)
+
+// confirm asks a yes/no question on the command's input and reports whether
+// the answer was yes.
+func confirm(cmd *cobra.Command, prompt string) bool {
+	fmt.Fprintf(cmd.OutOrStdout(), "%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// expungeCmd permanently deletes notes.
+var expungeCmd = &cobra.Command{
+	Use:   "expunge [guid...]",
+	Short: "Permanently delete notes",
+	Long: `Permanently delete one or more notes. This cannot be undone, so you are
+asked to confirm first unless --yes is given. Evernote only allows this with
+a full-access API key.
+
+Use --query to expunge every trashed note matching a search. Pass - to read
+GUIDs from stdin, one per line; --yes is required in that case because stdin
+is not available for the prompt.
+
+Examples:
+  evernote-cli expunge <guid>
+  evernote-cli expunge --query "tag:scratch" --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 && args[0] == "-" && !expungeYes {
+			return fmt.Errorf("--yes is required when reading GUIDs from stdin")
+		}
+
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		guids, err := noteGUIDsFromInput(cmd, ns, token, args, expungeQuery, true)
+		if err != nil {
+			return err
+		}
+
+		if len(guids) > 0 && !expungeYes {
+			if !confirm(cmd, fmt.Sprintf("Permanently delete %d note(s)? This cannot be undone.", len(guids))) {
+				fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+				return nil
+			}
+		}
+
+		return applyToNotes(cmd, guids, "Expunged", "expunge", func(guid edam.GUID) error {
+			_, err := ns.ExpungeNote(context.Background(), token, guid)
+			return err
+		})
+	},
+}
+
+func init() {
+	expungeCmd.Flags().StringVar(&expungeQuery, "query", "", "expunge every trashed note matching this search query")
+	expungeCmd.Flags().BoolVarP(&expungeYes, "yes", "y", false, "do not ask for confirmation")
+	rootCmd.AddCommand(expungeCmd)
+}
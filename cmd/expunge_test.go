@@ -0,0 +1,82 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpungeCommand(t *testing.T) {
+	t.Run("confirmed", func(t *testing.T) {
+		mock := &mockNoteStore{}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		expungeCmd.SetOut(&buf)
+		expungeCmd.SetIn(strings.NewReader("y\n"))
+		defer expungeCmd.SetIn(nil)
+		err := expungeCmd.RunE(expungeCmd, []string{"note-1"})
+		require.NoError(t, err)
+
+		assert.Contains(t, buf.String(), "Permanently delete 1 note(s)?")
+		assert.Contains(t, buf.String(), "Expunged: note-1")
+		assert.Equal(t, []edam.GUID{"note-1"}, mock.expunged)
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		mock := &mockNoteStore{}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		expungeCmd.SetOut(&buf)
+		expungeCmd.SetIn(strings.NewReader("\n"))
+		defer expungeCmd.SetIn(nil)
+		err := expungeCmd.RunE(expungeCmd, []string{"note-1"})
+		require.NoError(t, err)
+
+		assert.Contains(t, buf.String(), "Aborted.")
+		assert.Nil(t, mock.expunged)
+	})
+
+	t.Run("query with --yes", func(t *testing.T) {
+		mock := &mockNoteStore{
+			notes: &edam.NotesMetadataList{TotalNotes: 2, Notes: []*edam.NoteMetadata{{GUID: "note-1"}, {GUID: "note-2"}}},
+		}
+		defer setMockNoteStore(mock)()
+
+		expungeQuery = "tag:scratch"
+		expungeYes = true
+		defer func() { expungeQuery = ""; expungeYes = false }()
+
+		var buf bytes.Buffer
+		expungeCmd.SetOut(&buf)
+		require.NoError(t, expungeCmd.RunE(expungeCmd, nil))
+		assert.True(t, mock.filter.GetInactive())
+		assert.NotContains(t, buf.String(), "Permanently delete")
+		assert.Equal(t, []edam.GUID{"note-1", "note-2"}, mock.expunged)
+	})
+
+	t.Run("stdin requires --yes", func(t *testing.T) {
+		err := expungeCmd.RunE(expungeCmd, []string{"-"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--yes is required")
+	})
+}
+
+func TestExpungeCmdRegistration(t *testing.T) {
+	found := false
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "expunge" {
+			found = true
+			assert.Equal(t, "Permanently delete notes", c.Short)
+			break
+		}
+	}
+	assert.True(t, found, "expunge command should be registered")
+}
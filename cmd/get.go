@@ -14,6 +14,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var getFormat string
+
 // stripENML removes ENML/XML tags and returns plain text content.
 func stripENML(content string) string {
 	// Remove XML declaration and DOCTYPE
@@ -32,12 +34,41 @@ func stripENML(content string) string {
 	return content
 }
 
+// renderNoteBody returns the note content in the requested output format.
+func renderNoteBody(note *edam.Note, format string) (string, error) {
+	switch format {
+	case "markdown":
+		md, err := enmlToMarkdown(note.GetContent(), note.GetResources())
+		if err != nil {
+			return "", fmt.Errorf("failed to render note as markdown: %w", err)
+		}
+		return md, nil
+	case "enml":
+		return note.GetContent(), nil
+	default:
+		return stripENML(note.GetContent()), nil
+	}
+}
+
 // getCmd retrieves a single note by its GUID.
 var getCmd = &cobra.Command{
 	Use:   "get [guid]",
 	Short: "Get a note by GUID",
-	Args:  cobra.ExactArgs(1),
+	Long: `Get a note by GUID and print its metadata and content.
+
+The content is printed as plain text by default. Use --format markdown to
+keep lists, headings, tables, links, checkboxes and attachment references,
+or --format enml to print the raw note content.
+
+Examples:
+  evernote-cli get <guid>
+  evernote-cli get <guid> --format markdown > note.md`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if getFormat != "text" && getFormat != "markdown" && getFormat != "enml" {
+			return fmt.Errorf("invalid --format %q (use text, markdown or enml)", getFormat)
+		}
+
 		ns, token, err := getNoteStoreFunc()
 		if err != nil {
 			return err
@@ -49,27 +80,24 @@ var getCmd = &cobra.Command{
 			return fmt.Errorf("failed to get note: %w", formatAPIError(err))
 		}
 
+		resolver := newNameResolver(ns, token)
 		if jsonFlag {
 			enc := json.NewEncoder(cmd.OutOrStdout())
 			enc.SetIndent("", "  ")
-			return enc.Encode(note)
+			return enc.Encode(resolver.nameNote(note))
 		}
 
 		fmt.Fprintf(cmd.OutOrStdout(), "Title: %s\n", note.GetTitle())
 		fmt.Fprintf(cmd.OutOrStdout(), "GUID:  %s\n", note.GetGUID())
 
 		if note.GetNotebookGuid() != "" {
-			fmt.Fprintf(cmd.OutOrStdout(), "Notebook: %s\n", note.GetNotebookGuid())
+			fmt.Fprintf(cmd.OutOrStdout(), "Notebook: %s\n", resolver.notebookName(note.GetNotebookGuid()))
 		}
 
 		if len(note.GetTagNames()) > 0 {
 			fmt.Fprintf(cmd.OutOrStdout(), "Tags: %s\n", strings.Join(note.GetTagNames(), ", "))
 		} else if len(note.GetTagGuids()) > 0 {
-			tagGUIDs := make([]string, len(note.GetTagGuids()))
-			for i, g := range note.GetTagGuids() {
-				tagGUIDs[i] = string(g)
-			}
-			fmt.Fprintf(cmd.OutOrStdout(), "Tag GUIDs: %s\n", strings.Join(tagGUIDs, ", "))
+			fmt.Fprintf(cmd.OutOrStdout(), "Tags: %s\n", strings.Join(resolver.tagNames(note.GetTagGuids()), ", "))
 		}
 
 		if note.GetCreated() != 0 {
@@ -94,7 +122,11 @@ var getCmd = &cobra.Command{
 		}
 
 		if note.GetContent() != "" {
-			fmt.Fprintf(cmd.OutOrStdout(), "\n%s\n", stripENML(note.GetContent()))
+			body, err := renderNoteBody(note, getFormat)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "\n%s\n", body)
 		}
 
 		return nil
@@ -102,5 +134,6 @@ var getCmd = &cobra.Command{
 }
 
 func init() {
+	getCmd.Flags().StringVar(&getFormat, "format", "text", "content output format: text, markdown or enml")
 	rootCmd.AddCommand(getCmd)
 }
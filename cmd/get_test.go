@@ -4,6 +4,7 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -49,6 +50,48 @@ func TestGetCommand(t *testing.T) {
 		assert.Contains(t, output, "Updated:")
 	})
 
+	t.Run("shows notebook and tag names", func(t *testing.T) {
+		title := "Named"
+		guid := edam.GUID("note-1")
+		nbGuid := "nb-1"
+		nbName, nbGUID := "Work Journal", edam.GUID("nb-1")
+		tagName, tagGUID := "urgent", edam.GUID("tag-1")
+
+		mock := &mockNoteStore{
+			gotNote: &edam.Note{
+				Title:        &title,
+				GUID:         &guid,
+				NotebookGuid: &nbGuid,
+				TagGuids:     []edam.GUID{"tag-1"},
+			},
+			notebooks: []*edam.Notebook{{Name: &nbName, GUID: &nbGUID}},
+			tags:      []*edam.Tag{{Name: &tagName, GUID: &tagGUID}},
+		}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+
+		var buf bytes.Buffer
+		getCmd.SetOut(&buf)
+		jsonFlag = false
+		err := getCmd.RunE(getCmd, []string{"note-1"})
+		require.NoError(t, err)
+
+		output := buf.String()
+		assert.Contains(t, output, "Notebook: Work Journal")
+		assert.Contains(t, output, "Tags: urgent")
+		assert.NotContains(t, output, "Tag GUIDs")
+
+		buf.Reset()
+		jsonFlag = true
+		defer func() { jsonFlag = false }()
+		require.NoError(t, getCmd.RunE(getCmd, []string{"note-1"}))
+		var got map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		assert.Equal(t, "nb-1", got["notebookGuid"])
+		assert.Equal(t, "Work Journal", got["notebookName"])
+		assert.Equal(t, []interface{}{"urgent"}, got["tagNames"])
+	})
+
 	t.Run("get with JSON output", func(t *testing.T) {
 		title := "JSON Note"
 		guid := edam.GUID("note-json-1")
@@ -98,6 +141,45 @@ func TestGetCommand(t *testing.T) {
 		assert.Contains(t, output, "Tags: Work, Important")
 	})
 
+	t.Run("get as markdown", func(t *testing.T) {
+		title := "Checklist"
+		guid := edam.GUID("note-md")
+		content := wrapHTMLInENML(`<h1>Tasks</h1><div><en-todo checked="true"/>Ship it</div><ul><li>Item</li></ul>`)
+
+		mock := &mockNoteStore{
+			gotNote: &edam.Note{
+				Title:   &title,
+				GUID:    &guid,
+				Content: &content,
+			},
+		}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+
+		getFormat = "markdown"
+		defer func() { getFormat = "text" }()
+
+		var buf bytes.Buffer
+		getCmd.SetOut(&buf)
+		jsonFlag = false
+		err := getCmd.RunE(getCmd, []string{"note-md"})
+		require.NoError(t, err)
+
+		output := buf.String()
+		assert.Contains(t, output, "# Tasks")
+		assert.Contains(t, output, "- [x] Ship it")
+		assert.Contains(t, output, "- Item")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		getFormat = "pdf"
+		defer func() { getFormat = "text" }()
+
+		err := getCmd.RunE(getCmd, []string{"note-md"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid --format")
+	})
+
 	t.Run("API error", func(t *testing.T) {
 		mock := &mockNoteStore{
 			gotNote: &edam.Note{},
@@ -0,0 +1,324 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFrom     string
+	importNotebook string
+	importTags     []string
+	importDryRun   bool
+	importRestart  bool
+	importSanitize bool
+)
+
+// importSummary is the JSON output of the import command.
+type importSummary struct {
+	File      string `json:"file"`
+	DryRun    bool   `json:"dry_run"`
+	Notes     int    `json:"notes"`
+	Resources int    `json:"resources"`
+	Skipped   int    `json:"skipped"`
+}
+
+// importLog records which notes of an import file have been created, so an
+// interrupted import can continue where it stopped. Logs are kept under the
+// data directory, named by a hash of the file's path, size and modification
+// time and of the target notebook and tags, and hold one line per created
+// note: its position in the file and its new GUID.
+type importLog struct {
+	path string
+	done map[int]edam.GUID
+	f    *os.File
+}
+
+// openImportLog loads the log for importing the file at path into a
+// notebook with tags. A changed file, or another notebook or set of tags,
+// starts a new log, so notes are never skipped for having been imported
+// somewhere else.
+func openImportLog(path, notebookGUID string, tags []string) (*importLog, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = strings.ToLower(tag)
+	}
+	sort.Strings(names)
+
+	dir, err := profileDataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00%d\x00%s\x00%s", abs, info.Size(), info.ModTime().UnixNano(), notebookGUID, strings.Join(names, "\x00"))
+	l := &importLog{
+		path: filepath.Join(dir, "imports", hex.EncodeToString(h.Sum(nil)[:16])+".log"),
+		done: map[int]edam.GUID{},
+	}
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import log: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if n, err := strconv.Atoi(fields[0]); err == nil {
+			l.done[n] = edam.GUID(strings.Join(fields[1:], ""))
+		}
+	}
+	return l, nil
+}
+
+// reset forgets every recorded note.
+func (l *importLog) reset() error {
+	l.done = map[int]edam.GUID{}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove import log: %w", err)
+	}
+	return nil
+}
+
+// record notes that the n-th note of the file was created as guid. The log
+// is synced after every note so a crash loses nothing.
+func (l *importLog) record(n int, guid edam.GUID) error {
+	if l.f == nil {
+		if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+			return fmt.Errorf("failed to create import log: %w", err)
+		}
+		f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open import log: %w", err)
+		}
+		l.f = f
+	}
+	l.done[n] = guid
+	if _, err := fmt.Fprintf(l.f, "%d %s\n", n, guid); err != nil {
+		return fmt.Errorf("failed to write import log: %w", err)
+	}
+	return l.f.Sync()
+}
+
+// close closes the log file if it was opened.
+func (l *importLog) close() error {
+	if l.f == nil {
+		return nil
+	}
+	return l.f.Close()
+}
+
+// prepareImportedNote fills in what Evernote requires of a note read from an
+// import file and applies the --notebook and --tag flags.
+func prepareImportedNote(note *edam.Note, notebookGUID string, tags []string) error {
+	if note.GetTitle() == "" {
+		title := "Untitled"
+		note.Title = &title
+	}
+	if notebookGUID != "" {
+		note.NotebookGuid = &notebookGUID
+	}
+	for _, tag := range tags {
+		found := false
+		for _, name := range note.TagNames {
+			if strings.EqualFold(name, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			note.TagNames = append(note.TagNames, tag)
+		}
+	}
+
+	content := note.GetContent()
+	if strings.TrimSpace(content) == "" {
+		content = wrapENML("")
+	}
+	content, err := prepareENML(content, note.Resources, importSanitize)
+	if err != nil {
+		return fmt.Errorf("note %q: %w", note.GetTitle(), err)
+	}
+	note.Content = &content
+	return nil
+}
+
+// importCmd creates notes from an export file.
+var importCmd = &cobra.Command{
+	Use:   "import [file.enex | dir]",
+	Short: "Import notes from ENEX or Markdown",
+	Long: `Import notes from an ENEX file, such as one written by Evernote or by
+'evernote-cli export', or from a folder of Markdown files.
+
+ENEX notes are created with their content, attachments, tags, attributes
+and original created and updated times. The file is read one note at a
+time, so very large exports can be imported. Every created note is recorded
+in a log under the data directory; if an import fails part way, running the
+same command again skips the notes that were already created. The log is
+kept for the file's path, size and modification time and for the --notebook
+and --tag given, so importing a changed file, or into another notebook or
+with other tags, starts over. Use --restart to ignore the log and import
+everything again.
+
+--from markdown walks a folder and creates one note per .md file. Each
+folder becomes a notebook (a nested folder's top folder becomes its stack),
+and files at the top go to --notebook or the default notebook. The title,
+tags and created date are taken from YAML front matter, falling back to the
+file name. Local images and linked files inside the folder are uploaded as
+attachments; links to files outside it are left as links. The note each
+file was imported as is recorded in .evernote-import.json at the top of the
+folder, so importing the folder again, from any clone that has the file,
+updates those notes, skipping files that have not changed; --restart sends
+every file again. A file with a guid in its front matter, as written by
+'export --format markdown', updates that note the first time too. Hidden
+files and folders and _resources folders are skipped, and notes are never
+deleted when files are removed.
+
+--dry-run checks every note and shows what would be imported without
+changing anything.
+
+Examples:
+  evernote-cli import notes.enex
+  evernote-cli import recipes.enex --notebook Recipes --tag imported
+  evernote-cli import archive.enex --dry-run
+  evernote-cli import --from markdown runbooks/ --tag runbook`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch importFrom {
+		case "markdown":
+			return importMarkdownTree(cmd, args[0])
+		case "enex":
+		default:
+			return fmt.Errorf("invalid --from %q (use enex or markdown)", importFrom)
+		}
+
+		path := args[0]
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+		var notebookGUID string
+		if importNotebook != "" {
+			nb, err := newNameResolver(ns, token).notebook(importNotebook)
+			if err != nil {
+				return err
+			}
+			notebookGUID = string(nb.GetGUID())
+		}
+
+		log, err := openImportLog(path, notebookGUID, importTags)
+		if err != nil {
+			return err
+		}
+		defer log.close()
+		if importRestart && !importDryRun {
+			if err := log.reset(); err != nil {
+				return err
+			}
+		}
+
+		var progress io.Writer
+		if !jsonFlag {
+			progress = cmd.ErrOrStderr()
+			if importDryRun {
+				progress = cmd.OutOrStdout()
+			}
+		}
+		summary := &importSummary{File: path, DryRun: importDryRun}
+		reader := newENEXReader(f)
+		for n := 1; ; n++ {
+			note, err := reader.next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if _, ok := log.done[n]; ok && !importRestart {
+				summary.Skipped++
+				continue
+			}
+			if err := prepareImportedNote(note, notebookGUID, importTags); err != nil {
+				return err
+			}
+
+			if importDryRun {
+				if progress != nil {
+					fmt.Fprintf(progress, "Would import: %s (%d attachment(s))\n", note.GetTitle(), len(note.Resources))
+				}
+			} else {
+				created, err := ns.CreateNote(context.Background(), token, note)
+				if err != nil {
+					return fmt.Errorf("failed to import note %d (%s): %w\nrun the same command again to continue from this note", n, note.GetTitle(), formatAPIError(err))
+				}
+				if err := log.record(n, created.GetGUID()); err != nil {
+					return err
+				}
+				if progress != nil {
+					fmt.Fprintf(progress, "Imported: %s\n", note.GetTitle())
+				}
+			}
+			summary.Notes++
+			summary.Resources += len(note.Resources)
+		}
+
+		if jsonFlag {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(summary)
+		}
+		verb := "Imported"
+		if importDryRun {
+			verb = "Would import"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %d note(s) with %d attachment(s)", verb, summary.Notes, summary.Resources)
+		if summary.Skipped > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "; skipped %d already imported", summary.Skipped)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), ".")
+		return nil
+	},
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFrom, "from", "enex", "format to import: enex or markdown")
+	importCmd.Flags().StringVar(&importNotebook, "notebook", "", "create the notes in this notebook (name or GUID); for markdown, files at the top of the folder")
+	importCmd.Flags().StringSliceVar(&importTags, "tag", nil, "add these tags to every imported note")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "check the file and show what would be imported")
+	importCmd.Flags().BoolVar(&importRestart, "restart", false, "ignore earlier runs: import every ENEX note again, or send every Markdown file")
+	importCmd.Flags().BoolVar(&importSanitize, "sanitize", false, "strip markup that is not valid ENML instead of failing")
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,535 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/spf13/cobra"
+)
+
+// frontMatter is what the import reads from a Markdown file's YAML front
+// matter. Other keys are ignored.
+type frontMatter struct {
+	Title   string
+	Tags    []string
+	Created *time.Time
+	// GUID is the note the file was exported from, as written by
+	// 'export --format markdown'.
+	GUID edam.GUID
+}
+
+// frontMatterTimeLayouts are the date formats accepted for created.
+var frontMatterTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// yamlScalar returns a YAML scalar without its quotes.
+func yamlScalar(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			return unquoted
+		}
+		return s[1 : len(s)-1]
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+// parseFrontMatter splits YAML front matter from the start of a Markdown
+// document. It understands the simple key: value and list forms that note
+// apps write: quoted and plain scalars, [a, b] lists and "- item" lists.
+func parseFrontMatter(src string) (frontMatter, string, error) {
+	var fm frontMatter
+	src = strings.TrimPrefix(src, "\ufeff")
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return fm, src, nil
+	}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if l := strings.TrimSpace(lines[i]); l == "---" || l == "..." {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return fm, src, nil
+	}
+
+	values := map[string][]string{}
+	key := ""
+	for _, line := range lines[1:end] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			if key != "" {
+				values[key] = append(values[key], yamlScalar(strings.TrimPrefix(trimmed, "-")))
+			}
+			continue
+		}
+		i := strings.Index(line, ":")
+		if i <= 0 || line[0] == ' ' {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(line[:i]))
+		value := strings.TrimSpace(line[i+1:])
+		switch {
+		case value == "":
+			values[key] = nil
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			var items []string
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = yamlScalar(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			values[key] = items
+		default:
+			values[key] = []string{yamlScalar(value)}
+		}
+	}
+
+	if v := values["title"]; len(v) > 0 {
+		fm.Title = v[0]
+	}
+	if v := values["guid"]; len(v) > 0 {
+		fm.GUID = edam.GUID(v[0])
+	}
+	for _, k := range []string{"tags", "tag"} {
+		for _, v := range values[k] {
+			for _, tag := range strings.Split(v, ",") {
+				if tag = strings.TrimPrefix(strings.TrimSpace(tag), "#"); tag != "" {
+					fm.Tags = append(fm.Tags, tag)
+				}
+			}
+		}
+	}
+	if v := values["created"]; len(v) > 0 && v[0] != "" {
+		for _, layout := range frontMatterTimeLayouts {
+			if t, err := time.ParseInLocation(layout, v[0], time.Local); err == nil {
+				fm.Created = &t
+				break
+			}
+		}
+		if fm.Created == nil {
+			return fm, "", fmt.Errorf("invalid created date %q in front matter", v[0])
+		}
+	}
+	return fm, strings.Join(lines[end+1:], "\n"), nil
+}
+
+// markdownImportSummary is the JSON output of importing a Markdown tree.
+type markdownImportSummary struct {
+	Dir       string `json:"dir"`
+	DryRun    bool   `json:"dry_run"`
+	Created   int    `json:"created"`
+	Updated   int    `json:"updated"`
+	Unchanged int    `json:"unchanged"`
+	Notebooks int    `json:"notebooks"`
+	Resources int    `json:"resources"`
+}
+
+// markdownImportEntry is what the import map keeps about one file.
+type markdownImportEntry struct {
+	GUID edam.GUID `json:"guid"`
+	// Hash covers everything sent for the note, including the hashes of its
+	// attachments, so unchanged files can be skipped.
+	Hash string `json:"hash"`
+}
+
+// markdownImportMapName is the file in the root of a Markdown tree that
+// records what it was imported as. Committing it with the tree keeps imports
+// from other clones and fresh checkouts updating the same notes.
+const markdownImportMapName = ".evernote-import.json"
+
+// markdownImportMap records the note each file of a Markdown tree was
+// imported as, so importing the tree again updates those notes instead of
+// creating new ones. The map is kept in the tree itself and keyed by
+// slash-separated paths relative to it.
+type markdownImportMap struct {
+	path  string
+	Files map[string]markdownImportEntry `json:"files"`
+}
+
+// loadMarkdownImportMap loads the map for the tree at dir. Maps written by
+// earlier versions under the data directory, named by a hash of the tree's
+// absolute path, are read when the tree has none yet.
+func loadMarkdownImportMap(dir string) (*markdownImportMap, error) {
+	m := &markdownImportMap{
+		path:  filepath.Join(dir, markdownImportMapName),
+		Files: map[string]markdownImportEntry{},
+	}
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, os.ErrNotExist) {
+		abs, absErr := filepath.Abs(dir)
+		if absErr != nil {
+			return nil, absErr
+		}
+		legacyDir, dirErr := profileDataDir()
+		if dirErr != nil {
+			return nil, dirErr
+		}
+		sum := sha256.Sum256([]byte(abs))
+		data, err = os.ReadFile(filepath.Join(legacyDir, "imports", "markdown-"+hex.EncodeToString(sum[:16])+".json"))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import map: %w", err)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to read import map %s: %w", m.path, err)
+	}
+	if m.Files == nil {
+		m.Files = map[string]markdownImportEntry{}
+	}
+	return m, nil
+}
+
+// save writes the map, replacing the previous one atomically.
+func (m *markdownImportMap) save() error {
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return fmt.Errorf("failed to create import map: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(m.path, data); err != nil {
+		return fmt.Errorf("failed to save import map: %w", err)
+	}
+	return nil
+}
+
+// markdownImporter imports a tree of Markdown files, one note per file.
+type markdownImporter struct {
+	ns       noteStoreClient
+	token    string
+	resolver *nameResolver
+	files    *markdownImportMap
+	summary  *markdownImportSummary
+	progress io.Writer
+	// rootNotebook is the GUID of the notebook for files at the top of the
+	// tree, or "" for the default notebook.
+	rootNotebook string
+	// notebooks caches the GUID of each folder's notebook by name.
+	notebooks map[string]string
+}
+
+// folderNotebook returns the stack and notebook a folder maps to. A file's
+// folder is its notebook, and when that folder is nested, the top folder of
+// the tree is the notebook's stack.
+func folderNotebook(rel string) (stack, name string) {
+	dir := filepath.Dir(filepath.FromSlash(rel))
+	if dir == "." {
+		return "", ""
+	}
+	parts := strings.Split(filepath.ToSlash(dir), "/")
+	name = parts[len(parts)-1]
+	if len(parts) > 1 {
+		stack = parts[0]
+	}
+	return stack, name
+}
+
+// notebook returns the GUID of the named notebook, creating it in the stack
+// when it does not exist yet. In a dry run nothing is created and the GUID
+// is empty.
+func (im *markdownImporter) notebook(stack, name string) (string, error) {
+	if guid, ok := im.notebooks[strings.ToLower(name)]; ok {
+		return guid, nil
+	}
+	notebooks, err := im.resolver.listNotebooks()
+	if err != nil {
+		return "", err
+	}
+	for _, nb := range notebooks {
+		if strings.EqualFold(nb.GetName(), name) {
+			im.notebooks[strings.ToLower(name)] = string(nb.GetGUID())
+			return string(nb.GetGUID()), nil
+		}
+	}
+	if im.summary.DryRun {
+		im.notebooks[strings.ToLower(name)] = ""
+		im.summary.Notebooks++
+		return "", nil
+	}
+
+	notebook := &edam.Notebook{Name: &name}
+	if stack != "" {
+		notebook.Stack = &stack
+	}
+	created, err := im.ns.CreateNotebook(context.Background(), im.token, notebook)
+	if err != nil {
+		return "", fmt.Errorf("failed to create notebook %s: %w", name, formatAPIError(err))
+	}
+	im.notebooks[strings.ToLower(name)] = string(created.GetGUID())
+	im.summary.Notebooks++
+	if im.progress != nil {
+		fmt.Fprintf(im.progress, "Created notebook: %s\n", name)
+	}
+	return string(created.GetGUID()), nil
+}
+
+// buildMarkdownNote converts a Markdown file into a note. Local images and linked
+// files inside the imported tree at root are attached as resources; links to
+// files elsewhere stay plain links, so a file in the tree cannot pull in
+// other files the importing user can read.
+func buildMarkdownNote(path, root string, tags []string) (*edam.Note, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	fm, src, err := parseFrontMatter(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	c := newMarkdownConverter(filepath.Dir(path))
+	c.attachLinks = true
+	c.root = root
+	body := c.convert(src)
+	if c.err != nil {
+		return nil, fmt.Errorf("%s: %w", path, c.err)
+	}
+	content, err := prepareENML(wrapHTMLInENML(body), c.resources, importSanitize)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	title := strings.TrimSpace(fm.Title)
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	note := &edam.Note{Title: &title, Content: &content, Resources: c.resources, TagNames: fm.Tags}
+	for _, tag := range tags {
+		found := false
+		for _, name := range note.TagNames {
+			if strings.EqualFold(name, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			note.TagNames = append(note.TagNames, tag)
+		}
+	}
+	if fm.Created != nil {
+		created := edam.Timestamp(fm.Created.UnixMilli())
+		note.Created = &created
+	}
+	if fm.GUID != "" {
+		guid := fm.GUID
+		note.GUID = &guid
+	}
+	return note, nil
+}
+
+// markdownNoteHash returns a hash of everything sent for a note.
+func markdownNoteHash(note *edam.Note, notebook string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%d\x00%s", note.GetTitle(), note.GetContent(), strings.Join(note.TagNames, "\x01"), note.GetCreated(), notebook)
+	for _, res := range note.Resources {
+		fmt.Fprintf(h, "\x00%x %s", res.GetData().GetBodyHash(), res.GetMime())
+		if res.GetAttributes() != nil {
+			fmt.Fprintf(h, " %s", res.GetAttributes().GetFileName())
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// importFile creates or updates the note for one file.
+func (im *markdownImporter) importFile(path, root, rel string, tags []string) error {
+	note, err := buildMarkdownNote(path, root, tags)
+	if err != nil {
+		return err
+	}
+
+	stack, name := folderNotebook(rel)
+	notebookKey := filepath.ToSlash(filepath.Join(stack, name))
+	if name == "" {
+		notebookKey = im.rootNotebook
+	}
+	entry, known := im.files.Files[rel]
+	if !known && note.GUID != nil {
+		// The front matter names the note the file was exported from
+		entry, known = markdownImportEntry{GUID: note.GetGUID()}, true
+	}
+	note.GUID = nil
+	hash := markdownNoteHash(note, notebookKey)
+	if known && entry.Hash == hash && !importRestart {
+		im.summary.Unchanged++
+		return nil
+	}
+
+	if im.summary.DryRun {
+		if name != "" {
+			if _, err := im.notebook(stack, name); err != nil {
+				return err
+			}
+		}
+		if im.progress != nil {
+			verb := "Would create"
+			if known {
+				verb = "Would update"
+			}
+			fmt.Fprintf(im.progress, "%s: %s (%d attachment(s))\n", verb, rel, len(note.Resources))
+		}
+		if known {
+			im.summary.Updated++
+		} else {
+			im.summary.Created++
+		}
+		im.summary.Resources += len(note.Resources)
+		return nil
+	}
+
+	guid := im.rootNotebook
+	if name != "" {
+		if guid, err = im.notebook(stack, name); err != nil {
+			return err
+		}
+	}
+	if guid != "" {
+		note.NotebookGuid = &guid
+	}
+
+	var saved *edam.Note
+	if known {
+		guid := entry.GUID
+		note.GUID = &guid
+		saved, err = im.ns.UpdateNote(context.Background(), im.token, note)
+		var notFound *edam.EDAMNotFoundException
+		if errors.As(err, &notFound) {
+			// The note was deleted in Evernote, so create it again
+			known, note.GUID = false, nil
+		} else if err != nil {
+			return fmt.Errorf("failed to update note for %s: %w", rel, formatAPIError(err))
+		}
+	}
+	if !known {
+		saved, err = im.ns.CreateNote(context.Background(), im.token, note)
+		if err != nil {
+			return fmt.Errorf("failed to create note for %s: %w", rel, formatAPIError(err))
+		}
+	}
+
+	im.files.Files[rel] = markdownImportEntry{GUID: saved.GetGUID(), Hash: hash}
+	if err := im.files.save(); err != nil {
+		return err
+	}
+	verb := "Created"
+	if known {
+		verb = "Updated"
+		im.summary.Updated++
+	} else {
+		im.summary.Created++
+	}
+	im.summary.Resources += len(note.Resources)
+	if im.progress != nil {
+		fmt.Fprintf(im.progress, "%s: %s\n", verb, rel)
+	}
+	return nil
+}
+
+// run imports every Markdown file under the tree, skipping hidden files and
+// folders and the _resources folders written by the Markdown export.
+func (im *markdownImporter) run(root string, tags []string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root && (strings.HasPrefix(d.Name(), ".") || (d.IsDir() && d.Name() == "_resources")) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		return im.importFile(path, root, filepath.ToSlash(rel), tags)
+	})
+}
+
+// importMarkdownTree runs the import command for --from markdown.
+func importMarkdownTree(cmd *cobra.Command, dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	files, err := loadMarkdownImportMap(dir)
+	if err != nil {
+		return err
+	}
+
+	ns, token, err := getNoteStoreFunc()
+	if err != nil {
+		return err
+	}
+	im := &markdownImporter{
+		ns:        ns,
+		token:     token,
+		resolver:  newNameResolver(ns, token),
+		files:     files,
+		summary:   &markdownImportSummary{Dir: dir, DryRun: importDryRun},
+		notebooks: map[string]string{},
+	}
+	if importNotebook != "" {
+		nb, err := im.resolver.notebook(importNotebook)
+		if err != nil {
+			return err
+		}
+		im.rootNotebook = string(nb.GetGUID())
+	}
+	if !jsonFlag {
+		im.progress = cmd.ErrOrStderr()
+		if importDryRun {
+			im.progress = cmd.OutOrStdout()
+		}
+	}
+	if err := im.run(dir, importTags); err != nil {
+		return err
+	}
+
+	s := im.summary
+	if jsonFlag {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(s)
+	}
+	if importDryRun {
+		fmt.Fprintf(cmd.OutOrStdout(), "Would import %s: %d to create, %d to update, %d unchanged, %d new notebook(s).\n", dir, s.Created, s.Updated, s.Unchanged, s.Notebooks)
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Imported %s: %d created, %d updated, %d unchanged, %d new notebook(s).\n", dir, s.Created, s.Updated, s.Unchanged, s.Notebooks)
+	return nil
+}
@@ -0,0 +1,279 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	t.Run("fields", func(t *testing.T) {
+		src := "---\ntitle: \"Deploy: prod\"\ntags:\n  - ops\n  - 'on call'\ncreated: 2026-01-02T03:04:05Z\nguid: abc-123\nowner: sam # ignored\n---\n# Body\n"
+		fm, body, err := parseFrontMatter(src)
+		require.NoError(t, err)
+		assert.Equal(t, "Deploy: prod", fm.Title)
+		assert.Equal(t, edam.GUID("abc-123"), fm.GUID)
+		assert.Equal(t, []string{"ops", "on call"}, fm.Tags)
+		require.NotNil(t, fm.Created)
+		assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), fm.Created.UTC())
+		assert.Equal(t, "# Body\n", body)
+	})
+
+	t.Run("flow lists and plain dates", func(t *testing.T) {
+		fm, _, err := parseFrontMatter("---\ntags: [a, \"b c\", '#d']\ncreated: 2026-01-02\n---\n")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b c", "d"}, fm.Tags)
+		assert.Equal(t, "2026-01-02", fm.Created.Format("2006-01-02"))
+
+		fm, _, err = parseFrontMatter("---\ntags: x, y # comment\n---\n")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"x", "y"}, fm.Tags)
+	})
+
+	t.Run("no front matter", func(t *testing.T) {
+		for _, src := range []string{"# Title\n", "---\nnot closed\n", ""} {
+			fm, body, err := parseFrontMatter(src)
+			require.NoError(t, err)
+			assert.Equal(t, frontMatter{}, fm)
+			assert.Equal(t, src, body)
+		}
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		_, _, err := parseFrontMatter("---\ncreated: last tuesday\n---\n")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid created date "last tuesday"`)
+	})
+}
+
+func TestFolderNotebook(t *testing.T) {
+	tests := []struct{ rel, stack, name string }{
+		{"readme.md", "", ""},
+		{"Ops/deploy.md", "", "Ops"},
+		{"Runbooks/DB/backup.md", "Runbooks", "DB"},
+		{"Runbooks/DB/Postgres/vacuum.md", "Runbooks", "Postgres"},
+	}
+	for _, tt := range tests {
+		stack, name := folderNotebook(tt.rel)
+		assert.Equal(t, tt.stack, stack, tt.rel)
+		assert.Equal(t, tt.name, name, tt.rel)
+	}
+}
+
+// writeTestTree writes files under dir, creating folders as needed.
+func writeTestTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestImportMarkdown(t *testing.T) {
+	setDataDir(t)
+	opsGUID, opsName, inboxGUID, inboxName := edam.GUID("nb-ops"), "ops", edam.GUID("nb-inbox"), "Inbox"
+	store := &failingCreateStore{mockNoteStore: &mockNoteStore{
+		notebooks: []*edam.Notebook{{GUID: &opsGUID, Name: &opsName}, {GUID: &inboxGUID, Name: &inboxName}},
+	}}
+	cleanup := setFailingCreateStore(store)
+	defer cleanup()
+	defer resetImportFlags()
+
+	dir := t.TempDir()
+	writeTestTree(t, dir, map[string]string{
+		"readme.md":             "Start here.\n",
+		"Ops/deploy.md":         "---\ntitle: Deploy\ntags: [ops]\ncreated: 2026-01-02T03:04:05Z\n---\n![graph](img/graph.png) see [the checklist](check.pdf) and [backup](../Runbooks/DB/backup.md)\n",
+		"Ops/img/graph.png":     "a",
+		"Ops/check.pdf":         "pdf",
+		"Runbooks/DB/backup.md": "# Backups\n",
+		".git/HEAD.md":          "skip",
+		"Ops/_resources/x.md":   "skip",
+		"notes.txt":             "skip",
+	})
+	importFrom, importNotebook, importTags = "markdown", "inbox", []string{"mirror"}
+
+	var out, errOut bytes.Buffer
+	importCmd.SetOut(&out)
+	importCmd.SetErr(&errOut)
+	require.NoError(t, importCmd.RunE(importCmd, []string{dir}))
+	assert.Equal(t, "Imported "+dir+": 3 created, 0 updated, 0 unchanged, 1 new notebook(s).\n", out.String())
+	assert.Equal(t, "Created: Ops/deploy.md\nCreated notebook: DB\nCreated: Runbooks/DB/backup.md\nCreated: readme.md\n", errOut.String())
+
+	require.Len(t, store.createdNotes, 3)
+	deploy := store.createdNotes[0]
+	assert.Equal(t, "Deploy", deploy.GetTitle())
+	assert.Equal(t, "nb-ops", deploy.GetNotebookGuid())
+	assert.Equal(t, []string{"ops", "mirror"}, deploy.TagNames)
+	assert.Equal(t, edam.Timestamp(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).UnixMilli()), deploy.GetCreated())
+	require.Len(t, deploy.Resources, 2)
+	assert.Equal(t, "graph.png", deploy.Resources[0].GetAttributes().GetFileName())
+	assert.Equal(t, "check.pdf", deploy.Resources[1].GetAttributes().GetFileName())
+	assert.Equal(t, 2, strings.Count(deploy.GetContent(), "<en-media"))
+	assert.Contains(t, deploy.GetContent(), `<a href="../Runbooks/DB/backup.md">backup</a>`)
+
+	require.Len(t, store.savedNotebooks, 1)
+	assert.Equal(t, "DB", store.savedNotebooks[0].GetName())
+	assert.Equal(t, "Runbooks", store.savedNotebooks[0].GetStack())
+	assert.Equal(t, "new-notebook-guid", store.createdNotes[1].GetNotebookGuid())
+	assert.Equal(t, "backup", store.createdNotes[1].GetTitle())
+	assert.Equal(t, "nb-inbox", store.createdNotes[2].GetNotebookGuid())
+	assert.Equal(t, "readme", store.createdNotes[2].GetTitle())
+
+	// Unchanged files are skipped on the next run
+	out.Reset()
+	errOut.Reset()
+	require.NoError(t, importCmd.RunE(importCmd, []string{dir}))
+	assert.Equal(t, "Imported "+dir+": 0 created, 0 updated, 3 unchanged, 0 new notebook(s).\n", out.String())
+	assert.Empty(t, errOut.String())
+	assert.Len(t, store.createdNotes, 3)
+
+	// A changed attachment updates the note it was imported as
+	writeTestTree(t, dir, map[string]string{"Ops/img/graph.png": "b", "Ops/new.md": "New"})
+	importDryRun = true
+	out.Reset()
+	require.NoError(t, importCmd.RunE(importCmd, []string{dir}))
+	assert.Equal(t, "Would update: Ops/deploy.md (2 attachment(s))\nWould create: Ops/new.md (0 attachment(s))\n"+
+		"Would import "+dir+": 1 to create, 1 to update, 2 unchanged, 0 new notebook(s).\n", out.String())
+	assert.Len(t, store.createdNotes, 3)
+	assert.Empty(t, store.savedNotes)
+
+	importDryRun = false
+	jsonFlag = true
+	out.Reset()
+	require.NoError(t, importCmd.RunE(importCmd, []string{dir}))
+	var summary markdownImportSummary
+	require.NoError(t, json.Unmarshal(out.Bytes(), &summary))
+	assert.Equal(t, markdownImportSummary{Dir: dir, Created: 1, Updated: 1, Unchanged: 2, Resources: 2}, summary)
+	require.Len(t, store.savedNotes, 1)
+	assert.Equal(t, edam.GUID("guid-1"), store.savedNotes[0].GetGUID())
+	assert.Len(t, store.createdNotes, 4)
+
+	files, err := loadMarkdownImportMap(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Ops/deploy.md", "Ops/new.md", "Runbooks/DB/backup.md", "readme.md"}, sortedKeys(files.Files))
+	assert.Equal(t, edam.GUID("guid-4"), files.Files["Ops/new.md"].GUID)
+	assert.FileExists(t, filepath.Join(dir, ".evernote-import.json"), "the map is kept in the tree")
+
+	// A fresh clone elsewhere, with an empty data directory, is the same import
+	setDataDir(t)
+	clone := filepath.Join(t.TempDir(), "clone")
+	require.NoError(t, os.CopyFS(clone, os.DirFS(dir)))
+	jsonFlag = false
+	out.Reset()
+	require.NoError(t, importCmd.RunE(importCmd, []string{clone}))
+	assert.Equal(t, "Imported "+clone+": 0 created, 0 updated, 4 unchanged, 0 new notebook(s).\n", out.String())
+	assert.Len(t, store.createdNotes, 4)
+}
+
+func TestImportMarkdownFrontMatterGUID(t *testing.T) {
+	setDataDir(t)
+	store := &failingCreateStore{mockNoteStore: &mockNoteStore{}}
+	cleanup := setFailingCreateStore(store)
+	defer cleanup()
+	defer resetImportFlags()
+	importFrom = "markdown"
+
+	// A vault written by 'export --format markdown'
+	dir := t.TempDir()
+	writeTestTree(t, dir, map[string]string{
+		"exported.md": "---\ntitle: Exported\nguid: note-7\n---\nBody\n",
+		"new.md":      "New\n",
+	})
+	var out bytes.Buffer
+	importCmd.SetOut(&out)
+	importCmd.SetErr(&out)
+	require.NoError(t, importCmd.RunE(importCmd, []string{dir}))
+	require.Len(t, store.savedNotes, 1)
+	assert.Equal(t, edam.GUID("note-7"), store.savedNotes[0].GetGUID())
+	assert.Equal(t, "Exported", store.savedNotes[0].GetTitle())
+	require.Len(t, store.createdNotes, 1)
+	assert.Equal(t, "new", store.createdNotes[0].GetTitle())
+}
+
+func TestLoadMarkdownImportMapLegacy(t *testing.T) {
+	data := setDataDir(t)
+	dir := t.TempDir()
+	sum := sha256.Sum256([]byte(dir))
+	legacy := filepath.Join(data, "imports", "markdown-"+hex.EncodeToString(sum[:16])+".json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(legacy), 0700))
+	require.NoError(t, os.WriteFile(legacy, []byte(`{"files":{"a.md":{"guid":"g-1","hash":"h"}}}`), 0600))
+
+	m, err := loadMarkdownImportMap(dir)
+	require.NoError(t, err)
+	assert.Equal(t, edam.GUID("g-1"), m.Files["a.md"].GUID)
+	require.NoError(t, m.save())
+	assert.FileExists(t, filepath.Join(dir, ".evernote-import.json"))
+}
+
+func TestBuildMarkdownNoteStaysInTree(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "repo")
+	secret := filepath.Join(base, "private", "id_rsa")
+	writeTestTree(t, base, map[string]string{
+		"private/id_rsa": "key",
+		"repo/docs/page.md": "[abs](" + secret + ") [url](file://" + secret + ") [up](../../private/id_rsa)" +
+			" [link](leak.txt) ![img](" + secret + ") [ok](../files/ok.txt)\n",
+		"repo/files/ok.txt": "fine",
+	})
+	require.NoError(t, os.Symlink(secret, filepath.Join(root, "docs", "leak.txt")))
+
+	note, err := buildMarkdownNote(filepath.Join(root, "docs", "page.md"), root, nil)
+	require.NoError(t, err)
+	require.Len(t, note.Resources, 1, "only the file inside the tree is attached")
+	assert.Equal(t, "ok.txt", note.Resources[0].GetAttributes().GetFileName())
+	content := note.GetContent()
+	assert.Contains(t, content, `<a href="`+secret+`">abs</a>`)
+	assert.Contains(t, content, `<a href="../../private/id_rsa">up</a>`)
+	assert.Contains(t, content, `<a href="leak.txt">link</a>`)
+	assert.NotContains(t, content, "<img")
+	assert.Equal(t, 1, strings.Count(content, "<en-media"))
+}
+
+// sortedKeys returns the keys of an import map in order.
+func sortedKeys(m map[string]markdownImportEntry) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestImportMarkdownErrors(t *testing.T) {
+	setDataDir(t)
+	cleanup := setMockNoteStore(&mockNoteStore{})
+	defer cleanup()
+	defer resetImportFlags()
+	importFrom = "markdown"
+
+	file := filepath.Join(t.TempDir(), "a.md")
+	require.NoError(t, os.WriteFile(file, []byte("x"), 0644))
+	err := importCmd.RunE(importCmd, []string{file})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a directory")
+
+	dir := t.TempDir()
+	writeTestTree(t, dir, map[string]string{"a.md": "![missing](nope.png)"})
+	err = importCmd.RunE(importCmd, []string{dir})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nope.png")
+
+	importFrom = "pdf"
+	err = importCmd.RunE(importCmd, []string{dir})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid --from "pdf"`)
+}
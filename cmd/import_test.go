@@ -0,0 +1,261 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetImportFlags restores the import flags after a test.
+func resetImportFlags() {
+	importFrom, importNotebook, importTags = "enex", "", nil
+	importDryRun, importRestart, importSanitize = false, false, false
+	jsonFlag = false
+}
+
+// writeTestENEX writes an ENEX file with the given note titles, the first
+// of which has an attachment.
+func writeTestENEX(t *testing.T, titles ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notes.enex")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	enex := newENEXWriter(f, time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+	for i, title := range titles {
+		note := enexTestNote()
+		content := wrapHTMLInENML(`<div>` + title + `</div><en-media type="image/png" hash="0cc175b9c0f1b6a831c399e269772661"/>`)
+		if i > 0 {
+			content, note.Resources = wrapENML(title), nil
+		}
+		note.Title, note.Content = strRef(title), &content
+		require.NoError(t, enex.writeNote(note, []string{"food"}))
+	}
+	require.NoError(t, enex.close())
+	return path
+}
+
+// failingCreateStore is a mock whose CreateNote fails on the given call.
+type failingCreateStore struct {
+	*mockNoteStore
+	calls, failAt int
+}
+
+// CreateNote fails on call failAt and gives every other note a GUID.
+func (s *failingCreateStore) CreateNote(ctx context.Context, token string, note *edam.Note) (*edam.Note, error) {
+	s.calls++
+	if s.calls == s.failAt {
+		return nil, fmt.Errorf("rate limited")
+	}
+	created, _ := s.mockNoteStore.CreateNote(ctx, token, note)
+	guid := edam.GUID(fmt.Sprintf("guid-%d", s.calls))
+	created.GUID = &guid
+	return created, nil
+}
+
+// setFailingCreateStore makes getNoteStoreFunc return store.
+func setFailingCreateStore(store *failingCreateStore) func() {
+	original := getNoteStoreFunc
+	getNoteStoreFunc = func() (noteStoreClient, string, error) { return store, "test-token", nil }
+	return func() { getNoteStoreFunc = original }
+}
+
+func TestImportCommand(t *testing.T) {
+	t.Run("creates notes", func(t *testing.T) {
+		setDataDir(t)
+		nbGUID, nbName := edam.GUID("nb-1"), "Recipes"
+		mock := &mockNoteStore{notebooks: []*edam.Notebook{{GUID: &nbGUID, Name: &nbName}}}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+		defer resetImportFlags()
+
+		path := writeTestENEX(t, "Fish", "Chips")
+		importNotebook = "recipes"
+		importTags = []string{"imported", "FOOD"}
+
+		var out, errOut bytes.Buffer
+		importCmd.SetOut(&out)
+		importCmd.SetErr(&errOut)
+		require.NoError(t, importCmd.RunE(importCmd, []string{path}))
+		assert.Equal(t, "Imported 2 note(s) with 1 attachment(s).\n", out.String())
+		assert.Equal(t, "Imported: Fish\nImported: Chips\n", errOut.String())
+
+		require.Len(t, mock.createdNotes, 2)
+		note := mock.createdNotes[0]
+		assert.Equal(t, "Fish", note.GetTitle())
+		assert.Equal(t, "nb-1", note.GetNotebookGuid())
+		assert.Equal(t, []string{"food", "imported"}, note.TagNames)
+		assert.Equal(t, edam.Timestamp(1700000000000), note.GetCreated())
+		assert.Equal(t, edam.Timestamp(1700003600000), note.GetUpdated())
+		assert.Equal(t, "https://example.com/fish", note.GetAttributes().GetSourceURL())
+		require.Len(t, note.Resources, 1)
+		assert.Equal(t, "0cc175b9c0f1b6a831c399e269772661", fmt.Sprintf("%x", note.Resources[0].GetData().GetBodyHash()))
+		assert.Equal(t, "Chips", mock.createdNotes[1].GetTitle())
+	})
+
+	t.Run("resumes after a failure", func(t *testing.T) {
+		setDataDir(t)
+		store := &failingCreateStore{mockNoteStore: &mockNoteStore{}, failAt: 2}
+		cleanup := setFailingCreateStore(store)
+		defer cleanup()
+		defer resetImportFlags()
+
+		path := writeTestENEX(t, "One", "Two", "Three")
+		var out, errOut bytes.Buffer
+		importCmd.SetOut(&out)
+		importCmd.SetErr(&errOut)
+		err := importCmd.RunE(importCmd, []string{path})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to import note 2 (Two): rate limited")
+		assert.Contains(t, err.Error(), "run the same command again")
+		require.Len(t, store.createdNotes, 1)
+
+		out.Reset()
+		require.NoError(t, importCmd.RunE(importCmd, []string{path}))
+		assert.Equal(t, "Imported 2 note(s) with 0 attachment(s); skipped 1 already imported.\n", out.String())
+		require.Len(t, store.createdNotes, 3)
+		assert.Equal(t, "Two", store.createdNotes[1].GetTitle())
+		assert.Equal(t, "Three", store.createdNotes[2].GetTitle())
+
+		log, err := openImportLog(path, "", nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[int]edam.GUID{1: "guid-1", 2: "guid-3", 3: "guid-4"}, log.done)
+
+		// Everything is already imported, unless the log is ignored
+		out.Reset()
+		require.NoError(t, importCmd.RunE(importCmd, []string{path}))
+		assert.Equal(t, "Imported 0 note(s) with 0 attachment(s); skipped 3 already imported.\n", out.String())
+		require.Len(t, store.createdNotes, 3)
+
+		// Other tags are another import
+		importTags = []string{"archive"}
+		out.Reset()
+		require.NoError(t, importCmd.RunE(importCmd, []string{path}))
+		assert.Equal(t, "Imported 3 note(s) with 1 attachment(s).\n", out.String())
+		require.Len(t, store.createdNotes, 6)
+		importTags = nil
+
+		importRestart = true
+		out.Reset()
+		require.NoError(t, importCmd.RunE(importCmd, []string{path}))
+		assert.Equal(t, "Imported 3 note(s) with 1 attachment(s).\n", out.String())
+		require.Len(t, store.createdNotes, 9)
+	})
+
+	t.Run("another notebook is another import", func(t *testing.T) {
+		setDataDir(t)
+		a, b, nameA, nameB := edam.GUID("nb-a"), edam.GUID("nb-b"), "A", "B"
+		mock := &mockNoteStore{notebooks: []*edam.Notebook{{GUID: &a, Name: &nameA}, {GUID: &b, Name: &nameB}}}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+		defer resetImportFlags()
+
+		path := writeTestENEX(t, "Fish", "Chips")
+		var out bytes.Buffer
+		importCmd.SetOut(&out)
+		importCmd.SetErr(io.Discard)
+		importNotebook = "A"
+		require.NoError(t, importCmd.RunE(importCmd, []string{path}))
+		importNotebook = "B"
+		out.Reset()
+		require.NoError(t, importCmd.RunE(importCmd, []string{path}))
+		assert.Equal(t, "Imported 2 note(s) with 1 attachment(s).\n", out.String())
+		require.Len(t, mock.createdNotes, 4)
+		assert.Equal(t, "nb-b", mock.createdNotes[3].GetNotebookGuid())
+
+		// Changing the file starts over as well
+		later := time.Now().Add(time.Hour)
+		require.NoError(t, os.Chtimes(path, later, later))
+		out.Reset()
+		require.NoError(t, importCmd.RunE(importCmd, []string{path}))
+		assert.Equal(t, "Imported 2 note(s) with 1 attachment(s).\n", out.String())
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		dir := setDataDir(t)
+		mock := &mockNoteStore{}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+		defer resetImportFlags()
+
+		path := writeTestENEX(t, "Fish", "")
+		importDryRun = true
+		var buf bytes.Buffer
+		importCmd.SetOut(&buf)
+		require.NoError(t, importCmd.RunE(importCmd, []string{path}))
+		assert.Equal(t, "Would import: Fish (1 attachment(s))\nWould import: Untitled (0 attachment(s))\nWould import 2 note(s) with 1 attachment(s).\n", buf.String())
+		assert.Empty(t, mock.createdNotes)
+		assert.NoDirExists(t, filepath.Join(dir, "imports"))
+
+		jsonFlag = true
+		buf.Reset()
+		require.NoError(t, importCmd.RunE(importCmd, []string{path}))
+		var summary importSummary
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &summary))
+		assert.Equal(t, importSummary{File: path, DryRun: true, Notes: 2, Resources: 1}, summary)
+	})
+
+	t.Run("invalid content", func(t *testing.T) {
+		setDataDir(t)
+		mock := &mockNoteStore{}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+		defer resetImportFlags()
+
+		path := filepath.Join(t.TempDir(), "bad.enex")
+		content := `<en-export><note><title>Bad</title><content><![CDATA[` + wrapHTMLInENML(`<div onclick="x()">hi</div>`) + `]]></content></note></en-export>`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		err := importCmd.RunE(importCmd, []string{path})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `note "Bad"`)
+		assert.Contains(t, err.Error(), "--sanitize")
+		assert.Empty(t, mock.createdNotes)
+
+		importSanitize = true
+		var buf bytes.Buffer
+		importCmd.SetOut(&buf)
+		require.NoError(t, importCmd.RunE(importCmd, []string{path}))
+		require.Len(t, mock.createdNotes, 1)
+		assert.NotContains(t, mock.createdNotes[0].GetContent(), "onclick")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		setDataDir(t)
+		err := importCmd.RunE(importCmd, []string{filepath.Join(t.TempDir(), "nope.enex")})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestImportCmdConfiguration(t *testing.T) {
+	assert.Equal(t, "import [file.enex | dir]", importCmd.Use)
+	assert.Equal(t, "Import notes from ENEX or Markdown", importCmd.Short)
+	assert.NotNil(t, importCmd.RunE)
+	for _, name := range []string{"from", "notebook", "tag", "dry-run", "restart", "sanitize"} {
+		assert.NotNil(t, importCmd.Flags().Lookup(name), name)
+	}
+}
+
+func TestImportCmdRegistration(t *testing.T) {
+	found := false
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "import" {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "import command should be registered")
+}
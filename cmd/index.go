@@ -0,0 +1,425 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+)
+
+// searchIndexVersion is bumped whenever the index format or tokenizer
+// changes, forcing a rebuild.
+const searchIndexVersion = 2
+
+// indexDoc is what the index keeps about one note.
+type indexDoc struct {
+	GUID   edam.GUID
+	Active bool
+	// TitleLen is the number of title tokens. Body tokens follow them after
+	// a gap of one position, so that no phrase spans the title and the body.
+	TitleLen int32
+	// Length is the total number of tokens.
+	Length int32
+	// ModTime is the modification time of the stored note the document was
+	// built from, used to find notes that changed since.
+	ModTime int64
+	// Note is the metadata returned in search results.
+	Note *edam.NoteMetadata
+}
+
+// posting lists where a term occurs in one document.
+type posting struct {
+	Doc int32
+	Pos []int32
+}
+
+// searchIndex is an inverted index over the titles, text and attachment
+// recognition text of the notes in the local store.
+type searchIndex struct {
+	Version int
+	// Docs is indexed by document number.
+	Docs  []*indexDoc
+	Terms map[string][]posting
+
+	byGUID map[edam.GUID]int32
+	path   string
+}
+
+// token is a word found in text, with its byte offsets.
+type token struct {
+	text       string
+	start, end int
+}
+
+// tokenize splits text into lower-cased words of letters and digits.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if word && start < 0 {
+			start = i
+		}
+		if !word && start >= 0 {
+			tokens = append(tokens, token{strings.ToLower(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{strings.ToLower(text[start:]), start, len(text)})
+	}
+	return tokens
+}
+
+var (
+	enmlHeaderPattern = regexp.MustCompile(`<\?xml[^?]*\?>|<!DOCTYPE[^>]*>`)
+	enmlTagPattern    = regexp.MustCompile(`<[^>]+>`)
+	recoTextPattern   = regexp.MustCompile(`<t\b[^>]*>([^<]*)</t>`)
+)
+
+// noteText returns the searchable plain text of a note: its content followed
+// by any text Evernote recognised in its images and documents.
+func noteText(note *edam.Note) string {
+	content := enmlHeaderPattern.ReplaceAllString(note.GetContent(), "")
+	text := html.UnescapeString(enmlTagPattern.ReplaceAllString(content, " "))
+
+	var b strings.Builder
+	b.WriteString(strings.Join(strings.Fields(text), " "))
+	for _, res := range note.GetResources() {
+		if res.GetRecognition() == nil {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, m := range recoTextPattern.FindAllStringSubmatch(string(res.GetRecognition().GetBody()), -1) {
+			word := html.UnescapeString(m[1])
+			if !seen[word] {
+				seen[word] = true
+				b.WriteString(" ")
+				b.WriteString(word)
+			}
+		}
+	}
+	return b.String()
+}
+
+// loadSearchIndex loads the index for a store and brings it up to date with
+// the stored notes, saving it if anything changed.
+func loadSearchIndex(store *localStore) (*searchIndex, error) {
+	ix := &searchIndex{path: filepath.Join(store.dir, "index.gob")}
+	if f, err := os.Open(ix.path); err == nil {
+		err = gob.NewDecoder(f).Decode(ix)
+		f.Close()
+		if err != nil || ix.Version != searchIndexVersion {
+			ix = &searchIndex{path: ix.path}
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if ix.Terms == nil {
+		ix.Version = searchIndexVersion
+		ix.Terms = map[string][]posting{}
+	}
+	ix.byGUID = map[edam.GUID]int32{}
+	for i, doc := range ix.Docs {
+		ix.byGUID[doc.GUID] = int32(i)
+	}
+
+	changed, err := ix.refresh(store)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := ix.save(); err != nil {
+			return nil, fmt.Errorf("failed to save search index: %w", err)
+		}
+	}
+	return ix, nil
+}
+
+// refresh re-indexes notes whose stored file changed and drops notes that
+// are no longer stored. It reports whether the index changed.
+func (ix *searchIndex) refresh(store *localStore) (bool, error) {
+	stamps, err := store.modTimes(storeNotes)
+	if err != nil {
+		return false, err
+	}
+
+	stale := map[int32]bool{}
+	var changed []edam.GUID
+	for guid, id := range ix.byGUID {
+		if mod, ok := stamps[guid]; !ok || mod != ix.Docs[id].ModTime {
+			stale[id] = true
+		}
+	}
+	for guid := range stamps {
+		if id, ok := ix.byGUID[guid]; !ok || stale[id] {
+			changed = append(changed, guid)
+		}
+	}
+	if len(stale) == 0 && len(changed) == 0 {
+		return false, nil
+	}
+
+	if len(stale) > 0 {
+		ix.remove(stale)
+	}
+
+	sort.Slice(changed, func(i, j int) bool { return changed[i] < changed[j] })
+	for _, guid := range changed {
+		var note edam.Note
+		if err := store.get(storeNotes, guid, &note); err != nil {
+			return false, err
+		}
+		ix.add(&note, stamps[guid])
+	}
+	return true, nil
+}
+
+// remove drops documents from the index and renumbers the rest.
+func (ix *searchIndex) remove(docs map[int32]bool) {
+	renumber := make([]int32, len(ix.Docs))
+	kept := ix.Docs[:0]
+	for id, doc := range ix.Docs {
+		if docs[int32(id)] {
+			delete(ix.byGUID, doc.GUID)
+			continue
+		}
+		renumber[id] = int32(len(kept))
+		ix.byGUID[doc.GUID] = int32(len(kept))
+		kept = append(kept, doc)
+	}
+	ix.Docs = kept
+
+	for term, postings := range ix.Terms {
+		keptPostings := postings[:0]
+		for _, p := range postings {
+			if !docs[p.Doc] {
+				p.Doc = renumber[p.Doc]
+				keptPostings = append(keptPostings, p)
+			}
+		}
+		if len(keptPostings) == 0 {
+			delete(ix.Terms, term)
+		} else {
+			ix.Terms[term] = keptPostings
+		}
+	}
+}
+
+// add indexes a note.
+func (ix *searchIndex) add(note *edam.Note, modTime int64) {
+	id := int32(len(ix.Docs))
+	title := tokenize(note.GetTitle())
+	body := tokenize(noteText(note))
+	doc := &indexDoc{
+		GUID:     note.GetGUID(),
+		Active:   note.Active == nil || note.GetActive(),
+		TitleLen: int32(len(title)),
+		Length:   int32(len(title) + len(body)),
+		ModTime:  modTime,
+		Note:     noteMetadata(note),
+	}
+	ix.Docs = append(ix.Docs, doc)
+	ix.byGUID[doc.GUID] = id
+
+	positions := map[string][]int32{}
+	for i, t := range title {
+		positions[t.text] = append(positions[t.text], int32(i))
+	}
+	for i, t := range body {
+		positions[t.text] = append(positions[t.text], int32(len(title)+1+i))
+	}
+	for term, pos := range positions {
+		ix.Terms[term] = append(ix.Terms[term], posting{Doc: id, Pos: pos})
+	}
+}
+
+// save writes the index next to the store.
+func (ix *searchIndex) save() error {
+	tmp, err := os.CreateTemp(filepath.Dir(ix.path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(tmp).Encode(ix); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), ix.path)
+}
+
+// liveDocs returns the number of indexed notes and their average length.
+func (ix *searchIndex) liveDocs() (int, float64) {
+	if len(ix.Docs) == 0 {
+		return 0, 0
+	}
+	total := 0
+	for _, doc := range ix.Docs {
+		total += int(doc.Length)
+	}
+	return len(ix.Docs), float64(total) / float64(len(ix.Docs))
+}
+
+// tokenHits counts how often a token sequence occurs in each document, in
+// total and within the title.
+type tokenHits map[int32]struct{ all, title int }
+
+// postingsFor returns the postings of a term, or of every term starting with
+// it when prefix is set.
+func (ix *searchIndex) postingsFor(term string, prefix bool) map[int32][]int32 {
+	docs := map[int32][]int32{}
+	add := func(postings []posting) {
+		for _, p := range postings {
+			docs[p.Doc] = append(docs[p.Doc], p.Pos...)
+		}
+	}
+	if !prefix {
+		add(ix.Terms[term])
+		return docs
+	}
+	for t, postings := range ix.Terms {
+		if strings.HasPrefix(t, term) {
+			add(postings)
+		}
+	}
+	return docs
+}
+
+// match finds the documents containing the words in order. The last word
+// matches as a prefix when prefix is set. With titleOnly only matches inside
+// the title count.
+func (ix *searchIndex) match(words []string, prefix, titleOnly bool) tokenHits {
+	hits := tokenHits{}
+	if len(words) == 0 {
+		return hits
+	}
+	lists := make([]map[int32][]int32, len(words))
+	for i, w := range words {
+		lists[i] = ix.postingsFor(w, prefix && i == len(words)-1)
+	}
+	for doc, starts := range lists[0] {
+		title := ix.Docs[doc].TitleLen
+		h := hits[doc]
+		for _, start := range starts {
+			ok := true
+			for k := 1; k < len(words) && ok; k++ {
+				ok = containsPos(lists[k][doc], start+int32(k))
+			}
+			if !ok {
+				continue
+			}
+			end := start + int32(len(words)) - 1
+			if end < title {
+				h.title++
+			} else if titleOnly {
+				continue
+			}
+			h.all++
+		}
+		if h.all > 0 {
+			hits[doc] = h
+		}
+	}
+	return hits
+}
+
+// containsPos reports whether a position list contains p.
+func containsPos(positions []int32, p int32) bool {
+	for _, q := range positions {
+		if q == p {
+			return true
+		}
+	}
+	return false
+}
+
+// bm25 scores term hits in a document, weighting title matches more.
+func bm25(h struct{ all, title int }, idf float64, length int32, avgLength float64) float64 {
+	const k1, b, titleBoost = 1.2, 0.75, 2.0
+	tf := float64(h.all) + titleBoost*float64(h.title)
+	norm := 1.0
+	if avgLength > 0 {
+		norm = 1 - b + b*float64(length)/avgLength
+	}
+	return idf * tf * (k1 + 1) / (tf + k1*norm)
+}
+
+// idf is the inverse document frequency of a term found in df of n documents.
+func idf(n, df int) float64 {
+	return math.Log(1 + (float64(n)-float64(df)+0.5)/(float64(df)+0.5))
+}
+
+// snippet returns a short excerpt of text around the first match of any of
+// the words, with every match wrapped in ** markers.
+func snippet(text string, words []string, prefixes []string) string {
+	const before, after = 8, 22
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return ""
+	}
+	matches := func(t string) bool {
+		for _, w := range words {
+			if t == w {
+				return true
+			}
+		}
+		for _, p := range prefixes {
+			if strings.HasPrefix(t, p) {
+				return true
+			}
+		}
+		return false
+	}
+
+	first := -1
+	for i, t := range tokens {
+		if matches(t.text) {
+			first = i
+			break
+		}
+	}
+	from := 0
+	if first > before {
+		from = first - before
+	}
+	to := from + before + after
+	if to > len(tokens) {
+		to = len(tokens)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("...")
+	}
+	pos := tokens[from].start
+	for _, t := range tokens[from:to] {
+		b.WriteString(text[pos:t.start])
+		if matches(t.text) {
+			b.WriteString("**" + text[t.start:t.end] + "**")
+		} else {
+			b.WriteString(text[t.start:t.end])
+		}
+		pos = t.end
+	}
+	if to < len(tokens) {
+		b.WriteString("...")
+	} else {
+		b.WriteString(text[pos:])
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
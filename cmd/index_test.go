@@ -0,0 +1,78 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	var words []string
+	for _, tok := range tokenize("Café-au-lait, 2 cups! ÜBER") {
+		words = append(words, tok.text)
+	}
+	assert.Equal(t, []string{"café", "au", "lait", "2", "cups", "über"}, words)
+}
+
+func TestNoteText(t *testing.T) {
+	content := wrapHTMLInENML("<div>Fish &amp; chips</div><ul><li>one</li><li>two</li></ul>")
+	reco := `<recoIndex><item><t w="80">RECEIPT</t><t w="20">RECE1PT</t></item><item><t w="90">RECEIPT</t></item></recoIndex>`
+	note := &edam.Note{
+		Content:   &content,
+		Resources: []*edam.Resource{{Recognition: &edam.Data{Body: []byte(reco)}}},
+	}
+	assert.Equal(t, "Fish & chips one two RECEIPT RECE1PT", noteText(note))
+}
+
+func TestSnippet(t *testing.T) {
+	text := "one two three four five six seven eight nine ten eleven twelve Passport thirteen"
+	assert.Equal(t, "...five six seven eight nine ten eleven twelve **Passport** thirteen", snippet(text, []string{"passport"}, nil))
+	assert.Equal(t, "**one** two", snippet("one two", nil, []string{"on"}))
+	assert.Equal(t, "", snippet("", []string{"x"}, nil))
+}
+
+func TestLoadSearchIndex(t *testing.T) {
+	store, err := openLocalStore(t.TempDir())
+	require.NoError(t, err)
+	put := func(guid, title string) {
+		g, content := edam.GUID(guid), wrapENML(title)
+		require.NoError(t, store.put(storeNotes, g, &edam.Note{GUID: &g, Title: &title, Content: &content}))
+	}
+	touch := func(guid string, age time.Duration) {
+		mod := time.Now().Add(-age)
+		require.NoError(t, os.Chtimes(filepath.Join(store.dir, storeNotes, guid+".json"), mod, mod))
+	}
+	put("n-1", "alpha")
+	put("n-2", "beta")
+	touch("n-1", time.Hour)
+	touch("n-2", time.Hour)
+
+	ix, err := loadSearchIndex(store)
+	require.NoError(t, err)
+	assert.Len(t, ix.Docs, 2)
+	assert.FileExists(t, filepath.Join(store.dir, "index.gob"))
+
+	// Changed, removed and new notes are picked up from the saved index
+	put("n-1", "gamma")
+	require.NoError(t, store.remove(storeNotes, "n-2"))
+	put("n-3", "delta")
+
+	ix, err = loadSearchIndex(store)
+	require.NoError(t, err)
+	require.Len(t, ix.Docs, 2)
+	assert.Empty(t, ix.match([]string{"alpha"}, false, false))
+	assert.Empty(t, ix.match([]string{"beta"}, false, false))
+	assert.Len(t, ix.match([]string{"gamma"}, false, false), 1)
+	hits := ix.match([]string{"delta"}, false, false)
+	require.Len(t, hits, 1)
+	for doc := range hits {
+		assert.Equal(t, edam.GUID("n-3"), ix.Docs[doc].GUID)
+	}
+}
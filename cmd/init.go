@@ -26,8 +26,15 @@ var initCmd = &cobra.Command{
 		}
 		id = strings.TrimSpace(id)
 		secret = strings.TrimSpace(secret)
+		env, err := parseEnvironment(environmentFlag)
+		if err != nil {
+			return err
+		}
+		if err := checkSecretStore(secretStoreFlag); err != nil {
+			return err
+		}
 
-		token, noteStoreURL, err := runAuthFlow(id, secret)
+		token, noteStoreURL, err := runAuthFlow(cmd, reader, id, secret, env)
 		if err != nil {
 			return err
 		}
@@ -37,15 +44,21 @@ var initCmd = &cobra.Command{
 			ClientSecret: secret,
 			AuthToken:    token,
 			NoteStoreURL: noteStoreURL,
+			Environment:  env.name,
+			SecretStore:  secretStoreFlag,
 		}
 		if err := saveConfig(cfg); err != nil {
 			return err
 		}
-		fmt.Fprintln(cmd.OutOrStdout(), "Authentication successful. Configuration saved to", configPath)
+		fmt.Fprintf(cmd.OutOrStdout(), "Authentication successful. Configuration saved to %s (profile %s)\n", configPath, currentProfile())
 		return nil
 	},
 }
 
 func init() {
+	initCmd.Flags().StringVar(&environmentFlag, "environment", "", "Evernote service: production, sandbox, yinxiang or a host URL (default production)")
+	initCmd.Flags().StringVar(&secretStoreFlag, "secret-store", "", "where to keep the token: plaintext, keyring or file (default from the profile, else plaintext)")
+	initCmd.Flags().BoolVar(&authNoBrowser, "no-browser", false, "print the sign-in URL and read the redirect URL or verifier from stdin")
+	initCmd.Flags().IntVar(&authCallbackPort, "callback-port", 0, "port on 127.0.0.1 for the OAuth callback (0 picks a free port)")
 	rootCmd.AddCommand(initCmd)
 }
@@ -0,0 +1,789 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mdATXHeading  = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	mdFence       = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ \t]*([^`]*)$")
+	mdQuote       = regexp.MustCompile(`^ {0,3}> ?`)
+	mdListItem    = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])([ \t]+|$)`)
+	mdTaskItem    = regexp.MustCompile(`^\[([ xX])\](?:[ \t]+|$)`)
+	mdTableDelim  = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	mdSetextLine  = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	mdURLScheme   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+	mdBareURL     = regexp.MustCompile(`^https?://[^\s<]+`)
+	mdSafeSchemes = map[string]bool{"http": true, "https": true, "mailto": true, "evernote": true, "ftp": true, "tel": true}
+)
+
+// mdNode is a block-level element parsed from Markdown source.
+type mdNode struct {
+	kind     string
+	level    int
+	lines    []string
+	ordered  bool
+	start    int
+	task     string
+	loose    bool
+	children []*mdNode
+	rows     [][]string
+}
+
+// markdownConverter renders Markdown into ENML, turning local image
+// references into attached resources.
+type markdownConverter struct {
+	baseDir   string
+	resources []*edam.Resource
+	hashes    map[string]bool
+	media     map[string]*edam.Resource
+	// attachLinks makes links to local files attach them like images.
+	attachLinks bool
+	// root, when set, confines local files to that directory: images and
+	// links to files outside it are not attached.
+	root string
+	err  error
+}
+
+// newMarkdownConverter returns a converter that resolves local images
+// relative to baseDir.
+func newMarkdownConverter(baseDir string) *markdownConverter {
+	return &markdownConverter{baseDir: baseDir, hashes: make(map[string]bool)}
+}
+
+// useResources lets links and images that name one of the note's existing
+// attachments, as written by enmlToMarkdown, convert back to <en-media>.
+func (c *markdownConverter) useResources(resources []*edam.Resource) {
+	c.media = make(map[string]*edam.Resource)
+	for _, res := range resources {
+		if res.GetData() == nil || len(res.GetData().GetBodyHash()) == 0 {
+			continue
+		}
+		hash := hex.EncodeToString(res.GetData().GetBodyHash())
+		name := resourceFileName(res, hash, res.GetMime())
+		if _, ok := c.media[name]; !ok {
+			c.media[name] = res
+		}
+	}
+}
+
+// existingMedia returns an <en-media> tag if dest names an existing attachment.
+func (c *markdownConverter) existingMedia(dest string) (string, bool) {
+	if c.media == nil {
+		return "", false
+	}
+	if unescaped, err := url.PathUnescape(dest); err == nil {
+		dest = unescaped
+	}
+	res, ok := c.media[dest]
+	if !ok {
+		return "", false
+	}
+	return buildMediaTag(res.GetData().GetBodyHash(), res.GetMime()), true
+}
+
+// markdownToENML converts Markdown source into a complete ENML document.
+// Local images are read relative to baseDir and returned as resources whose
+// hashes match the generated <en-media> tags.
+func markdownToENML(src, baseDir string) (string, []*edam.Resource, error) {
+	c := newMarkdownConverter(baseDir)
+	body := c.convert(src)
+	if c.err != nil {
+		return "", nil, c.err
+	}
+	return wrapHTMLInENML(body), c.resources, nil
+}
+
+// convert parses and renders Markdown source as an ENML fragment.
+func (c *markdownConverter) convert(src string) string {
+	src = strings.ReplaceAll(strings.ReplaceAll(src, "\r\n", "\n"), "\t", "    ")
+	var b strings.Builder
+	c.renderBlocks(&b, parseMarkdownBlocks(strings.Split(src, "\n")), false)
+	return b.String()
+}
+
+// readMarkdownInput returns Markdown from the --markdown or --md-file flags
+// together with the directory used to resolve relative image paths. A file
+// name of "-" reads from standard input.
+func readMarkdownInput(cmd *cobra.Command, text, file string) (string, string, error) {
+	if file == "" {
+		return text, ".", nil
+	}
+	if file == "-" {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read markdown from stdin: %w", err)
+		}
+		return string(data), ".", nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read markdown file %s: %w", file, err)
+	}
+	return string(data), filepath.Dir(file), nil
+}
+
+// parseMarkdownBlocks splits Markdown lines into block nodes.
+func parseMarkdownBlocks(lines []string) []*mdNode {
+	var nodes []*mdNode
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			i++
+			continue
+		}
+
+		if m := mdFence.FindStringSubmatch(line); m != nil {
+			fence := m[1]
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			var code []string
+			i++
+			for ; i < len(lines); i++ {
+				trimmed := strings.TrimSpace(lines[i])
+				if strings.HasPrefix(trimmed, fence[:3]) && strings.Trim(trimmed, fence[:1]) == "" && len(trimmed) >= len(fence) {
+					i++
+					break
+				}
+				code = append(code, trimIndent(lines[i], indent))
+			}
+			nodes = append(nodes, &mdNode{kind: "code", lines: code})
+			continue
+		}
+
+		if m := mdATXHeading.FindStringSubmatch(line); m != nil {
+			nodes = append(nodes, &mdNode{kind: "heading", level: len(m[1]), lines: []string{m[2]}})
+			i++
+			continue
+		}
+
+		if isThematicBreak(line) {
+			nodes = append(nodes, &mdNode{kind: "hr"})
+			i++
+			continue
+		}
+
+		if mdQuote.MatchString(line) {
+			var inner []string
+			for ; i < len(lines) && mdQuote.MatchString(lines[i]); i++ {
+				inner = append(inner, mdQuote.ReplaceAllString(lines[i], ""))
+			}
+			nodes = append(nodes, &mdNode{kind: "quote", children: parseMarkdownBlocks(inner)})
+			continue
+		}
+
+		if mdListItem.MatchString(line) {
+			var list *mdNode
+			list, i = parseMarkdownList(lines, i)
+			nodes = append(nodes, list)
+			continue
+		}
+
+		if strings.HasPrefix(line, "    ") {
+			var code []string
+			for ; i < len(lines) && (strings.HasPrefix(lines[i], "    ") || strings.TrimSpace(lines[i]) == ""); i++ {
+				code = append(code, trimIndent(lines[i], 4))
+			}
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			nodes = append(nodes, &mdNode{kind: "code", lines: code})
+			continue
+		}
+
+		if strings.Contains(line, "|") && i+1 < len(lines) && mdTableDelim.MatchString(lines[i+1]) && strings.Contains(lines[i+1], "-") {
+			table := &mdNode{kind: "table", rows: [][]string{splitTableRow(line)}}
+			for i += 2; i < len(lines) && strings.TrimSpace(lines[i]) != "" && strings.Contains(lines[i], "|"); i++ {
+				table.rows = append(table.rows, splitTableRow(lines[i]))
+			}
+			nodes = append(nodes, table)
+			continue
+		}
+
+		para := &mdNode{kind: "paragraph"}
+		for ; i < len(lines); i++ {
+			l := lines[i]
+			if strings.TrimSpace(l) == "" {
+				break
+			}
+			if len(para.lines) > 0 {
+				if m := mdSetextLine.FindStringSubmatch(l); m != nil {
+					para.kind = "heading"
+					para.level = 1
+					if m[1][0] == '-' {
+						para.level = 2
+					}
+					i++
+					break
+				}
+				if interruptsParagraph(l) {
+					break
+				}
+			}
+			para.lines = append(para.lines, l)
+		}
+		if para.kind == "heading" {
+			para.lines = []string{strings.Join(trimLines(para.lines), " ")}
+		}
+		nodes = append(nodes, para)
+	}
+	return nodes
+}
+
+// parseMarkdownList parses consecutive list items of the same type starting
+// at lines[i] and returns the list node and the index of the next line.
+func parseMarkdownList(lines []string, i int) (*mdNode, int) {
+	first := mdListItem.FindStringSubmatch(lines[i])
+	list := &mdNode{kind: "list", ordered: !strings.ContainsAny(first[2], "-*+"), start: 1}
+	if list.ordered {
+		list.start, _ = strconv.Atoi(first[2][:len(first[2])-1])
+	}
+	delim := first[2][len(first[2])-1:]
+
+	for i < len(lines) {
+		m := mdListItem.FindStringSubmatch(lines[i])
+		if m == nil || strings.ContainsAny(m[2], "-*+") == list.ordered || m[2][len(m[2])-1:] != delim || isThematicBreak(lines[i]) {
+			break
+		}
+
+		width := len(m[0])
+		if len(m[3]) > 4 {
+			width = len(m[1]) + len(m[2]) + 1
+		} else if m[3] == "" {
+			width = len(m[0]) + 1
+		}
+		content := []string{strings.TrimLeft(lines[i][len(m[1])+len(m[2]):], " ")}
+		i++
+
+		for i < len(lines) {
+			l := lines[i]
+			if strings.TrimSpace(l) == "" {
+				if i+1 < len(lines) && indentOf(lines[i+1]) >= width {
+					content = append(content, "")
+					i++
+					continue
+				}
+				break
+			}
+			if indentOf(l) >= width {
+				content = append(content, l[width:])
+				i++
+				continue
+			}
+			prev := content[len(content)-1]
+			if strings.TrimSpace(prev) != "" && !interruptsParagraph(l) && !mdListItem.MatchString(l) {
+				content = append(content, strings.TrimLeft(l, " "))
+				i++
+				continue
+			}
+			break
+		}
+
+		item := &mdNode{kind: "item"}
+		if t := mdTaskItem.FindStringSubmatch(content[0]); t != nil {
+			item.task = "false"
+			if t[1] != " " {
+				item.task = "true"
+			}
+			content[0] = content[0][len(t[0]):]
+		}
+		for j := 1; j < len(content)-1; j++ {
+			if content[j] == "" {
+				list.loose = true
+			}
+		}
+		item.children = parseMarkdownBlocks(content)
+		list.children = append(list.children, item)
+
+		if i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			j := i
+			for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+				j++
+			}
+			if j < len(lines) && mdListItem.MatchString(lines[j]) && indentOf(lines[j]) < width {
+				i = j
+				list.loose = true
+			}
+		}
+	}
+	return list, i
+}
+
+// interruptsParagraph reports whether line starts a block that ends a paragraph.
+func interruptsParagraph(line string) bool {
+	if mdATXHeading.MatchString(line) || mdFence.MatchString(line) || mdQuote.MatchString(line) || isThematicBreak(line) {
+		return true
+	}
+	if m := mdListItem.FindStringSubmatch(line); m != nil && m[3] != "" {
+		return strings.ContainsAny(m[2], "-*+") || strings.HasPrefix(m[2], "1")
+	}
+	return false
+}
+
+// isThematicBreak reports whether line is a Markdown horizontal rule.
+func isThematicBreak(line string) bool {
+	s := strings.TrimSpace(line)
+	if len(s) < 3 || indentOf(line) > 3 || !strings.ContainsAny(s[:1], "-*_") {
+		return false
+	}
+	count := 0
+	for _, c := range s {
+		switch {
+		case c == rune(s[0]):
+			count++
+		case c == ' ' || c == '\t':
+		default:
+			return false
+		}
+	}
+	return count >= 3
+}
+
+// indentOf returns the number of leading spaces in line.
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// trimIndent removes up to n leading spaces from line.
+func trimIndent(line string, n int) string {
+	for n > 0 && strings.HasPrefix(line, " ") {
+		line = line[1:]
+		n--
+	}
+	return line
+}
+
+// trimLines trims surrounding whitespace from every line.
+func trimLines(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = strings.TrimSpace(l)
+	}
+	return out
+}
+
+// splitTableRow splits a GFM table row into its cells.
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '|' {
+			cell.WriteByte('|')
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(line[i])
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// renderBlocks writes block nodes as ENML. Inside the items of a tight list
+// paragraphs are written without a wrapping element.
+func (c *markdownConverter) renderBlocks(b *strings.Builder, nodes []*mdNode, tight bool) {
+	for _, n := range nodes {
+		switch n.kind {
+		case "paragraph":
+			text := c.inline(strings.Join(n.lines, "\n"))
+			if tight {
+				b.WriteString(text)
+			} else {
+				b.WriteString("<p>" + text + "</p>")
+			}
+		case "heading":
+			fmt.Fprintf(b, "<h%d>%s</h%d>", n.level, c.inline(strings.Join(n.lines, " ")), n.level)
+		case "code":
+			b.WriteString("<pre>" + html.EscapeString(strings.Join(n.lines, "\n")) + "</pre>")
+		case "hr":
+			b.WriteString("<hr/>")
+		case "quote":
+			b.WriteString("<blockquote>")
+			c.renderBlocks(b, n.children, false)
+			b.WriteString("</blockquote>")
+		case "list":
+			c.renderList(b, n)
+		case "table":
+			b.WriteString("<table>")
+			for r, row := range n.rows {
+				cellTag := "td"
+				if r == 0 {
+					cellTag = "th"
+				}
+				b.WriteString("<tr>")
+				for _, cell := range row {
+					b.WriteString("<" + cellTag + ">" + c.inline(cell) + "</" + cellTag + ">")
+				}
+				b.WriteString("</tr>")
+			}
+			b.WriteString("</table>")
+		}
+	}
+}
+
+// renderList writes a list node. A bullet list made up entirely of task
+// items is written as Evernote checklist lines rather than a <ul>.
+func (c *markdownConverter) renderList(b *strings.Builder, n *mdNode) {
+	checklist := !n.ordered
+	for _, item := range n.children {
+		if item.task == "" {
+			checklist = false
+		}
+	}
+	if checklist {
+		for _, item := range n.children {
+			b.WriteString(`<div><en-todo checked="` + item.task + `"/>`)
+			c.renderBlocks(b, item.children, !n.loose)
+			b.WriteString("</div>")
+		}
+		return
+	}
+
+	switch {
+	case !n.ordered:
+		b.WriteString("<ul>")
+	case n.start != 1:
+		fmt.Fprintf(b, `<ol start="%d">`, n.start)
+	default:
+		b.WriteString("<ol>")
+	}
+	for _, item := range n.children {
+		b.WriteString("<li>")
+		if item.task != "" {
+			b.WriteString(`<en-todo checked="` + item.task + `"/>`)
+		}
+		c.renderBlocks(b, item.children, !n.loose)
+		b.WriteString("</li>")
+	}
+	if n.ordered {
+		b.WriteString("</ol>")
+	} else {
+		b.WriteString("</ul>")
+	}
+}
+
+// inline renders Markdown inline syntax as escaped ENML.
+func (c *markdownConverter) inline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		ch := s[i]
+		switch {
+		case ch == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			b.WriteString("<br/>")
+			i += 2
+			continue
+		case ch == '\\' && i+1 < len(s) && strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+		case ch == '\n':
+			if strings.HasSuffix(b.String(), "  ") {
+				trimmed := strings.TrimRight(b.String(), " ")
+				b.Reset()
+				b.WriteString(trimmed + "<br/>")
+			} else {
+				b.WriteString("\n")
+			}
+			i++
+			continue
+		case ch == '`':
+			run := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+			fence := s[i : i+run]
+			if end := strings.Index(s[i+run:], fence); end >= 0 {
+				code := strings.ReplaceAll(s[i+run:i+run+end], "\n", " ")
+				if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' {
+					code = code[1 : len(code)-1]
+				}
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i += run + end + run
+				continue
+			}
+			b.WriteString(fence)
+			i += run
+			continue
+		case ch == '!' && strings.HasPrefix(s[i:], "!["):
+			if alt, dest, n := parseMarkdownLink(s[i+1:]); n > 0 {
+				b.WriteString(c.image(alt, dest))
+				i += 1 + n
+				continue
+			}
+		case ch == '[':
+			if text, dest, n := parseMarkdownLink(s[i:]); n > 0 {
+				b.WriteString(c.link(c.inline(text), dest))
+				i += n
+				continue
+			}
+		case ch == '<':
+			if end := strings.IndexByte(s[i:], '>'); end > 0 {
+				target := s[i+1 : i+end]
+				if mdURLScheme.MatchString(target) && !strings.ContainsAny(target, " <") {
+					b.WriteString(c.link(html.EscapeString(target), target))
+					i += end + 1
+					continue
+				}
+			}
+		case ch == 'h' && (i == 0 || !isWordByte(s[i-1])):
+			if m := mdBareURL.FindString(s[i:]); m != "" {
+				m = strings.TrimRight(m, ".,;:!?)*_~'\"")
+				b.WriteString(c.link(html.EscapeString(m), m))
+				i += len(m)
+				continue
+			}
+		case ch == '*' || ch == '_' || ch == '~':
+			if out, n := c.emphasis(s, i); n > 0 {
+				b.WriteString(out)
+				i += n
+				continue
+			}
+		}
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// emphasis renders a strong, emphasis or strikethrough span starting at
+// s[i]. It returns the rendered text and the number of bytes consumed, or 0
+// when the delimiter does not open a span.
+func (c *markdownConverter) emphasis(s string, i int) (string, int) {
+	ch := s[i]
+	run := 1
+	for i+run < len(s) && s[i+run] == ch && run < 3 {
+		run++
+	}
+	if ch == '~' && run < 2 {
+		return "", 0
+	}
+	if ch == '~' {
+		run = 2
+	}
+	delim := s[i : i+run]
+	rest := s[i+run:]
+	if rest == "" || rest[0] == ' ' || rest[0] == '\n' {
+		return "", 0
+	}
+	if ch == '_' && i > 0 && isWordByte(s[i-1]) {
+		return "", 0
+	}
+
+	for from := 0; from < len(rest); {
+		end := strings.Index(rest[from:], delim)
+		if end < 0 {
+			return "", 0
+		}
+		end += from
+		after := end + len(delim)
+		validClose := end > 0 && rest[end-1] != ' ' && rest[end-1] != '\n' &&
+			(after >= len(rest) || rest[after] != ch) &&
+			!(ch == '_' && after < len(rest) && isWordByte(rest[after]))
+		if !validClose {
+			from = end + 1
+			continue
+		}
+		inner := c.inline(rest[:end])
+		switch {
+		case ch == '~':
+			return "<del>" + inner + "</del>", run + after
+		case run == 1:
+			return "<em>" + inner + "</em>", run + after
+		case run == 2:
+			return "<strong>" + inner + "</strong>", run + after
+		default:
+			return "<strong><em>" + inner + "</em></strong>", run + after
+		}
+	}
+	return "", 0
+}
+
+// parseMarkdownLink parses "[text](dest "title")" at the start of s and
+// returns the text, destination and number of bytes consumed.
+func parseMarkdownLink(s string) (string, string, int) {
+	if !strings.HasPrefix(s, "[") {
+		return "", "", 0
+	}
+	depth := 0
+	closeText := -1
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			closeText = i
+			break
+		}
+	}
+	if closeText < 0 || closeText+1 >= len(s) || s[closeText+1] != '(' {
+		return "", "", 0
+	}
+
+	text := s[1:closeText]
+	rest := s[closeText+2:]
+	var dest string
+	var n int
+	if strings.HasPrefix(rest, "<") {
+		end := strings.IndexByte(rest, '>')
+		if end < 0 {
+			return "", "", 0
+		}
+		dest = rest[1:end]
+		n = end + 1
+	} else {
+		parens := 0
+		for n < len(rest) {
+			ch := rest[n]
+			if ch == ' ' || ch == '\n' || (ch == ')' && parens == 0) {
+				break
+			}
+			if ch == '(' {
+				parens++
+			}
+			if ch == ')' {
+				parens--
+			}
+			n++
+		}
+		dest = rest[:n]
+	}
+
+	end := strings.IndexByte(rest[n:], ')')
+	if end < 0 {
+		return "", "", 0
+	}
+	if title := strings.TrimSpace(rest[n : n+end]); title != "" && !strings.ContainsAny(title[:1], `"'(`) {
+		return "", "", 0
+	}
+	return text, dest, closeText + 2 + n + end + 1
+}
+
+// link renders an anchor. Links with unsafe schemes are written as text.
+func (c *markdownConverter) link(text, dest string) string {
+	if tag, ok := c.existingMedia(dest); ok {
+		return tag
+	}
+	if c.attachLinks && c.isLocalFile(dest) {
+		return c.attach(dest)
+	}
+	if m := mdURLScheme.FindString(dest); m != "" && !mdSafeSchemes[strings.ToLower(strings.TrimSuffix(m, ":"))] {
+		return text
+	}
+	return `<a href="` + html.EscapeString(dest) + `">` + text + `</a>`
+}
+
+// image renders an image. Local files are attached to the note as resources
+// and referenced with <en-media>; remote images are linked with <img>.
+func (c *markdownConverter) image(alt, dest string) string {
+	if tag, ok := c.existingMedia(dest); ok {
+		return tag
+	}
+	if strings.HasPrefix(dest, "http://") || strings.HasPrefix(dest, "https://") {
+		return `<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(alt) + `"/>`
+	}
+	if mdURLScheme.MatchString(dest) && !strings.HasPrefix(dest, "file:") {
+		return html.EscapeString(alt)
+	}
+	if !c.inRoot(c.localPath(dest)) {
+		return html.EscapeString(alt)
+	}
+	return c.attach(dest)
+}
+
+// localPath returns the file a local link or image destination refers to.
+func (c *markdownConverter) localPath(dest string) string {
+	path := strings.TrimPrefix(dest, "file://")
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(c.baseDir, path)
+	}
+	return path
+}
+
+// isLocalFile reports whether a link points to a local file other than a
+// Markdown document.
+func (c *markdownConverter) isLocalFile(dest string) bool {
+	if dest == "" || strings.HasPrefix(dest, "#") || (mdURLScheme.MatchString(dest) && !strings.HasPrefix(dest, "file:")) {
+		return false
+	}
+	path := c.localPath(dest)
+	if strings.EqualFold(filepath.Ext(path), ".md") {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && c.inRoot(path)
+}
+
+// inRoot reports whether path, with symlinks resolved, lies inside the
+// converter's root. Every path is allowed when there is no root.
+func (c *markdownConverter) inRoot(path string) bool {
+	if c.root == "" {
+		return true
+	}
+	root, err := resolvedPath(c.root)
+	if err != nil {
+		return false
+	}
+	target, err := resolvedPath(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(root, target)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// resolvedPath returns the absolute path with symlinks resolved, or just
+// cleaned when the file does not exist.
+func resolvedPath(path string) (string, error) {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return filepath.Abs(path)
+}
+
+// attach attaches a local file to the note and returns its <en-media> tag.
+func (c *markdownConverter) attach(dest string) string {
+	res, hash, err := buildResource(c.localPath(dest))
+	if err != nil {
+		if c.err == nil {
+			c.err = err
+		}
+		return ""
+	}
+	key := hex.EncodeToString(hash)
+	if !c.hashes[key] {
+		c.hashes[key] = true
+		c.resources = append(c.resources, res)
+	}
+	return buildMediaTag(hash, res.GetMime())
+}
+
+// isWordByte reports whether b is an ASCII letter, digit or underscore.
+func isWordByte(b byte) bool {
+	return b == '_' || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,119 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"crypto/md5"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// markdownBody converts Markdown and returns the ENML between the <en-note> tags.
+func markdownBody(t *testing.T, src string) string {
+	t.Helper()
+	content, _, err := markdownToENML(src, t.TempDir())
+	require.NoError(t, err)
+	start := strings.Index(content, "<en-note>") + len("<en-note>")
+	end := strings.LastIndex(content, "</en-note>")
+	return content[start:end]
+}
+
+func TestMarkdownToENML(t *testing.T) {
+	t.Run("headings and paragraphs", func(t *testing.T) {
+		body := markdownBody(t, "# Title\n\nFirst *para*\nstill first\n\nSecond **para**")
+		assert.Equal(t, "<h1>Title</h1><p>First <em>para</em>\nstill first</p><p>Second <strong>para</strong></p>", body)
+	})
+
+	t.Run("setext heading", func(t *testing.T) {
+		assert.Equal(t, "<h2>Sub</h2>", markdownBody(t, "Sub\n---"))
+	})
+
+	t.Run("escapes html", func(t *testing.T) {
+		body := markdownBody(t, `<script>alert("x")</script> & more`)
+		assert.Equal(t, "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; more</p>", body)
+	})
+
+	t.Run("links and unsafe schemes", func(t *testing.T) {
+		body := markdownBody(t, "[docs](https://example.com/a_(b)) and [bad](javascript:alert(1)) <https://x.io>")
+		assert.Equal(t, `<p><a href="https://example.com/a_(b)">docs</a> and bad <a href="https://x.io">https://x.io</a></p>`, body)
+	})
+
+	t.Run("inline code and strikethrough", func(t *testing.T) {
+		body := markdownBody(t, "Run `a <b> *c*` then ~~skip~~ snake_case_name")
+		assert.Equal(t, "<p>Run <code>a &lt;b&gt; *c*</code> then <del>skip</del> snake_case_name</p>", body)
+	})
+
+	t.Run("nested lists", func(t *testing.T) {
+		body := markdownBody(t, "- one\n- two\n  1. sub\n  2. sub two\n- three")
+		assert.Equal(t, "<ul><li>one</li><li>two<ol><li>sub</li><li>sub two</li></ol></li><li>three</li></ul>", body)
+	})
+
+	t.Run("checklist", func(t *testing.T) {
+		body := markdownBody(t, "- [ ] open\n- [x] done")
+		assert.Equal(t, `<div><en-todo checked="false"/>open</div><div><en-todo checked="true"/>done</div>`, body)
+	})
+
+	t.Run("mixed task list", func(t *testing.T) {
+		body := markdownBody(t, "1. [x] done\n2. plain")
+		assert.Equal(t, `<ol><li><en-todo checked="true"/>done</li><li>plain</li></ol>`, body)
+	})
+
+	t.Run("fenced code", func(t *testing.T) {
+		body := markdownBody(t, "```go\nif a < b {\n}\n```")
+		assert.Equal(t, "<pre>if a &lt; b {\n}</pre>", body)
+	})
+
+	t.Run("blockquote and rule", func(t *testing.T) {
+		body := markdownBody(t, "> quoted\n> text\n\n***")
+		assert.Equal(t, "<blockquote><p>quoted\ntext</p></blockquote><hr/>", body)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		body := markdownBody(t, "| a | b |\n|---|:-:|\n| 1 | x\\|y |")
+		assert.Equal(t, "<table><tr><th>a</th><th>b</th></tr><tr><td>1</td><td>x|y</td></tr></table>", body)
+	})
+
+	t.Run("hard line break", func(t *testing.T) {
+		assert.Equal(t, "<p>one<br/>two</p>", markdownBody(t, "one  \ntwo"))
+	})
+
+	t.Run("local images become resources", func(t *testing.T) {
+		dir := t.TempDir()
+		data := []byte("png data")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "shot one.png"), data, 0644))
+
+		content, resources, err := markdownToENML("![shot](shot%20one.png) and ![again](<shot one.png>)", dir)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		hash := md5.Sum(data)
+		assert.Equal(t, hash[:], resources[0].GetData().GetBodyHash())
+		assert.Equal(t, "image/png", resources[0].GetMime())
+		assert.Equal(t, 2, strings.Count(content, fmt.Sprintf(`<en-media type="image/png" hash="%x"/>`, hash[:])))
+	})
+
+	t.Run("remote images use img", func(t *testing.T) {
+		body := markdownBody(t, "![logo](https://example.com/logo.png)")
+		assert.Equal(t, `<p><img src="https://example.com/logo.png" alt="logo"/></p>`, body)
+	})
+
+	t.Run("missing local image", func(t *testing.T) {
+		_, _, err := markdownToENML("![x](missing.png)", t.TempDir())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read file")
+	})
+
+	t.Run("round trips through the markdown renderer", func(t *testing.T) {
+		src := "## Tasks\n\n- [ ] write\n- [x] test\n\n| a | b |\n| --- | --- |\n| 1 | 2 |"
+		content, _, err := markdownToENML(src, t.TempDir())
+		require.NoError(t, err)
+		md, err := enmlToMarkdown(content, nil)
+		require.NoError(t, err)
+		assert.Equal(t, src, md)
+	})
+}
@@ -4,14 +4,57 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
 
+	"github.com/dreampuf/evernote-sdk-golang/edam"
 	"github.com/spf13/cobra"
 )
 
+var (
+	notebookStack string
+	notebookYes   bool
+)
+
+// validNotebookName checks a notebook name against Evernote's length and
+// whitespace rules.
+func validNotebookName(name string) error {
+	if name == "" || strings.TrimSpace(name) != name {
+		return fmt.Errorf("notebook name cannot be empty or start or end with whitespace")
+	}
+	if len([]rune(name)) > 100 {
+		return fmt.Errorf("notebook name cannot be longer than 100 characters")
+	}
+	return nil
+}
+
+// printNotebookResult prints a notebook after a change, as JSON with --json.
+func printNotebookResult(w io.Writer, action string, nb *edam.Notebook) error {
+	if jsonFlag {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(nb)
+	}
+	fmt.Fprintf(w, "Notebook %s: %s\n", action, nb.GetName())
+	fmt.Fprintf(w, "GUID: %s\n", nb.GetGUID())
+	return nil
+}
+
 // notebooksCmd lists all notebooks in the authenticated Evernote account.
 var notebooksCmd = &cobra.Command{
 	Use:   "notebooks",
 	Short: "List all notebooks",
+	Long: `List all notebooks, or manage them with the subcommands below. Notebooks
+can be given by name or GUID.
+
+Examples:
+  evernote-cli notebooks
+  evernote-cli notebooks create "Project X" --stack Projects
+  evernote-cli notebooks rename "Project X" "Project Y"
+  evernote-cli notebooks set-default Inbox
+  evernote-cli notebooks stacks`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ns, token, err := getNoteStoreFunc()
 		if err != nil {
@@ -51,6 +94,210 @@ var notebooksCmd = &cobra.Command{
 	},
 }
 
+// notebooksCreateCmd creates a notebook, optionally inside a stack.
+var notebooksCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a notebook",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validNotebookName(args[0]); err != nil {
+			return err
+		}
+
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		notebook := &edam.Notebook{Name: &args[0]}
+		if notebookStack != "" {
+			notebook.Stack = &notebookStack
+		}
+		created, err := ns.CreateNotebook(context.Background(), token, notebook)
+		if err != nil {
+			return fmt.Errorf("failed to create notebook: %w", formatAPIError(err))
+		}
+		return printNotebookResult(cmd.OutOrStdout(), "created", created)
+	},
+}
+
+// notebooksRenameCmd renames a notebook.
+var notebooksRenameCmd = &cobra.Command{
+	Use:   "rename <notebook> <new-name>",
+	Short: "Rename a notebook",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validNotebookName(args[1]); err != nil {
+			return err
+		}
+
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		nb, err := newNameResolver(ns, token).notebook(args[0])
+		if err != nil {
+			return err
+		}
+
+		// UpdateNotebook replaces the notebook, so send a copy with only the name changed
+		updated := *nb
+		updated.Name = &args[1]
+		if _, err := ns.UpdateNotebook(context.Background(), token, &updated); err != nil {
+			return fmt.Errorf("failed to rename notebook: %w", formatAPIError(err))
+		}
+		return printNotebookResult(cmd.OutOrStdout(), "renamed", &updated)
+	},
+}
+
+// notebooksSetDefaultCmd makes a notebook the default for new notes.
+var notebooksSetDefaultCmd = &cobra.Command{
+	Use:   "set-default <notebook>",
+	Short: "Make a notebook the default for new notes",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		nb, err := newNameResolver(ns, token).notebook(args[0])
+		if err != nil {
+			return err
+		}
+
+		updated := *nb
+		isDefault := true
+		updated.DefaultNotebook = &isDefault
+		if _, err := ns.UpdateNotebook(context.Background(), token, &updated); err != nil {
+			return fmt.Errorf("failed to set default notebook: %w", formatAPIError(err))
+		}
+		return printNotebookResult(cmd.OutOrStdout(), "set as default", &updated)
+	},
+}
+
+// notebooksDeleteCmd permanently deletes a notebook.
+var notebooksDeleteCmd = &cobra.Command{
+	Use:   "delete <notebook>",
+	Short: "Delete a notebook",
+	Long: `Permanently delete a notebook. Evernote moves the notes it contains to the
+trash. You are asked to confirm first unless --yes is given. Evernote only
+allows this with a full-access API key.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		nb, err := newNameResolver(ns, token).notebook(args[0])
+		if err != nil {
+			return err
+		}
+		if nb.GetDefaultNotebook() {
+			return fmt.Errorf("%q is the default notebook; set another default first", nb.GetName())
+		}
+
+		if !notebookYes && !confirm(cmd, fmt.Sprintf("Delete notebook %q?", nb.GetName())) {
+			fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+			return nil
+		}
+
+		if _, err := ns.ExpungeNotebook(context.Background(), token, nb.GetGUID()); err != nil {
+			return fmt.Errorf("failed to delete notebook: %w", formatAPIError(err))
+		}
+		return printNotebookResult(cmd.OutOrStdout(), "deleted", nb)
+	},
+}
+
+// notebookStackGroup is a stack and the notebooks in it.
+type notebookStackGroup struct {
+	Stack     string           `json:"stack"`
+	Notebooks []*edam.Notebook `json:"notebooks"`
+}
+
+// groupNotebooksByStack groups notebooks by stack, sorted by name, with
+// notebooks outside any stack last.
+func groupNotebooksByStack(notebooks []*edam.Notebook) []notebookStackGroup {
+	byStack := make(map[string][]*edam.Notebook)
+	for _, nb := range notebooks {
+		byStack[nb.GetStack()] = append(byStack[nb.GetStack()], nb)
+	}
+
+	var groups []notebookStackGroup
+	for stack, nbs := range byStack {
+		sort.Slice(nbs, func(i, j int) bool {
+			return strings.ToLower(nbs[i].GetName()) < strings.ToLower(nbs[j].GetName())
+		})
+		groups = append(groups, notebookStackGroup{Stack: stack, Notebooks: nbs})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].Stack == "") != (groups[j].Stack == "") {
+			return groups[j].Stack == ""
+		}
+		return strings.ToLower(groups[i].Stack) < strings.ToLower(groups[j].Stack)
+	})
+	return groups
+}
+
+// notebooksStacksCmd lists notebooks grouped by stack.
+var notebooksStacksCmd = &cobra.Command{
+	Use:   "stacks",
+	Short: "List notebooks grouped by stack",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		notebooks, err := ns.ListNotebooks(context.Background(), token)
+		if err != nil {
+			return fmt.Errorf("failed to list notebooks: %w", formatAPIError(err))
+		}
+		groups := groupNotebooksByStack(notebooks)
+
+		if jsonFlag {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(groups)
+		}
+
+		if len(groups) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No notebooks found.")
+			return nil
+		}
+
+		for i, g := range groups {
+			if i > 0 {
+				fmt.Fprintln(cmd.OutOrStdout())
+			}
+			if g.Stack == "" {
+				fmt.Fprintln(cmd.OutOrStdout(), "(no stack)")
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), g.Stack)
+			}
+			for j, nb := range g.Notebooks {
+				branch := "├── "
+				if j == len(g.Notebooks)-1 {
+					branch = "└── "
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s%s", branch, nb.GetName())
+				if nb.GetDefaultNotebook() {
+					fmt.Fprint(cmd.OutOrStdout(), " (default)")
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), " [%s]\n", nb.GetGUID())
+			}
+		}
+
+		return nil
+	},
+}
+
 func init() {
+	notebooksCreateCmd.Flags().StringVar(&notebookStack, "stack", "", "stack to put the notebook in")
+	notebooksDeleteCmd.Flags().BoolVarP(&notebookYes, "yes", "y", false, "do not ask for confirmation")
+	notebooksCmd.AddCommand(notebooksCreateCmd, notebooksRenameCmd, notebooksDeleteCmd, notebooksSetDefaultCmd, notebooksStacksCmd)
 	rootCmd.AddCommand(notebooksCmd)
 }
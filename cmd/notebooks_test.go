@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/dreampuf/evernote-sdk-golang/edam"
@@ -123,3 +124,119 @@ func TestNotebooksCommandRegistration(t *testing.T) {
 	}
 	assert.True(t, found, "notebooks command should be registered with root command")
 }
+
+// testNotebooks returns a mock notebook list with a default notebook and a stack.
+func testNotebooks() []*edam.Notebook {
+	inbox, inboxGUID, isDefault := "Inbox", edam.GUID("nb-1"), true
+	alpha, alphaGUID, stack := "Alpha", edam.GUID("nb-2"), "Projects"
+	beta, betaGUID := "beta", edam.GUID("nb-3")
+	return []*edam.Notebook{
+		{Name: &inbox, GUID: &inboxGUID, DefaultNotebook: &isDefault},
+		{Name: &beta, GUID: &betaGUID, Stack: &stack},
+		{Name: &alpha, GUID: &alphaGUID, Stack: &stack},
+	}
+}
+
+func TestNotebooksSubcommands(t *testing.T) {
+	jsonFlag = false
+
+	t.Run("create in a stack", func(t *testing.T) {
+		mock := &mockNoteStore{}
+		defer setMockNoteStore(mock)()
+
+		notebookStack = "Projects"
+		defer func() { notebookStack = "" }()
+
+		var buf bytes.Buffer
+		notebooksCreateCmd.SetOut(&buf)
+		require.NoError(t, notebooksCreateCmd.RunE(notebooksCreateCmd, []string{"Project X"}))
+
+		require.Len(t, mock.savedNotebooks, 1)
+		assert.Equal(t, "Project X", mock.savedNotebooks[0].GetName())
+		assert.Equal(t, "Projects", mock.savedNotebooks[0].GetStack())
+		assert.Contains(t, buf.String(), "Notebook created: Project X")
+		assert.Contains(t, buf.String(), "GUID: new-notebook-guid")
+	})
+
+	t.Run("create rejects bad names", func(t *testing.T) {
+		err := notebooksCreateCmd.RunE(notebooksCreateCmd, []string{" padded"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "whitespace")
+	})
+
+	t.Run("rename keeps other fields", func(t *testing.T) {
+		mock := &mockNoteStore{notebooks: testNotebooks()}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		notebooksRenameCmd.SetOut(&buf)
+		require.NoError(t, notebooksRenameCmd.RunE(notebooksRenameCmd, []string{"alpha", "Gamma"}))
+
+		require.Len(t, mock.savedNotebooks, 1)
+		assert.Equal(t, edam.GUID("nb-2"), mock.savedNotebooks[0].GetGUID())
+		assert.Equal(t, "Gamma", mock.savedNotebooks[0].GetName())
+		assert.Equal(t, "Projects", mock.savedNotebooks[0].GetStack())
+		assert.Equal(t, "Alpha", mock.notebooks[2].GetName())
+		assert.Contains(t, buf.String(), "Notebook renamed: Gamma")
+	})
+
+	t.Run("set default", func(t *testing.T) {
+		mock := &mockNoteStore{notebooks: testNotebooks()}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		notebooksSetDefaultCmd.SetOut(&buf)
+		require.NoError(t, notebooksSetDefaultCmd.RunE(notebooksSetDefaultCmd, []string{"nb-3"}))
+		require.Len(t, mock.savedNotebooks, 1)
+		assert.True(t, mock.savedNotebooks[0].GetDefaultNotebook())
+		assert.Contains(t, buf.String(), "Notebook set as default: beta")
+	})
+
+	t.Run("delete asks for confirmation", func(t *testing.T) {
+		mock := &mockNoteStore{notebooks: testNotebooks()}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		notebooksDeleteCmd.SetOut(&buf)
+		notebooksDeleteCmd.SetIn(strings.NewReader("n\n"))
+		require.NoError(t, notebooksDeleteCmd.RunE(notebooksDeleteCmd, []string{"Alpha"}))
+		assert.Contains(t, buf.String(), "Aborted.")
+		assert.Nil(t, mock.expungedNotebooks)
+
+		notebooksDeleteCmd.SetIn(strings.NewReader("y\n"))
+		defer notebooksDeleteCmd.SetIn(nil)
+		require.NoError(t, notebooksDeleteCmd.RunE(notebooksDeleteCmd, []string{"Alpha"}))
+		assert.Equal(t, []edam.GUID{"nb-2"}, mock.expungedNotebooks)
+		assert.Contains(t, buf.String(), "Notebook deleted: Alpha")
+	})
+
+	t.Run("delete refuses the default notebook", func(t *testing.T) {
+		mock := &mockNoteStore{notebooks: testNotebooks()}
+		defer setMockNoteStore(mock)()
+
+		notebookYes = true
+		defer func() { notebookYes = false }()
+		err := notebooksDeleteCmd.RunE(notebooksDeleteCmd, []string{"inbox"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "default notebook")
+		assert.Nil(t, mock.expungedNotebooks)
+	})
+
+	t.Run("stacks tree", func(t *testing.T) {
+		mock := &mockNoteStore{notebooks: testNotebooks()}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		notebooksStacksCmd.SetOut(&buf)
+		require.NoError(t, notebooksStacksCmd.RunE(notebooksStacksCmd, nil))
+		assert.Equal(t, "Projects\n├── Alpha [nb-2]\n└── beta [nb-3]\n\n(no stack)\n└── Inbox (default) [nb-1]\n", buf.String())
+	})
+
+	t.Run("subcommands are registered", func(t *testing.T) {
+		for _, name := range []string{"create", "rename", "delete", "set-default", "stacks"} {
+			sub, _, err := notebooksCmd.Find([]string{name})
+			require.NoError(t, err)
+			assert.Equal(t, name, sub.Name())
+		}
+	})
+}
@@ -0,0 +1,374 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// configVersion is the version of the config file format written by this
+// build. Files without a version hold a single account and are migrated into
+// the default profile when read.
+const configVersion = 1
+
+// defaultProfile is the profile used when none is chosen, and the one an old
+// single-account config is migrated into.
+const defaultProfile = "default"
+
+// profileFlag is the --profile flag; EVERNOTE_PROFILE is used when it is
+// not given.
+var profileFlag string
+
+var profileYes bool
+
+// validProfileName matches names that are safe to use as directory names.
+var validProfileName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// configFile is the config file: a set of named profiles, each holding the
+// credentials of one account, and the profile used by default.
+type configFile struct {
+	Version  int                `json:"version"`
+	Current  string             `json:"current_profile,omitempty"`
+	Profiles map[string]*Config `json:"profiles"`
+}
+
+// readConfigFile reads the config file. A single-account file from older
+// versions is read as the default profile; it is written in the current
+// format the next time the config is saved.
+func readConfigFile() (*configFile, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	var probe struct {
+		Version *int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	if probe.Version == nil {
+		var c Config
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+		return &configFile{Version: configVersion, Current: defaultProfile, Profiles: map[string]*Config{defaultProfile: &c}}, nil
+	}
+	if *probe.Version > configVersion {
+		return nil, fmt.Errorf("config file %s is version %d, newer than this evernote-cli understands (%d)", configPath, *probe.Version, configVersion)
+	}
+
+	var f configFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if f.Profiles == nil {
+		f.Profiles = map[string]*Config{}
+	}
+	return &f, nil
+}
+
+// writeConfigFile writes the config file, replacing it in one step. The
+// temporary file it is written through is only readable by the user.
+func writeConfigFile(f *configFile) error {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		return err
+	}
+	f.Version = configVersion
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(configPath, data)
+}
+
+// activeProfile returns the profile to use: --profile, then
+// EVERNOTE_PROFILE, then the one chosen with 'profile use'.
+func activeProfile(f *configFile) string {
+	if profileFlag != "" {
+		return profileFlag
+	}
+	if name := os.Getenv("EVERNOTE_PROFILE"); name != "" {
+		return name
+	}
+	if f != nil && f.Current != "" {
+		return f.Current
+	}
+	return defaultProfile
+}
+
+// currentProfile returns the name of the profile in use.
+func currentProfile() string {
+	f, _ := readConfigFile()
+	return activeProfile(f)
+}
+
+// checkProfileName rejects profile names that cannot be used as a directory.
+func checkProfileName(name string) error {
+	if !validProfileName.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, '.', '_' and '-')", name)
+	}
+	return nil
+}
+
+// profileDataDir returns the data directory of the active profile. The data
+// of each profile other than the default is kept apart, so that accounts
+// never share cached notes. A token given by flag or environment may be for
+// any account, so the data of its account is kept apart from every
+// profile's.
+func profileDataDir() (string, error) {
+	token := firstSet(authTokenFlag, os.Getenv("EVERNOTE_AUTH_TOKEN"))
+	f, err := readConfigFile()
+	if err != nil && !os.IsNotExist(err) && token == "" {
+		return "", fmt.Errorf("could not read config: %w", err)
+	}
+	name := activeProfile(f)
+	if err := checkProfileName(name); err != nil {
+		return "", err
+	}
+	if token != "" {
+		env := os.Getenv("EVERNOTE_ENVIRONMENT")
+		if c := f.profile(name); env == "" && c != nil {
+			env = c.Environment
+		}
+		return filepath.Join(dataDir, "accounts", tokenAccountDir(env, token)), nil
+	}
+	if name != defaultProfile {
+		return filepath.Join(dataDir, "profiles", name), nil
+	}
+	return dataDir, nil
+}
+
+// profile returns the named profile, or nil when the file or the profile
+// does not exist.
+func (f *configFile) profile(name string) *Config {
+	if f == nil {
+		return nil
+	}
+	return f.Profiles[name]
+}
+
+// tokenAccountDir names the data directory of the account a token is for:
+// a hash of the environment and the user ID that Evernote tokens carry in
+// their U= field, or of the whole token when it has none.
+func tokenAccountDir(envName, token string) string {
+	if env, err := parseEnvironment(envName); err == nil {
+		envName = env.name
+	}
+	account := token
+	for _, field := range strings.Split(token, ":") {
+		if strings.HasPrefix(field, "U=") {
+			account = field
+			break
+		}
+	}
+	sum := sha256.Sum256([]byte(envName + "\x00" + account))
+	return hex.EncodeToString(sum[:8])
+}
+
+// profileSummary describes a profile in 'profile list --json'.
+type profileSummary struct {
+	Name    string `json:"name"`
+	Current bool   `json:"current"`
+	// Authenticated is whether a token is saved. Secrets kept outside the
+	// config file are not read to check, so such profiles count as
+	// authenticated.
+	Authenticated bool   `json:"authenticated"`
+	SecretStore   string `json:"secret_store"`
+}
+
+// profileCmd groups the profile commands.
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage account profiles",
+	Long: `Keep the credentials of several Evernote accounts side by side, each in a
+named profile. A command uses the profile given with --profile, then the one
+in EVERNOTE_PROFILE, then the one chosen with 'profile use', and otherwise
+the profile named "default". Run 'evernote-cli auth --profile <name>' to add
+a profile.
+
+Each profile other than "default" keeps its local cache separately, and so
+does each account used with a token from --auth-token or
+EVERNOTE_AUTH_TOKEN.
+
+Examples:
+  evernote-cli auth --profile work
+  evernote-cli profile list
+  evernote-cli profile use work
+  EVERNOTE_PROFILE=personal evernote-cli notebooks
+  evernote-cli profile secret-store keyring
+  evernote-cli profile remove work`,
+}
+
+// profileListCmd lists the profiles in the config file.
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := readConfigFile()
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("could not read config: %w", err)
+		}
+		active := activeProfile(f)
+
+		summaries := []profileSummary{}
+		if f != nil {
+			names := make([]string, 0, len(f.Profiles))
+			for name := range f.Profiles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				c := f.Profiles[name]
+				if c == nil {
+					c = &Config{}
+				}
+				store := firstSet(c.SecretStore, secretStorePlaintext)
+				summaries = append(summaries, profileSummary{
+					Name:          name,
+					Current:       name == active,
+					Authenticated: c.AuthToken != "" || store != secretStorePlaintext,
+					SecretStore:   store,
+				})
+			}
+		}
+
+		if jsonFlag {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(summaries)
+		}
+		if len(summaries) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No profiles found. Run 'evernote-cli auth' to add one.")
+			return nil
+		}
+		for _, p := range summaries {
+			marker := " "
+			if p.Current {
+				marker = "*"
+			}
+			state := ""
+			if !p.Authenticated {
+				state = " (not authenticated)"
+			} else if p.SecretStore != secretStorePlaintext {
+				state = " (secrets in " + p.SecretStore + ")"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s%s\n", marker, p.Name, state)
+		}
+		return nil
+	},
+}
+
+// profileUseCmd chooses the profile used by default.
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Use a profile by default",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := readConfigFile()
+		if err != nil {
+			return fmt.Errorf("could not read config: %w", err)
+		}
+		name := args[0]
+		if _, ok := f.Profiles[name]; !ok {
+			return fmt.Errorf("profile %q not found (see 'evernote-cli profile list')", name)
+		}
+		f.Current = name
+		if err := writeConfigFile(f); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Using profile %s\n", name)
+		return nil
+	},
+}
+
+// profileRemoveCmd deletes a profile and its credentials.
+var profileRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := readConfigFile()
+		if err != nil {
+			return fmt.Errorf("could not read config: %w", err)
+		}
+		name := args[0]
+		if _, ok := f.Profiles[name]; !ok {
+			return fmt.Errorf("profile %q not found (see 'evernote-cli profile list')", name)
+		}
+		if !profileYes && !confirm(cmd, fmt.Sprintf("Remove profile %q and its credentials?", name)) {
+			fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+			return nil
+		}
+
+		if c := f.Profiles[name]; c != nil {
+			if err := removeSecrets(name, c.SecretStore); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+			}
+		}
+		delete(f.Profiles, name)
+		if f.Current == name {
+			f.Current = ""
+		}
+		if err := writeConfigFile(f); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed profile %s\n", name)
+		return nil
+	},
+}
+
+// profileSecretStoreCmd moves the active profile's secrets to another store.
+var profileSecretStoreCmd = &cobra.Command{
+	Use:   "secret-store <plaintext|keyring|file>",
+	Short: "Move a profile's secrets to another store",
+	Long: `Move the auth token and client secret of the active profile (or the one
+given with --profile) to another store, and remove them from the old one.
+
+  plaintext  the config file itself, readable only by you
+  keyring    the desktop keyring, through the Secret Service API (secret-tool)
+  file       a file encrypted with age using a passphrase, read from
+             EVERNOTE_SECRET_PASSPHRASE or asked for
+
+Examples:
+  evernote-cli profile secret-store keyring
+  evernote-cli profile secret-store file --profile work`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := args[0]
+		if store == "" {
+			store = secretStorePlaintext
+		}
+		if err := checkSecretStore(store); err != nil {
+			return err
+		}
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("could not read config: %w", err)
+		}
+		cfg.SecretStore = store
+		if err := saveConfig(cfg); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Secrets of profile %s are now kept in the %s store.\n", currentProfile(), store)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "account profile to use (default from EVERNOTE_PROFILE or 'profile use')")
+	profileRemoveCmd.Flags().BoolVarP(&profileYes, "yes", "y", false, "do not ask for confirmation")
+	profileCmd.AddCommand(profileListCmd, profileUseCmd, profileRemoveCmd, profileSecretStoreCmd)
+	rootCmd.AddCommand(profileCmd)
+}
@@ -0,0 +1,231 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setConfigPath points the config file at a temporary directory and clears
+// the profile selection and credentials given by flag or environment for
+// the duration of a test.
+func setConfigPath(t *testing.T) {
+	t.Helper()
+	originalPath, originalProfile := configPath, profileFlag
+	configPath = filepath.Join(t.TempDir(), "auth.json")
+	profileFlag = ""
+	for _, name := range []string{"EVERNOTE_PROFILE", "EVERNOTE_AUTH_TOKEN", "EVERNOTE_NOTESTORE_URL", "EVERNOTE_ENVIRONMENT"} {
+		t.Setenv(name, "")
+	}
+	t.Cleanup(func() {
+		configPath, profileFlag = originalPath, originalProfile
+		authTokenFlag, noteStoreURLFlag = "", ""
+		profileYes = false
+	})
+}
+
+func TestConfigMigration(t *testing.T) {
+	setConfigPath(t)
+	legacy := `{"client_id":"id","client_secret":"secret","auth_token":"token","note_store_url":"https://www.evernote.com/shard/s1/notestore"}`
+	require.NoError(t, os.WriteFile(configPath, []byte(legacy), 0600))
+
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "token", cfg.AuthToken)
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, legacy, string(data), "reading leaves the file alone")
+
+	require.NoError(t, saveConfig(cfg))
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	var f configFile
+	require.NoError(t, json.Unmarshal(data, &f))
+	assert.Equal(t, configVersion, f.Version)
+	assert.Equal(t, "default", f.Current)
+	assert.Equal(t, "id", f.Profiles["default"].ClientID)
+	assert.Equal(t, "https://www.evernote.com/shard/s1/notestore", f.Profiles["default"].NoteStoreURL)
+
+	info, err := os.Stat(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestConfigNewerVersion(t *testing.T) {
+	setConfigPath(t)
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"version": 99, "profiles": {}}`), 0600))
+
+	_, err := loadConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "newer than this evernote-cli understands")
+}
+
+func TestProfileSelection(t *testing.T) {
+	setConfigPath(t)
+	require.NoError(t, saveConfig(&Config{AuthToken: "personal-token"}))
+	profileFlag = "work"
+	require.NoError(t, saveConfig(&Config{AuthToken: "work-token"}))
+	profileFlag = ""
+
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "personal-token", cfg.AuthToken, "the first profile saved is used by default")
+
+	t.Setenv("EVERNOTE_PROFILE", "work")
+	cfg, err = loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "work-token", cfg.AuthToken)
+
+	profileFlag = "default"
+	cfg, err = loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "personal-token", cfg.AuthToken, "--profile wins over EVERNOTE_PROFILE")
+
+	profileFlag = "missing"
+	_, err = loadConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `profile "missing" not found`)
+
+	profileFlag = "../evil"
+	assert.Error(t, saveConfig(&Config{}))
+}
+
+func TestProfileDataDir(t *testing.T) {
+	setConfigPath(t)
+	originalDataDir := dataDir
+	defer func() { dataDir = originalDataDir }()
+
+	dataDir = "data"
+	dir, err := profileDataDir()
+	require.NoError(t, err)
+	assert.Equal(t, "data", dir)
+
+	profileFlag = "work"
+	dir, err = profileDataDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("data", "profiles", "work"), dir)
+
+	t.Run("tokens from the environment", func(t *testing.T) {
+		dirFor := func(token string) string {
+			t.Helper()
+			t.Setenv("EVERNOTE_AUTH_TOKEN", token)
+			dir, err := profileDataDir()
+			require.NoError(t, err)
+			return dir
+		}
+		first := dirFor("S=s1:U=1a:E=100:C=1:P=1:A=en-devtoken:V=2:H=aa")
+		assert.Equal(t, filepath.Join("data", "accounts"), filepath.Dir(first), "not the profile's data")
+		assert.Equal(t, first, dirFor("S=s1:U=1a:E=200:C=2:P=1:A=en-devtoken:V=2:H=bb"), "a new token for the same account")
+		assert.NotEqual(t, first, dirFor("S=s1:U=2b:E=100:C=1:P=1:A=en-devtoken:V=2:H=aa"), "another account")
+
+		t.Setenv("EVERNOTE_ENVIRONMENT", "sandbox")
+		assert.NotEqual(t, first, dirFor("S=s1:U=1a:E=100:C=1:P=1:A=en-devtoken:V=2:H=aa"), "the same user ID on another service")
+	})
+}
+
+func TestCorruptConfig(t *testing.T) {
+	setConfigPath(t)
+	setDataDir(t)
+	require.NoError(t, os.WriteFile(configPath, []byte("{not json"), 0600))
+
+	rootCmd.SetArgs([]string{"version"})
+	rootCmd.SetOut(io.Discard)
+	defer func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetOut(nil)
+	}()
+	require.NoError(t, rootCmd.Execute(), "commands that need no config still run")
+
+	_, err := profileDataDir()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not read config")
+
+	t.Setenv("EVERNOTE_AUTH_TOKEN", "S=s1:U=1a")
+	_, err = profileDataDir()
+	assert.NoError(t, err, "a token from the environment needs no profile")
+}
+
+func TestProfileCommands(t *testing.T) {
+	setConfigPath(t)
+	require.NoError(t, saveConfig(&Config{AuthToken: "personal-token"}))
+	profileFlag = "work"
+	require.NoError(t, saveConfig(&Config{ClientID: "id"}))
+	profileFlag = ""
+
+	var buf bytes.Buffer
+	profileListCmd.SetOut(&buf)
+	require.NoError(t, profileListCmd.RunE(profileListCmd, nil))
+	assert.Equal(t, "* default\n  work (not authenticated)\n", buf.String())
+
+	buf.Reset()
+	profileUseCmd.SetOut(&buf)
+	require.NoError(t, profileUseCmd.RunE(profileUseCmd, []string{"work"}))
+	assert.Equal(t, "Using profile work\n", buf.String())
+	assert.Equal(t, "work", currentProfile())
+
+	err := profileUseCmd.RunE(profileUseCmd, []string{"other"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `profile "other" not found`)
+
+	t.Run("list as JSON", func(t *testing.T) {
+		jsonFlag = true
+		defer func() { jsonFlag = false }()
+		buf.Reset()
+		require.NoError(t, profileListCmd.RunE(profileListCmd, nil))
+		var summaries []profileSummary
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &summaries))
+		assert.Equal(t, []profileSummary{{Name: "default", Authenticated: true, SecretStore: "plaintext"}, {Name: "work", Current: true, SecretStore: "plaintext"}}, summaries)
+	})
+
+	t.Run("remove asks first", func(t *testing.T) {
+		buf.Reset()
+		profileRemoveCmd.SetOut(&buf)
+		profileRemoveCmd.SetIn(strings.NewReader("n\n"))
+		require.NoError(t, profileRemoveCmd.RunE(profileRemoveCmd, []string{"work"}))
+		assert.Contains(t, buf.String(), "Aborted.")
+		assert.Equal(t, "work", currentProfile())
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		profileYes = true
+		buf.Reset()
+		require.NoError(t, profileRemoveCmd.RunE(profileRemoveCmd, []string{"work"}))
+		assert.Equal(t, "Removed profile work\n", buf.String())
+		assert.Equal(t, "default", currentProfile())
+		cfg, err := loadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, "personal-token", cfg.AuthToken)
+	})
+}
+
+func TestProfileCmdConfiguration(t *testing.T) {
+	assert.Equal(t, "profile", profileCmd.Use)
+	assert.Equal(t, "Manage account profiles", profileCmd.Short)
+	assert.NotNil(t, rootCmd.PersistentFlags().Lookup("profile"))
+
+	names := []string{}
+	for _, sub := range profileCmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	assert.ElementsMatch(t, []string{"list", "use", "remove", "secret-store"}, names)
+}
+
+func TestProfileCommandRegistration(t *testing.T) {
+	found := false
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == "profile" {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "profile command should be registered with root command")
+}
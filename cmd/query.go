@@ -0,0 +1,333 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+)
+
+// queryTerm is one term of an Evernote search query.
+type queryTerm struct {
+	// field is the operator before the colon, or empty for plain words and
+	// quoted phrases.
+	field string
+	// value is lower-cased with quotes and a trailing "*" removed.
+	value  string
+	negate bool
+	// prefix is set when the value ended in "*" to match any word starting
+	// with it.
+	prefix bool
+}
+
+// searchQuery is a parsed Evernote search query.
+type searchQuery struct {
+	terms []queryTerm
+	// any is set by a leading any: and matches notes with any of the terms
+	// instead of all of them.
+	any bool
+}
+
+// relativeDatePattern matches relative dates such as day, week-1 or month+2.
+var relativeDatePattern = regexp.MustCompile(`^(day|week|month|year)([+-]\d+)?$`)
+
+// parseSearchQuery parses the parts of the Evernote search grammar that can
+// be answered locally: words and word* prefixes, "quoted phrases", intitle:,
+// tag:, notebook:, created:, updated: and any:, each optionally negated with
+// a leading "-".
+func parseSearchQuery(query string) (*searchQuery, error) {
+	q := &searchQuery{}
+	var b strings.Builder
+	quoted := false
+	var err error
+	flush := func() {
+		raw := b.String()
+		b.Reset()
+		if raw == "" || raw == "-" || err != nil {
+			return
+		}
+		var t queryTerm
+		token := raw
+		if strings.HasPrefix(token, "-") {
+			t.negate = true
+			token = token[1:]
+		}
+		if i := strings.Index(token, ":"); i > 0 && !strings.HasPrefix(token, `"`) {
+			t.field = strings.ToLower(token[:i])
+			token = token[i+1:]
+		}
+		token = strings.ToLower(strings.ReplaceAll(token, `"`, ""))
+		if strings.HasSuffix(token, "*") {
+			t.prefix = true
+			token = strings.TrimSuffix(token, "*")
+		}
+		t.value = token
+
+		switch t.field {
+		case "any":
+			q.any = true
+			return
+		case "", "intitle", "tag", "notebook":
+		case "created", "updated":
+			if _, dateErr := grammarTime(t.value, timeNow()); dateErr != nil {
+				err = dateErr
+				return
+			}
+		default:
+			err = fmt.Errorf("search term %q is not supported locally", raw)
+			return
+		}
+		q.terms = append(q.terms, t)
+	}
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			b.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			flush()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	flush()
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// grammarTime parses a date from the search grammar: an absolute date such
+// as 20260102 or 20260102T150405Z, or a date relative to now such as day,
+// day-7, week, month-1 or year. Weeks start on Sunday.
+func grammarTime(value string, now time.Time) (time.Time, error) {
+	if m := relativeDatePattern.FindStringSubmatch(value); m != nil {
+		n := 0
+		if m[2] != "" {
+			n, _ = strconv.Atoi(m[2])
+		}
+		y, mo, d := now.Date()
+		today := time.Date(y, mo, d, 0, 0, 0, 0, now.Location())
+		switch m[1] {
+		case "day":
+			return today.AddDate(0, 0, n), nil
+		case "week":
+			return today.AddDate(0, 0, -int(today.Weekday())+7*n), nil
+		case "month":
+			return time.Date(y, mo, 1, 0, 0, 0, 0, now.Location()).AddDate(0, n, 0), nil
+		default:
+			return time.Date(y, 1, 1, 0, 0, 0, 0, now.Location()).AddDate(n, 0, 0), nil
+		}
+	}
+	if t, err := time.Parse("20060102t150405z", value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"20060102t150405", "20060102"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q in search (use 20060102, 20060102T150405Z or day, week, month or year with an optional -N)", value)
+}
+
+// highlightWords returns the words and word prefixes of the query that
+// should be highlighted in snippets.
+func (q *searchQuery) highlightWords() (words, prefixes []string) {
+	for _, t := range q.terms {
+		if t.negate || (t.field != "" && t.field != "intitle") {
+			continue
+		}
+		tokens := tokenize(t.value)
+		for i, tok := range tokens {
+			if t.prefix && i == len(tokens)-1 {
+				prefixes = append(prefixes, tok.text)
+			} else {
+				words = append(words, tok.text)
+			}
+		}
+	}
+	return words, prefixes
+}
+
+// localSearchResult is a note found by a local search.
+type localSearchResult struct {
+	*edam.NoteMetadata
+	NotebookName string   `json:"notebookName,omitempty"`
+	TagNames     []string `json:"tagNames,omitempty"`
+	Score        float64  `json:"score"`
+	Snippet      string   `json:"snippet,omitempty"`
+}
+
+// nameMatches reports whether a notebook or tag name matches a query value.
+func nameMatches(name string, t queryTerm) bool {
+	name = strings.ToLower(name)
+	if t.prefix {
+		return strings.HasPrefix(name, t.value)
+	}
+	return name == t.value
+}
+
+// search runs a query against the index, keeping notes that also match the
+// notebook, tags and trash state of the filter, sorted in the filter's
+// order. Notebook and tag names in the query are resolved with the given
+// lists.
+func (ix *searchIndex) search(q *searchQuery, filter *edam.NoteFilter, notebooks []*edam.Notebook, tags []*edam.Tag, now time.Time) []*localSearchResult {
+	n, avgLength := ix.liveDocs()
+	scores := make([]float64, n)
+	matched := make([]int, n)
+	counted := 0
+
+	for _, t := range q.terms {
+		docs := map[int32]bool{}
+		switch t.field {
+		case "", "intitle":
+			var words []string
+			for _, tok := range tokenize(t.value) {
+				words = append(words, tok.text)
+			}
+			if len(words) == 0 {
+				continue
+			}
+			hits := ix.match(words, t.prefix, t.field == "intitle")
+			weight := idf(n, len(hits))
+			for doc, h := range hits {
+				docs[doc] = true
+				if !t.negate {
+					scores[doc] += bm25(h, weight, ix.Docs[doc].Length, avgLength)
+				}
+			}
+		case "tag":
+			guids := map[edam.GUID]bool{}
+			for _, tag := range tags {
+				if nameMatches(tag.GetName(), t) {
+					guids[tag.GetGUID()] = true
+				}
+			}
+			for id, doc := range ix.Docs {
+				for _, g := range doc.Note.GetTagGuids() {
+					if guids[g] {
+						docs[int32(id)] = true
+					}
+				}
+			}
+		case "notebook":
+			guids := map[string]bool{}
+			for _, nb := range notebooks {
+				if nameMatches(nb.GetName(), t) {
+					guids[string(nb.GetGUID())] = true
+				}
+			}
+			for id, doc := range ix.Docs {
+				if guids[doc.Note.GetNotebookGuid()] {
+					docs[int32(id)] = true
+				}
+			}
+		case "created", "updated":
+			since, _ := grammarTime(t.value, now)
+			for id, doc := range ix.Docs {
+				ts := doc.Note.GetCreated()
+				if t.field == "updated" {
+					ts = doc.Note.GetUpdated()
+				}
+				if int64(ts) >= since.UnixMilli() {
+					docs[int32(id)] = true
+				}
+			}
+		}
+
+		counted++
+		for id := range ix.Docs {
+			if docs[int32(id)] != t.negate {
+				matched[id]++
+			}
+		}
+	}
+
+	var results []*localSearchResult
+	for id, doc := range ix.Docs {
+		if counted > 0 && ((q.any && matched[id] == 0) || (!q.any && matched[id] < counted)) {
+			continue
+		}
+		if doc.Active == filter.GetInactive() {
+			continue
+		}
+		if filter.NotebookGuid != nil && edam.GUID(doc.Note.GetNotebookGuid()) != filter.GetNotebookGuid() {
+			continue
+		}
+		if !hasAllTags(doc.Note.GetTagGuids(), filter.GetTagGuids()) {
+			continue
+		}
+		results = append(results, &localSearchResult{NoteMetadata: doc.Note, Score: scores[id]})
+	}
+	sortLocalResults(results, edam.NoteSortOrder(filter.GetOrder()), filter.GetAscending())
+	return results
+}
+
+// hasAllTags reports whether have contains every tag in want.
+func hasAllTags(have, want []edam.GUID) bool {
+	for _, w := range want {
+		found := false
+		for _, g := range have {
+			if g == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// sortLocalResults sorts results the way Evernote would for the sort order,
+// best or newest first unless ascending. Ties are broken by update time.
+func sortLocalResults(results []*localSearchResult, order edam.NoteSortOrder, ascending bool) {
+	less := func(a, b *localSearchResult) bool { return a.GetUpdated() < b.GetUpdated() }
+	switch order {
+	case edam.NoteSortOrder_RELEVANCE:
+		less = func(a, b *localSearchResult) bool {
+			if a.Score != b.Score {
+				return a.Score < b.Score
+			}
+			return a.GetUpdated() < b.GetUpdated()
+		}
+	case edam.NoteSortOrder_CREATED:
+		less = func(a, b *localSearchResult) bool { return a.GetCreated() < b.GetCreated() }
+	case edam.NoteSortOrder_TITLE:
+		less = func(a, b *localSearchResult) bool {
+			return strings.ToLower(a.GetTitle()) < strings.ToLower(b.GetTitle())
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if ascending {
+			return less(results[i], results[j])
+		}
+		return less(results[j], results[i])
+	})
+}
+
+// searchLocalStore runs a filter's query against the index of the local
+// store.
+func searchLocalStore(store *localStore, ix *searchIndex, filter *edam.NoteFilter) ([]*localSearchResult, *searchQuery, error) {
+	q, err := parseSearchQuery(filter.GetWords())
+	if err != nil {
+		return nil, nil, err
+	}
+	notebooks, err := store.notebooks()
+	if err != nil {
+		return nil, nil, err
+	}
+	tags, err := store.tags()
+	if err != nil {
+		return nil, nil, err
+	}
+	return ix.search(q, filter, notebooks, tags, timeNow()), q, nil
+}
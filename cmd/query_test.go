@@ -0,0 +1,131 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	q, err := parseSearchQuery(`any: Rome -tag:"to do" intitle:trip* "Road Trip" created:day-7`)
+	require.NoError(t, err)
+	assert.True(t, q.any)
+	assert.Equal(t, []queryTerm{
+		{value: "rome"},
+		{field: "tag", value: "to do", negate: true},
+		{field: "intitle", value: "trip", prefix: true},
+		{value: "road trip"},
+		{field: "created", value: "day-7"},
+	}, q.terms)
+
+	_, err = parseSearchQuery("source:mobile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `search term "source:mobile" is not supported locally`)
+
+	_, err = parseSearchQuery("created:yesterday")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid date "yesterday"`)
+}
+
+func TestGrammarTime(t *testing.T) {
+	// Thursday 2026-10-15 14:30 UTC
+	now := time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"day":              time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC),
+		"day-7":            time.Date(2026, 10, 8, 0, 0, 0, 0, time.UTC),
+		"week":             time.Date(2026, 10, 11, 0, 0, 0, 0, time.UTC),
+		"week-1":           time.Date(2026, 10, 4, 0, 0, 0, 0, time.UTC),
+		"month-1":          time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		"year":             time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		"20250301":         time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		"20250301t101500z": time.Date(2025, 3, 1, 10, 15, 0, 0, time.UTC),
+	}
+	for value, want := range tests {
+		got, err := grammarTime(value, now)
+		require.NoError(t, err, value)
+		assert.True(t, want.Equal(got), "%s: got %s", value, got)
+	}
+}
+
+// queryTestIndex returns an index over a few notes, with the notebooks and
+// tags they use.
+func queryTestIndex(t *testing.T) (*searchIndex, []*edam.Notebook, []*edam.Tag) {
+	t.Helper()
+	travel, work := edam.GUID("tag-travel"), edam.GUID("tag-work")
+	tags := []*edam.Tag{{GUID: &travel, Name: strRef("Travel")}, {GUID: &work, Name: strRef("Work")}}
+	personal, office := edam.GUID("nb-personal"), edam.GUID("nb-office")
+	notebooks := []*edam.Notebook{{GUID: &personal, Name: strRef("Personal")}, {GUID: &office, Name: strRef("Office")}}
+
+	day := int64(24 * time.Hour / time.Millisecond)
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC).UnixMilli()
+	notes := []*edam.Note{
+		queryTestNote("n-1", "Road trip packing", "Passport, charger and snacks for the road trip.", personal, now-2*day, travel),
+		queryTestNote("n-2", "Rome", "Book the train from Rome to Florence. Road closures expected.", personal, now-20*day, travel),
+		queryTestNote("n-3", "Quarterly planning", "Trip budget for the offsite and the roadmap.", office, now-1*day, work),
+	}
+	ix := &searchIndex{Terms: map[string][]posting{}, byGUID: map[edam.GUID]int32{}}
+	for _, note := range notes {
+		ix.add(note, 0)
+	}
+	return ix, notebooks, tags
+}
+
+func strRef(s string) *string { return &s }
+
+func queryTestNote(guid, title, body string, notebook edam.GUID, updated int64, tags ...edam.GUID) *edam.Note {
+	g, nb, content, ts := edam.GUID(guid), string(notebook), wrapENML(body), edam.Timestamp(updated)
+	return &edam.Note{GUID: &g, Title: &title, Content: &content, NotebookGuid: &nb, Created: &ts, Updated: &ts, TagGuids: tags}
+}
+
+func TestSearchIndexQueries(t *testing.T) {
+	ix, notebooks, tags := queryTestIndex(t)
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	relevance := int32(edam.NoteSortOrder_RELEVANCE)
+
+	search := func(query string) []edam.GUID {
+		t.Helper()
+		q, err := parseSearchQuery(query)
+		require.NoError(t, err)
+		var guids []edam.GUID
+		for _, r := range ix.search(q, &edam.NoteFilter{Order: &relevance}, notebooks, tags, now) {
+			guids = append(guids, r.GetGUID())
+		}
+		return guids
+	}
+
+	assert.Equal(t, []edam.GUID{"n-1", "n-2"}, search("road"))
+	assert.Equal(t, []edam.GUID{"n-1"}, search(`"road trip"`))
+	assert.Empty(t, search(`"rome book"`), "phrases do not span the title and the body")
+	assert.ElementsMatch(t, []edam.GUID{"n-1", "n-2", "n-3"}, search("road*"))
+	assert.Equal(t, []edam.GUID{"n-2"}, search("intitle:rome"))
+	assert.Equal(t, []edam.GUID{"n-1"}, search("trip tag:travel"))
+	assert.Equal(t, []edam.GUID{"n-3"}, search("trip -tag:travel"))
+	assert.Equal(t, []edam.GUID{"n-3"}, search("notebook:office"))
+	assert.Equal(t, []edam.GUID{"n-3", "n-1"}, search("updated:day-7"))
+	assert.Equal(t, []edam.GUID{"n-2"}, search("-updated:day-7"))
+	assert.ElementsMatch(t, []edam.GUID{"n-2", "n-3"}, search("any: florence budget"))
+	assert.Equal(t, []edam.GUID{"n-3", "n-1", "n-2"}, search("tag:*"))
+	assert.Empty(t, search("tag:missing"))
+}
+
+func TestSearchIndexRanking(t *testing.T) {
+	ix, notebooks, tags := queryTestIndex(t)
+	q, err := parseSearchQuery("trip")
+	require.NoError(t, err)
+	relevance := int32(edam.NoteSortOrder_RELEVANCE)
+	results := ix.search(q, &edam.NoteFilter{Order: &relevance}, notebooks, tags, time.Now())
+	require.Len(t, results, 2)
+	// A title match ranks above a body match
+	assert.Equal(t, edam.GUID("n-1"), results[0].GetGUID())
+	assert.Greater(t, results[0].Score, results[1].Score)
+
+	title := int32(edam.NoteSortOrder_TITLE)
+	ascending := true
+	results = ix.search(q, &edam.NoteFilter{Order: &title, Ascending: &ascending}, notebooks, tags, time.Now())
+	assert.Equal(t, "Quarterly planning", results[0].GetTitle())
+}
@@ -0,0 +1,217 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+)
+
+// nameResolver maps notebook, tag and saved search names to GUIDs and back.
+// Each list is fetched at most once, so a resolver should live for a
+// single command invocation.
+type nameResolver struct {
+	ns              noteStoreClient
+	token           string
+	notebooks       []*edam.Notebook
+	notebooksErr    error
+	notebooksLoaded bool
+	tags            []*edam.Tag
+	tagsErr         error
+	tagsLoaded      bool
+	searches        []*edam.SavedSearch
+	searchesErr     error
+	searchesLoaded  bool
+}
+
+// newNameResolver returns a resolver that lists notebooks and tags on first use.
+func newNameResolver(ns noteStoreClient, token string) *nameResolver {
+	return &nameResolver{ns: ns, token: token}
+}
+
+// listNotebooks returns the account's notebooks, fetching them on first use.
+func (r *nameResolver) listNotebooks() ([]*edam.Notebook, error) {
+	if !r.notebooksLoaded {
+		r.notebooksLoaded = true
+		r.notebooks, r.notebooksErr = r.ns.ListNotebooks(context.Background(), r.token)
+		if r.notebooksErr != nil {
+			r.notebooksErr = fmt.Errorf("failed to list notebooks: %w", formatAPIError(r.notebooksErr))
+		}
+	}
+	return r.notebooks, r.notebooksErr
+}
+
+// listTags returns the account's tags, fetching them on first use.
+func (r *nameResolver) listTags() ([]*edam.Tag, error) {
+	if !r.tagsLoaded {
+		r.tagsLoaded = true
+		r.tags, r.tagsErr = r.ns.ListTags(context.Background(), r.token)
+		if r.tagsErr != nil {
+			r.tagsErr = fmt.Errorf("failed to list tags: %w", formatAPIError(r.tagsErr))
+		}
+	}
+	return r.tags, r.tagsErr
+}
+
+// listSearches returns the account's saved searches, fetching them on first use.
+func (r *nameResolver) listSearches() ([]*edam.SavedSearch, error) {
+	if !r.searchesLoaded {
+		r.searchesLoaded = true
+		r.searches, r.searchesErr = r.ns.ListSearches(context.Background(), r.token)
+		if r.searchesErr != nil {
+			r.searchesErr = fmt.Errorf("failed to list saved searches: %w", formatAPIError(r.searchesErr))
+		}
+	}
+	return r.searches, r.searchesErr
+}
+
+// matchName picks the item whose GUID equals s, or else the single item
+// whose name matches s case-insensitively.
+func matchName(kind, s string, n int, guid func(int) edam.GUID, name func(int) string) (int, error) {
+	for i := 0; i < n; i++ {
+		if string(guid(i)) == s {
+			return i, nil
+		}
+	}
+	var matches []int
+	for i := 0; i < n; i++ {
+		if strings.EqualFold(name(i), s) {
+			matches = append(matches, i)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return -1, fmt.Errorf("%s %q not found", kind, s)
+	case 1:
+		return matches[0], nil
+	}
+	candidates := make([]string, len(matches))
+	for i, m := range matches {
+		candidates[i] = fmt.Sprintf("%s (%s)", name(m), guid(m))
+	}
+	return -1, fmt.Errorf("%s name %q is ambiguous, use a GUID instead: %s", kind, s, strings.Join(candidates, ", "))
+}
+
+// notebook returns the notebook with the given GUID or name.
+func (r *nameResolver) notebook(nameOrGUID string) (*edam.Notebook, error) {
+	notebooks, err := r.listNotebooks()
+	if err != nil {
+		return nil, err
+	}
+	i, err := matchName("notebook", nameOrGUID, len(notebooks),
+		func(i int) edam.GUID { return notebooks[i].GetGUID() },
+		func(i int) string { return notebooks[i].GetName() })
+	if err != nil {
+		return nil, err
+	}
+	return notebooks[i], nil
+}
+
+// tag returns the tag with the given GUID or name.
+func (r *nameResolver) tag(nameOrGUID string) (*edam.Tag, error) {
+	tags, err := r.listTags()
+	if err != nil {
+		return nil, err
+	}
+	i, err := matchName("tag", nameOrGUID, len(tags),
+		func(i int) edam.GUID { return tags[i].GetGUID() },
+		func(i int) string { return tags[i].GetName() })
+	if err != nil {
+		return nil, err
+	}
+	return tags[i], nil
+}
+
+// savedSearch returns the saved search with the given GUID or name.
+func (r *nameResolver) savedSearch(nameOrGUID string) (*edam.SavedSearch, error) {
+	searches, err := r.listSearches()
+	if err != nil {
+		return nil, err
+	}
+	i, err := matchName("saved search", nameOrGUID, len(searches),
+		func(i int) edam.GUID { return searches[i].GetGUID() },
+		func(i int) string { return searches[i].GetName() })
+	if err != nil {
+		return nil, err
+	}
+	return searches[i], nil
+}
+
+// notebookName returns the name of the notebook with the given GUID, or the
+// GUID itself if the notebook cannot be found.
+func (r *nameResolver) notebookName(guid string) string {
+	notebooks, _ := r.listNotebooks()
+	for _, nb := range notebooks {
+		if string(nb.GetGUID()) == guid {
+			return nb.GetName()
+		}
+	}
+	return guid
+}
+
+// tagNames returns the names of the tags with the given GUIDs, falling back
+// to the GUID for any tag that cannot be found.
+func (r *nameResolver) tagNames(guids []edam.GUID) []string {
+	tags, _ := r.listTags()
+	names := make([]string, len(guids))
+	for i, guid := range guids {
+		names[i] = string(guid)
+		for _, tag := range tags {
+			if tag.GetGUID() == guid {
+				names[i] = tag.GetName()
+				break
+			}
+		}
+	}
+	return names
+}
+
+// namedNote is a note as written with --json, with the names of its
+// notebook and tags next to their GUIDs.
+type namedNote struct {
+	*edam.Note
+	NotebookName string   `json:"notebookName,omitempty"`
+	TagNames     []string `json:"tagNames,omitempty"`
+}
+
+// namedNoteMetadata is a search result as written with --json, with the
+// names of its notebook and tags next to their GUIDs.
+type namedNoteMetadata struct {
+	*edam.NoteMetadata
+	NotebookName string   `json:"notebookName,omitempty"`
+	TagNames     []string `json:"tagNames,omitempty"`
+}
+
+// namedNotesMetadataList is a page of search results as written with --json.
+type namedNotesMetadataList struct {
+	*edam.NotesMetadataList
+	Notes []*namedNoteMetadata `json:"notes"`
+}
+
+// nameNote returns a note with the names of its notebook and tags.
+func (r *nameResolver) nameNote(note *edam.Note) *namedNote {
+	named := &namedNote{Note: note, TagNames: note.GetTagNames()}
+	if note.GetNotebookGuid() != "" {
+		named.NotebookName = r.notebookName(note.GetNotebookGuid())
+	}
+	if len(named.TagNames) == 0 && len(note.GetTagGuids()) > 0 {
+		named.TagNames = r.tagNames(note.GetTagGuids())
+	}
+	return named
+}
+
+// nameNoteMetadata returns a search result with the names of its notebook
+// and tags.
+func (r *nameResolver) nameNoteMetadata(note *edam.NoteMetadata) *namedNoteMetadata {
+	named := &namedNoteMetadata{NoteMetadata: note}
+	if note.GetNotebookGuid() != "" {
+		named.NotebookName = r.notebookName(note.GetNotebookGuid())
+	}
+	if len(note.GetTagGuids()) > 0 {
+		named.TagNames = r.tagNames(note.GetTagGuids())
+	}
+	return named
+}
@@ -0,0 +1,75 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameResolver(t *testing.T) {
+	work, workGUID := "Work", edam.GUID("nb-1")
+	work2, work2GUID := "WORK", edam.GUID("nb-2")
+	home, homeGUID := "Home", edam.GUID("nb-3")
+	urgent, urgentGUID := "Urgent", edam.GUID("tag-1")
+	mock := &mockNoteStore{
+		notebooks: []*edam.Notebook{
+			{Name: &work, GUID: &workGUID},
+			{Name: &work2, GUID: &work2GUID},
+			{Name: &home, GUID: &homeGUID},
+		},
+		tags: []*edam.Tag{{Name: &urgent, GUID: &urgentGUID}},
+	}
+	r := newNameResolver(mock, "token")
+
+	t.Run("by name case-insensitively", func(t *testing.T) {
+		nb, err := r.notebook("home")
+		require.NoError(t, err)
+		assert.Equal(t, homeGUID, nb.GetGUID())
+
+		tag, err := r.tag("URGENT")
+		require.NoError(t, err)
+		assert.Equal(t, urgentGUID, tag.GetGUID())
+	})
+
+	t.Run("by guid", func(t *testing.T) {
+		nb, err := r.notebook("nb-2")
+		require.NoError(t, err)
+		assert.Equal(t, "WORK", nb.GetName())
+	})
+
+	t.Run("ambiguous name", func(t *testing.T) {
+		_, err := r.notebook("work")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `notebook name "work" is ambiguous`)
+		assert.Contains(t, err.Error(), "Work (nb-1), WORK (nb-2)")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := r.tag("later")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `tag "later" not found`)
+	})
+
+	t.Run("names for guids", func(t *testing.T) {
+		assert.Equal(t, "Home", r.notebookName("nb-3"))
+		assert.Equal(t, "nb-9", r.notebookName("nb-9"))
+		assert.Equal(t, []string{"Urgent", "tag-9"}, r.tagNames([]edam.GUID{"tag-1", "tag-9"}))
+	})
+
+	t.Run("lists are fetched once", func(t *testing.T) {
+		assert.Equal(t, 2, mock.listCalls)
+	})
+
+	t.Run("list errors", func(t *testing.T) {
+		r := newNameResolver(&mockNoteStore{err: fmt.Errorf("offline")}, "token")
+		_, err := r.notebook("Work")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to list notebooks: offline")
+		assert.Equal(t, "nb-1", r.notebookName("nb-1"))
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"context"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/spf13/cobra"
+)
+
+var restoreQuery string
+
+// restoreCmd moves notes out of the trash.
+var restoreCmd = &cobra.Command{
+	Use:   "restore [guid...]",
+	Short: "Restore notes from the trash",
+	Long: `Restore one or more notes from the trash.
+
+Pass - to read GUIDs from stdin, one per line, or use --query to restore
+every trashed note matching a search.
+
+Examples:
+  evernote-cli restore <guid>
+  evernote-cli restore --query "intitle:report"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		guids, err := noteGUIDsFromInput(cmd, ns, token, args, restoreQuery, true)
+		if err != nil {
+			return err
+		}
+
+		return applyToNotes(cmd, guids, "Restored", "restore", func(guid edam.GUID) error {
+			// UpdateNote requires the title, so fetch it first
+			existing, err := ns.GetNote(context.Background(), token, guid, false, false, false, false)
+			if err != nil {
+				return err
+			}
+			title := existing.GetTitle()
+			active := true
+			_, err = ns.UpdateNote(context.Background(), token, &edam.Note{
+				GUID:   &guid,
+				Title:  &title,
+				Active: &active,
+			})
+			return err
+		})
+	},
+}
+
+func init() {
+	restoreCmd.Flags().StringVar(&restoreQuery, "query", "", "restore every trashed note matching this search query")
+	rootCmd.AddCommand(restoreCmd)
+}
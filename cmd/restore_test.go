@@ -0,0 +1,61 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreCommand(t *testing.T) {
+	t.Run("restore by guid", func(t *testing.T) {
+		title := "Old Note"
+		mock := &mockNoteStore{gotNote: &edam.Note{Title: &title}}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		restoreCmd.SetOut(&buf)
+		err := restoreCmd.RunE(restoreCmd, []string{"note-1"})
+		require.NoError(t, err)
+
+		require.NotNil(t, mock.savedNote)
+		assert.Equal(t, edam.GUID("note-1"), mock.savedNote.GetGUID())
+		assert.Equal(t, "Old Note", mock.savedNote.GetTitle())
+		assert.True(t, mock.savedNote.GetActive())
+		assert.Contains(t, buf.String(), "Restored: note-1")
+	})
+
+	t.Run("query searches the trash", func(t *testing.T) {
+		title := "Old Note"
+		mock := &mockNoteStore{
+			gotNote: &edam.Note{Title: &title},
+			notes:   &edam.NotesMetadataList{TotalNotes: 1, Notes: []*edam.NoteMetadata{{GUID: "note-1"}}},
+		}
+		defer setMockNoteStore(mock)()
+
+		restoreQuery = "report"
+		defer func() { restoreQuery = "" }()
+
+		var buf bytes.Buffer
+		restoreCmd.SetOut(&buf)
+		require.NoError(t, restoreCmd.RunE(restoreCmd, nil))
+		assert.True(t, mock.filter.GetInactive())
+		assert.Contains(t, buf.String(), "Restored: note-1")
+	})
+}
+
+func TestRestoreCmdRegistration(t *testing.T) {
+	found := false
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "restore" {
+			found = true
+			assert.Equal(t, "Restore notes from the trash", c.Short)
+			break
+		}
+	}
+	assert.True(t, found, "restore command should be registered")
+}
@@ -2,14 +2,12 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/dreampuf/evernote-sdk-golang/client"
 	"github.com/dreampuf/evernote-sdk-golang/edam"
 	"github.com/spf13/cobra"
 )
@@ -27,6 +25,12 @@ type Config struct {
 	ClientSecret string `json:"client_secret"`
 	AuthToken    string `json:"auth_token"`
 	NoteStoreURL string `json:"note_store_url"`
+	// Environment is the service the account is on: production, sandbox,
+	// yinxiang or a custom host URL. Empty means production.
+	Environment string `json:"environment,omitempty"`
+	// SecretStore is where the auth token and client secret are kept:
+	// plaintext (in this file), keyring or file. Empty means plaintext.
+	SecretStore string `json:"secret_store,omitempty"`
 }
 
 // noteStoreClient defines the interface for Evernote NoteStore operations.
@@ -38,22 +42,108 @@ type noteStoreClient interface {
 	GetNote(ctx context.Context, authenticationToken string, guid edam.GUID, withContent bool, withResourcesData bool, withResourcesRecognition bool, withResourcesAlternateData bool) (*edam.Note, error)
 	GetResource(ctx context.Context, authenticationToken string, guid edam.GUID, withData bool, withRecognition bool, withAttributes bool, withAlternateData bool) (*edam.Resource, error)
 	UpdateNote(ctx context.Context, authenticationToken string, note *edam.Note) (*edam.Note, error)
+	DeleteNote(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error)
+	ExpungeNote(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error)
+	CreateNotebook(ctx context.Context, authenticationToken string, notebook *edam.Notebook) (*edam.Notebook, error)
+	UpdateNotebook(ctx context.Context, authenticationToken string, notebook *edam.Notebook) (int32, error)
+	ExpungeNotebook(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error)
+	CreateTag(ctx context.Context, authenticationToken string, tag *edam.Tag) (*edam.Tag, error)
+	UpdateTag(ctx context.Context, authenticationToken string, tag *edam.Tag) (int32, error)
+	ExpungeTag(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error)
+	ListSearches(ctx context.Context, authenticationToken string) ([]*edam.SavedSearch, error)
+	CreateSearch(ctx context.Context, authenticationToken string, search *edam.SavedSearch) (*edam.SavedSearch, error)
+	UpdateSearch(ctx context.Context, authenticationToken string, search *edam.SavedSearch) (int32, error)
+	ExpungeSearch(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error)
+	GetSyncState(ctx context.Context, authenticationToken string) (*edam.SyncState, error)
+	GetFilteredSyncChunk(ctx context.Context, authenticationToken string, afterUSN int32, maxEntries int32, filter *edam.SyncChunkFilter) (*edam.SyncChunk, error)
 }
 
 // getNoteStoreFunc returns a NoteStore client and auth token. Can be overridden in tests.
 var getNoteStoreFunc = getDefaultNoteStore
 
-// getDefaultNoteStore loads config and creates a NoteStore client using the Evernote SDK.
+// getRemoteNoteStoreFunc returns a NoteStore client that does not fall back
+// to the cache, and the auth token. Can be overridden in tests.
+var getRemoteNoteStoreFunc = getRemoteNoteStore
+
+// getDefaultNoteStore loads config and creates a NoteStore client using the
+// Evernote SDK. The client caches what it reads in the local store and
+// answers from there with --offline or when Evernote cannot be reached. A
+// store that cannot be opened only costs the cache: the client then talks to
+// Evernote directly, as it does with --no-cache.
 func getDefaultNoteStore() (noteStoreClient, string, error) {
-	cfg, err := loadConfig()
+	if offlineFlag {
+		store, err := openProfileStore()
+		if err != nil {
+			return nil, "", err
+		}
+		return newCachingNoteStore(nil, store, true), "", nil
+	}
+
+	cfg, err := authenticatedConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	ns, err := connectNoteStore(cfg)
+	if err != nil {
+		if store, storeErr := openProfileStore(); storeErr == nil {
+			cache := newCachingNoteStore(nil, store, false)
+			if cache.fallBack(err) {
+				return cache, cfg.AuthToken, nil
+			}
+		}
+		return nil, "", err
+	}
+	if noCacheFlag {
+		return ns, cfg.AuthToken, nil
+	}
+	store, err := openProfileStore()
 	if err != nil {
-		return nil, "", fmt.Errorf("could not read config: %w", err)
+		fmt.Fprintf(cacheWarnings, "Warning: %v; continuing without the local cache.\n", err)
+		return ns, cfg.AuthToken, nil
+	}
+	return newCachingNoteStore(ns, store, false), cfg.AuthToken, nil
+}
+
+// getRemoteNoteStore creates a NoteStore client for commands whose results
+// must come from Evernote, such as sync and export: a network error fails
+// the command instead of being answered from stale cached copies. With
+// --offline the cache is used as asked.
+func getRemoteNoteStore() (noteStoreClient, string, error) {
+	if offlineFlag {
+		return getDefaultNoteStore()
+	}
+	cfg, err := authenticatedConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	ns, err := connectNoteStore(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	return ns, cfg.AuthToken, nil
+}
+
+// authenticatedConfig returns the credentials for a command, failing when
+// there is no auth token.
+func authenticatedConfig() (*Config, error) {
+	cfg, err := resolveCredentials()
+	if err != nil {
+		return nil, err
 	}
 	if cfg.AuthToken == "" {
-		return nil, "", fmt.Errorf("not authenticated, run 'evernote-cli init' or 'evernote-cli auth'")
+		return nil, fmt.Errorf("not authenticated, run 'evernote-cli init' or 'evernote-cli auth', or set EVERNOTE_AUTH_TOKEN")
 	}
+	return cfg, nil
+}
 
-	c := client.NewClient(cfg.ClientID, cfg.ClientSecret, client.PRODUCTION)
+// connectNoteStore connects to the account's NoteStore, looking up its URL
+// when the config does not have it.
+func connectNoteStore(cfg *Config) (*edam.NoteStoreClient, error) {
+	env, err := parseEnvironment(cfg.Environment)
+	if err != nil {
+		return nil, err
+	}
+	c := env.newClient(cfg.ClientID, cfg.ClientSecret)
 
 	var ns *edam.NoteStoreClient
 	if cfg.NoteStoreURL != "" {
@@ -62,33 +152,124 @@ func getDefaultNoteStore() (noteStoreClient, string, error) {
 		ns, err = c.GetNoteStore(context.Background(), cfg.AuthToken)
 	}
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to connect to Evernote: %w", err)
+		return nil, fmt.Errorf("failed to connect to Evernote: %w", err)
 	}
+	return ns, nil
+}
 
-	return ns, cfg.AuthToken, nil
+// authTokenFlag and noteStoreURLFlag give credentials on the command line,
+// ahead of the environment and the profile.
+var (
+	authTokenFlag    string
+	noteStoreURLFlag string
+)
+
+// firstSet returns the first non-empty value.
+func firstSet(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// resolveCredentials works out the credentials for a command. Each comes
+// from its flag, then its environment variable (EVERNOTE_AUTH_TOKEN,
+// EVERNOTE_NOTESTORE_URL, EVERNOTE_ENVIRONMENT), then the profile. A token
+// given by flag or environment never takes the profile's NoteStore URL,
+// which may belong to another account; without one it is looked up.
+func resolveCredentials() (*Config, error) {
+	token := firstSet(authTokenFlag, os.Getenv("EVERNOTE_AUTH_TOKEN"))
+	cfg, err := loadConfig()
+	if err != nil {
+		if token == "" {
+			return nil, fmt.Errorf("could not read config: %w", err)
+		}
+		cfg = &Config{}
+	}
+
+	c := *cfg
+	noteStoreURL := firstSet(noteStoreURLFlag, os.Getenv("EVERNOTE_NOTESTORE_URL"))
+	if token != "" {
+		c.AuthToken = token
+		c.NoteStoreURL = noteStoreURL
+	} else if noteStoreURL != "" {
+		c.NoteStoreURL = noteStoreURL
+	}
+	c.Environment = firstSet(os.Getenv("EVERNOTE_ENVIRONMENT"), c.Environment)
+	return &c, nil
 }
 
-// loadConfig reads the config file from disk.
+// loadConfig reads the credentials of the active profile from the config
+// file.
 func loadConfig() (*Config, error) {
-	data, err := os.ReadFile(configPath)
+	f, err := readConfigFile()
 	if err != nil {
 		return nil, err
 	}
-	var c Config
-	if err := json.Unmarshal(data, &c); err != nil {
-		return nil, err
+	name := activeProfile(f)
+	c, ok := f.Profiles[name]
+	if !ok || c == nil {
+		return nil, profileNotFoundError{name}
 	}
-	return &c, nil
+	if err := loadSecrets(name, c); err != nil {
+		return nil, fmt.Errorf("could not read the secrets of profile %s: %w", name, err)
+	}
+	return c, nil
 }
 
-// saveConfig writes the config file to disk with secure permissions.
+// profileNotFoundError is returned by loadConfig when the active profile is
+// not in the config file.
+type profileNotFoundError struct {
+	name string
+}
+
+func (e profileNotFoundError) Error() string {
+	return fmt.Sprintf("profile %q not found (see 'evernote-cli profile list')", e.name)
+}
+
+// isNoProfile reports whether err means the active profile does not exist
+// yet, as opposed to a config or secret store that cannot be read.
+func isNoProfile(err error) bool {
+	var notFound profileNotFoundError
+	return os.IsNotExist(err) || errors.As(err, &notFound)
+}
+
+// saveConfig stores the credentials of the active profile in the config
+// file, creating the file and the profile as needed. Secrets go to the
+// profile's secret store, which is kept unless c names another.
 func saveConfig(c *Config) error {
-	os.MkdirAll(filepath.Dir(configPath), 0700)
-	data, err := json.MarshalIndent(c, "", "  ")
+	f, err := readConfigFile()
+	if os.IsNotExist(err) {
+		f, err = &configFile{Profiles: map[string]*Config{}}, nil
+	}
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(configPath, data, 0600)
+	name := activeProfile(f)
+	if err := checkProfileName(name); err != nil {
+		return err
+	}
+	var previous string
+	if old := f.Profiles[name]; old != nil {
+		previous = old.SecretStore
+	}
+	if c.SecretStore == "" {
+		// Only an explicit choice moves secrets out of their store.
+		kept := *c
+		kept.SecretStore = previous
+		c = &kept
+	}
+	stored, err := storeSecrets(name, c, previous)
+	if err != nil {
+		return err
+	}
+	f.Profiles[name] = stored
+	if f.Current == "" {
+		f.Current = name
+	}
+	return writeConfigFile(f)
 }
 
 // formatAPIError converts Evernote SDK exceptions into human-readable error messages.
@@ -151,4 +332,8 @@ func Execute() error {
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&jsonFlag, "json", false, "output in JSON format")
+	rootCmd.PersistentFlags().StringVar(&authTokenFlag, "auth-token", "", "Evernote auth or developer token to use instead of the profile's (prefer EVERNOTE_AUTH_TOKEN, as flags are visible to other users)")
+	rootCmd.PersistentFlags().StringVar(&noteStoreURLFlag, "notestore-url", "", "NoteStore URL to use with the token (default from EVERNOTE_NOTESTORE_URL)")
+	rootCmd.PersistentFlags().BoolVar(&offlineFlag, "offline", false, "read from the local cache without contacting Evernote")
+	rootCmd.PersistentFlags().BoolVar(&noCacheFlag, "no-cache", false, "do not copy notes read from Evernote to the local cache")
 }
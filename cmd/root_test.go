@@ -15,79 +15,282 @@ import (
 
 // mockNoteStore implements the noteStoreClient interface for testing.
 type mockNoteStore struct {
-	notebooks   []*edam.Notebook
-	tags        []*edam.Tag
-	notes       *edam.NotesMetadataList
-	createdNote *edam.Note
-	gotNote     *edam.Note
-	updatedNote *edam.Note
-	resource    *edam.Resource
-	err         error
+	notebooks         []*edam.Notebook
+	tags              []*edam.Tag
+	searches          []*edam.SavedSearch
+	notes             *edam.NotesMetadataList
+	trashedNotes      *edam.NotesMetadataList
+	createdNote       *edam.Note
+	gotNote           *edam.Note
+	gotNotes          []*edam.Note
+	updatedNote       *edam.Note
+	resource          *edam.Resource
+	savedNote         *edam.Note
+	createdNotes      []*edam.Note
+	savedNotes        []*edam.Note
+	filter            *edam.NoteFilter
+	pages             int
+	listCalls         int
+	deleted           []edam.GUID
+	expunged          []edam.GUID
+	savedNotebooks    []*edam.Notebook
+	expungedNotebooks []edam.GUID
+	savedTags         []*edam.Tag
+	expungedTags      []edam.GUID
+	savedSearches     []*edam.SavedSearch
+	expungedSearches  []edam.GUID
+	notesByGUID       map[edam.GUID]*edam.Note
+	resourcesByGUID   map[edam.GUID]*edam.Resource
+	syncState         *edam.SyncState
+	syncChunks        []*edam.SyncChunk
+	syncFilter        *edam.SyncChunkFilter
+	chunkCalls        int
+	err               error
 }
 
 // ListNotebooks returns the mock notebooks.
 func (m *mockNoteStore) ListNotebooks(ctx context.Context, authenticationToken string) ([]*edam.Notebook, error) {
+	m.listCalls++
 	return m.notebooks, m.err
 }
 
 // ListTags returns the mock tags.
 func (m *mockNoteStore) ListTags(ctx context.Context, authenticationToken string) ([]*edam.Tag, error) {
+	m.listCalls++
 	return m.tags, m.err
 }
 
-// FindNotesMetadata returns the mock note metadata.
+// FindNotesMetadata records the filter and returns the requested page of the
+// mock note metadata, using trashedNotes for trash searches when it is set.
 func (m *mockNoteStore) FindNotesMetadata(ctx context.Context, authenticationToken string, filter *edam.NoteFilter, offset int32, maxNotes int32, resultSpec *edam.NotesMetadataResultSpec) (*edam.NotesMetadataList, error) {
-	return m.notes, m.err
+	m.filter = filter
+	notes := m.notes
+	if filter.GetInactive() && m.trashedNotes != nil {
+		notes = m.trashedNotes
+	}
+	if m.err != nil || notes == nil {
+		return notes, m.err
+	}
+	// Return the requested page of the mock notes
+	start := int(offset)
+	if start > len(notes.Notes) {
+		start = len(notes.Notes)
+	}
+	end := start + int(maxNotes)
+	if end > len(notes.Notes) {
+		end = len(notes.Notes)
+	}
+	m.pages++
+	return &edam.NotesMetadataList{
+		StartIndex: offset,
+		TotalNotes: notes.TotalNotes,
+		Notes:      notes.Notes[start:end],
+	}, nil
 }
 
-// CreateNote returns the mock created note.
+// CreateNote records the note sent and returns the mock created note.
 func (m *mockNoteStore) CreateNote(ctx context.Context, authenticationToken string, note *edam.Note) (*edam.Note, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	m.savedNote = note
+	m.createdNotes = append(m.createdNotes, note)
 	if m.createdNote != nil {
 		return m.createdNote, nil
 	}
 	return note, nil
 }
 
-// GetNote returns the mock note.
+// GetNote returns the mock note for the GUID if there is one, otherwise the
+// next queued mock note, or the mock note once the queue is empty.
 func (m *mockNoteStore) GetNote(ctx context.Context, authenticationToken string, guid edam.GUID, withContent bool, withResourcesData bool, withResourcesRecognition bool, withResourcesAlternateData bool) (*edam.Note, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	if note, ok := m.notesByGUID[guid]; ok {
+		return note, nil
+	}
+	if len(m.gotNotes) > 0 {
+		note := m.gotNotes[0]
+		m.gotNotes = m.gotNotes[1:]
+		return note, nil
+	}
 	return m.gotNote, nil
 }
 
-// GetResource returns the mock resource.
+// GetResource returns the mock resource for the GUID if there is one,
+// otherwise the mock resource.
 func (m *mockNoteStore) GetResource(ctx context.Context, authenticationToken string, guid edam.GUID, withData bool, withRecognition bool, withAttributes bool, withAlternateData bool) (*edam.Resource, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	if res, ok := m.resourcesByGUID[guid]; ok {
+		return res, nil
+	}
 	return m.resource, nil
 }
 
-// UpdateNote returns the mock updated note.
+// UpdateNote records the note sent and returns the mock updated note.
 func (m *mockNoteStore) UpdateNote(ctx context.Context, authenticationToken string, note *edam.Note) (*edam.Note, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	m.savedNote = note
+	m.savedNotes = append(m.savedNotes, note)
 	if m.updatedNote != nil {
 		return m.updatedNote, nil
 	}
 	return note, nil
 }
 
+// DeleteNote records the GUID moved to the trash.
+func (m *mockNoteStore) DeleteNote(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.deleted = append(m.deleted, guid)
+	return 1, nil
+}
+
+// ExpungeNote records the GUID permanently deleted.
+func (m *mockNoteStore) ExpungeNote(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.expunged = append(m.expunged, guid)
+	return 1, nil
+}
+
+// CreateNotebook records the notebook sent and returns it with a GUID.
+func (m *mockNoteStore) CreateNotebook(ctx context.Context, authenticationToken string, notebook *edam.Notebook) (*edam.Notebook, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.savedNotebooks = append(m.savedNotebooks, notebook)
+	created := *notebook
+	guid := edam.GUID("new-notebook-guid")
+	created.GUID = &guid
+	return &created, nil
+}
+
+// UpdateNotebook records the notebook sent.
+func (m *mockNoteStore) UpdateNotebook(ctx context.Context, authenticationToken string, notebook *edam.Notebook) (int32, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.savedNotebooks = append(m.savedNotebooks, notebook)
+	return 1, nil
+}
+
+// ExpungeNotebook records the GUID of the notebook removed.
+func (m *mockNoteStore) ExpungeNotebook(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.expungedNotebooks = append(m.expungedNotebooks, guid)
+	return 1, nil
+}
+
+// CreateTag records the tag sent and returns it with a GUID.
+func (m *mockNoteStore) CreateTag(ctx context.Context, authenticationToken string, tag *edam.Tag) (*edam.Tag, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.savedTags = append(m.savedTags, tag)
+	created := *tag
+	guid := edam.GUID("new-tag-guid")
+	created.GUID = &guid
+	return &created, nil
+}
+
+// UpdateTag records the tag sent.
+func (m *mockNoteStore) UpdateTag(ctx context.Context, authenticationToken string, tag *edam.Tag) (int32, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.savedTags = append(m.savedTags, tag)
+	return 1, nil
+}
+
+// ExpungeTag records the GUID of the tag removed.
+func (m *mockNoteStore) ExpungeTag(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.expungedTags = append(m.expungedTags, guid)
+	return 1, nil
+}
+
+// ListSearches returns the mock saved searches.
+func (m *mockNoteStore) ListSearches(ctx context.Context, authenticationToken string) ([]*edam.SavedSearch, error) {
+	return m.searches, m.err
+}
+
+// CreateSearch records the saved search sent and returns it with a GUID.
+func (m *mockNoteStore) CreateSearch(ctx context.Context, authenticationToken string, search *edam.SavedSearch) (*edam.SavedSearch, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.savedSearches = append(m.savedSearches, search)
+	created := *search
+	guid := edam.GUID("new-search-guid")
+	created.GUID = &guid
+	return &created, nil
+}
+
+// UpdateSearch records the saved search sent.
+func (m *mockNoteStore) UpdateSearch(ctx context.Context, authenticationToken string, search *edam.SavedSearch) (int32, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.savedSearches = append(m.savedSearches, search)
+	return 1, nil
+}
+
+// ExpungeSearch records the GUID of the saved search removed.
+func (m *mockNoteStore) ExpungeSearch(ctx context.Context, authenticationToken string, guid edam.GUID) (int32, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.expungedSearches = append(m.expungedSearches, guid)
+	return 1, nil
+}
+
+// GetSyncState returns the mock sync state.
+func (m *mockNoteStore) GetSyncState(ctx context.Context, authenticationToken string) (*edam.SyncState, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.syncState, nil
+}
+
+// GetFilteredSyncChunk records the filter and returns the first mock chunk
+// past afterUSN, or an empty chunk once they have all been returned.
+func (m *mockNoteStore) GetFilteredSyncChunk(ctx context.Context, authenticationToken string, afterUSN int32, maxEntries int32, filter *edam.SyncChunkFilter) (*edam.SyncChunk, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.syncFilter = filter
+	m.chunkCalls++
+	for _, chunk := range m.syncChunks {
+		if chunk.GetChunkHighUSN() > afterUSN {
+			return chunk, nil
+		}
+	}
+	return &edam.SyncChunk{UpdateCount: m.syncState.GetUpdateCount()}, nil
+}
+
 // setMockNoteStore overrides getNoteStoreFunc for testing and returns a cleanup function.
 func setMockNoteStore(mock *mockNoteStore) func() {
-	original := getNoteStoreFunc
+	original, originalRemote := getNoteStoreFunc, getRemoteNoteStoreFunc
 	getNoteStoreFunc = func() (noteStoreClient, string, error) {
 		if mock.err != nil && mock.notebooks == nil && mock.tags == nil && mock.notes == nil && mock.createdNote == nil && mock.gotNote == nil && mock.updatedNote == nil && mock.resource == nil {
 			return nil, "", mock.err
 		}
 		return mock, "test-token", nil
 	}
-	return func() { getNoteStoreFunc = original }
+	getRemoteNoteStoreFunc = getNoteStoreFunc
+	return func() { getNoteStoreFunc, getRemoteNoteStoreFunc = original, originalRemote }
 }
 
 func TestLoadConfig(t *testing.T) {
@@ -173,9 +376,13 @@ func TestSaveConfig(t *testing.T) {
 		data, err := os.ReadFile(configPath)
 		require.NoError(t, err)
 
-		var savedConfig Config
-		err = json.Unmarshal(data, &savedConfig)
+		var saved configFile
+		err = json.Unmarshal(data, &saved)
 		require.NoError(t, err)
+		assert.Equal(t, configVersion, saved.Version)
+		assert.Equal(t, "default", saved.Current)
+		savedConfig := saved.Profiles["default"]
+		require.NotNil(t, savedConfig)
 
 		assert.Equal(t, testConfig.ClientID, savedConfig.ClientID)
 		assert.Equal(t, testConfig.ClientSecret, savedConfig.ClientSecret)
@@ -194,11 +401,7 @@ func TestSaveConfig(t *testing.T) {
 		err := saveConfig(testConfig)
 		require.NoError(t, err)
 
-		data, err := os.ReadFile(configPath)
-		require.NoError(t, err)
-
-		var savedConfig Config
-		err = json.Unmarshal(data, &savedConfig)
+		savedConfig, err := loadConfig()
 		require.NoError(t, err)
 
 		assert.Equal(t, testConfig.ClientID, savedConfig.ClientID)
@@ -413,3 +616,61 @@ func TestFormatAPIError(t *testing.T) {
 		assert.Equal(t, "some other error", result.Error())
 	})
 }
+
+func TestResolveCredentials(t *testing.T) {
+	setConfigPath(t)
+
+	_, err := resolveCredentials()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not read config")
+
+	t.Run("token from the environment without a config file", func(t *testing.T) {
+		t.Setenv("EVERNOTE_AUTH_TOKEN", "env-token")
+		t.Setenv("EVERNOTE_ENVIRONMENT", "sandbox")
+		cfg, err := resolveCredentials()
+		require.NoError(t, err)
+		assert.Equal(t, &Config{AuthToken: "env-token", Environment: "sandbox"}, cfg)
+	})
+
+	require.NoError(t, saveConfig(&Config{
+		ClientID:     "id",
+		AuthToken:    "profile-token",
+		NoteStoreURL: "https://www.evernote.com/shard/s1/notestore",
+	}))
+
+	t.Run("profile", func(t *testing.T) {
+		cfg, err := resolveCredentials()
+		require.NoError(t, err)
+		assert.Equal(t, "profile-token", cfg.AuthToken)
+		assert.Equal(t, "https://www.evernote.com/shard/s1/notestore", cfg.NoteStoreURL)
+	})
+
+	t.Run("environment before profile", func(t *testing.T) {
+		t.Setenv("EVERNOTE_AUTH_TOKEN", "env-token")
+		cfg, err := resolveCredentials()
+		require.NoError(t, err)
+		assert.Equal(t, "env-token", cfg.AuthToken)
+		assert.Empty(t, cfg.NoteStoreURL, "the profile's NoteStore URL may be another account's")
+		assert.Equal(t, "id", cfg.ClientID)
+
+		t.Setenv("EVERNOTE_NOTESTORE_URL", "https://www.evernote.com/shard/s2/notestore")
+		cfg, err = resolveCredentials()
+		require.NoError(t, err)
+		assert.Equal(t, "https://www.evernote.com/shard/s2/notestore", cfg.NoteStoreURL)
+	})
+
+	t.Run("flags before environment", func(t *testing.T) {
+		t.Setenv("EVERNOTE_AUTH_TOKEN", "env-token")
+		t.Setenv("EVERNOTE_NOTESTORE_URL", "https://www.evernote.com/shard/s2/notestore")
+		authTokenFlag, noteStoreURLFlag = "flag-token", "https://www.evernote.com/shard/s3/notestore"
+		defer func() { authTokenFlag, noteStoreURLFlag = "", "" }()
+		cfg, err := resolveCredentials()
+		require.NoError(t, err)
+		assert.Equal(t, "flag-token", cfg.AuthToken)
+		assert.Equal(t, "https://www.evernote.com/shard/s3/notestore", cfg.NoteStoreURL)
+	})
+
+	saved, err := loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "profile-token", saved.AuthToken, "resolving never changes the profile")
+}
@@ -0,0 +1,211 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/spf13/cobra"
+)
+
+var savedYes bool
+
+// validSearchName checks a saved search name against Evernote's rules.
+func validSearchName(name string) error {
+	if name == "" || strings.TrimSpace(name) != name {
+		return fmt.Errorf("saved search name cannot be empty or start or end with whitespace")
+	}
+	if len([]rune(name)) > 100 {
+		return fmt.Errorf("saved search name cannot be longer than 100 characters")
+	}
+	return nil
+}
+
+// printSearchResult prints a saved search after a change, as JSON with --json.
+func printSearchResult(w io.Writer, action string, search *edam.SavedSearch) error {
+	if jsonFlag {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(search)
+	}
+	fmt.Fprintf(w, "Saved search %s: %s\n", action, search.GetName())
+	fmt.Fprintf(w, "Query: %s\n", search.GetQuery())
+	fmt.Fprintf(w, "GUID: %s\n", search.GetGUID())
+	return nil
+}
+
+// savedCmd groups the saved search commands.
+var savedCmd = &cobra.Command{
+	Use:   "saved",
+	Short: "Manage and run saved searches",
+	Long: `Manage Evernote saved searches and run them by name. Saved searches can be
+given by name (case-insensitive) or GUID.
+
+Examples:
+  evernote-cli saved list
+  evernote-cli saved create "Open tasks" "todo:false tag:work"
+  evernote-cli saved run "Open tasks" --all --json
+  evernote-cli saved delete "Open tasks"`,
+}
+
+// savedListCmd lists saved searches.
+var savedListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved searches",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		searches, err := ns.ListSearches(context.Background(), token)
+		if err != nil {
+			return fmt.Errorf("failed to list saved searches: %w", formatAPIError(err))
+		}
+
+		if jsonFlag {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(searches)
+		}
+
+		if len(searches) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No saved searches found.")
+			return nil
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Found %d saved search(es):\n\n", len(searches))
+		for i, s := range searches {
+			fmt.Fprintf(cmd.OutOrStdout(), "%d. %s\n", i+1, s.GetName())
+			fmt.Fprintf(cmd.OutOrStdout(), "   Query: %s\n", s.GetQuery())
+			fmt.Fprintf(cmd.OutOrStdout(), "   GUID: %s\n", s.GetGUID())
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+		return nil
+	},
+}
+
+// savedCreateCmd creates a saved search.
+var savedCreateCmd = &cobra.Command{
+	Use:   "create <name> <query>",
+	Short: "Create a saved search",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, query := args[0], strings.Join(args[1:], " ")
+		if err := validSearchName(name); err != nil {
+			return err
+		}
+
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		if existing, err := newNameResolver(ns, token).savedSearch(name); err == nil {
+			return fmt.Errorf("saved search %q already exists, use 'saved update' to change its query", existing.GetName())
+		}
+
+		created, err := ns.CreateSearch(context.Background(), token, &edam.SavedSearch{Name: &name, Query: &query})
+		if err != nil {
+			return fmt.Errorf("failed to create saved search: %w", formatAPIError(err))
+		}
+		return printSearchResult(cmd.OutOrStdout(), "created", created)
+	},
+}
+
+// savedUpdateCmd replaces the query of a saved search.
+var savedUpdateCmd = &cobra.Command{
+	Use:   "update <name> <query>",
+	Short: "Change the query of a saved search",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		search, err := newNameResolver(ns, token).savedSearch(args[0])
+		if err != nil {
+			return err
+		}
+
+		// UpdateSearch replaces the search, so send a copy with only the query changed
+		updated := *search
+		query := strings.Join(args[1:], " ")
+		updated.Query = &query
+		if _, err := ns.UpdateSearch(context.Background(), token, &updated); err != nil {
+			return fmt.Errorf("failed to update saved search: %w", formatAPIError(err))
+		}
+		return printSearchResult(cmd.OutOrStdout(), "updated", &updated)
+	},
+}
+
+// savedRunCmd runs a saved search and prints the results like search.
+var savedRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a saved search",
+	Long: `Run a saved search and print the matching notes in the same format as
+search. --limit, --offset, --all and --json work as they do for search.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateSearchPaging(cmd); err != nil {
+			return err
+		}
+
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		search, err := newNameResolver(ns, token).savedSearch(args[0])
+		if err != nil {
+			return err
+		}
+		return runSearch(cmd, ns, token, search.GetQuery(), searchFilterOptions{})
+	},
+}
+
+// savedDeleteCmd permanently deletes a saved search.
+var savedDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a saved search",
+	Long: `Permanently delete a saved search. You are asked to confirm first unless
+--yes is given. Evernote only allows this with a full-access API key.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		search, err := newNameResolver(ns, token).savedSearch(args[0])
+		if err != nil {
+			return err
+		}
+
+		if !savedYes && !confirm(cmd, fmt.Sprintf("Delete saved search %q?", search.GetName())) {
+			fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+			return nil
+		}
+
+		if _, err := ns.ExpungeSearch(context.Background(), token, search.GetGUID()); err != nil {
+			return fmt.Errorf("failed to delete saved search: %w", formatAPIError(err))
+		}
+		return printSearchResult(cmd.OutOrStdout(), "deleted", search)
+	},
+}
+
+func init() {
+	savedRunCmd.Flags().IntVar(&searchLimit, "limit", 100, "maximum number of notes to return")
+	savedRunCmd.Flags().IntVar(&searchOffset, "offset", 0, "number of matching notes to skip")
+	savedRunCmd.Flags().BoolVar(&searchAll, "all", false, "return every matching note, streaming results as they arrive")
+	savedDeleteCmd.Flags().BoolVarP(&savedYes, "yes", "y", false, "do not ask for confirmation")
+	savedCmd.AddCommand(savedListCmd, savedCreateCmd, savedUpdateCmd, savedRunCmd, savedDeleteCmd)
+	rootCmd.AddCommand(savedCmd)
+}
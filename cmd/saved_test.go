@@ -0,0 +1,128 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSearches returns mock saved searches.
+func testSearches() []*edam.SavedSearch {
+	name, guid, query := "Open tasks", edam.GUID("search-1"), "todo:false"
+	return []*edam.SavedSearch{{Name: &name, GUID: &guid, Query: &query}}
+}
+
+func TestSavedCommands(t *testing.T) {
+	jsonFlag = false
+
+	t.Run("list", func(t *testing.T) {
+		defer setMockNoteStore(&mockNoteStore{searches: testSearches()})()
+
+		var buf bytes.Buffer
+		savedListCmd.SetOut(&buf)
+		require.NoError(t, savedListCmd.RunE(savedListCmd, nil))
+		assert.Contains(t, buf.String(), "Found 1 saved search(es):")
+		assert.Contains(t, buf.String(), "1. Open tasks")
+		assert.Contains(t, buf.String(), "Query: todo:false")
+		assert.Contains(t, buf.String(), "GUID: search-1")
+	})
+
+	t.Run("list empty", func(t *testing.T) {
+		defer setMockNoteStore(&mockNoteStore{searches: []*edam.SavedSearch{}})()
+
+		var buf bytes.Buffer
+		savedListCmd.SetOut(&buf)
+		require.NoError(t, savedListCmd.RunE(savedListCmd, nil))
+		assert.Contains(t, buf.String(), "No saved searches found.")
+	})
+
+	t.Run("create", func(t *testing.T) {
+		mock := &mockNoteStore{searches: testSearches()}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		savedCreateCmd.SetOut(&buf)
+		require.NoError(t, savedCreateCmd.RunE(savedCreateCmd, []string{"Work", "tag:work", "created:day-7"}))
+		require.Len(t, mock.savedSearches, 1)
+		assert.Equal(t, "Work", mock.savedSearches[0].GetName())
+		assert.Equal(t, "tag:work created:day-7", mock.savedSearches[0].GetQuery())
+		assert.Contains(t, buf.String(), "Saved search created: Work")
+	})
+
+	t.Run("create existing name", func(t *testing.T) {
+		mock := &mockNoteStore{searches: testSearches()}
+		defer setMockNoteStore(mock)()
+
+		err := savedCreateCmd.RunE(savedCreateCmd, []string{"open TASKS", "x"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+		assert.Nil(t, mock.savedSearches)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		mock := &mockNoteStore{searches: testSearches()}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		savedUpdateCmd.SetOut(&buf)
+		require.NoError(t, savedUpdateCmd.RunE(savedUpdateCmd, []string{"open tasks", "todo:false tag:work"}))
+		require.Len(t, mock.savedSearches, 1)
+		assert.Equal(t, edam.GUID("search-1"), mock.savedSearches[0].GetGUID())
+		assert.Equal(t, "Open tasks", mock.savedSearches[0].GetName())
+		assert.Equal(t, "todo:false tag:work", mock.savedSearches[0].GetQuery())
+	})
+
+	t.Run("run uses the stored query", func(t *testing.T) {
+		mock := &mockNoteStore{searches: testSearches(), notes: manyNotes(2)}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		savedRunCmd.SetOut(&buf)
+		require.NoError(t, savedRunCmd.RunE(savedRunCmd, []string{"Open tasks"}))
+		assert.Equal(t, "todo:false", mock.filter.GetWords())
+		assert.Contains(t, buf.String(), "Found 2 note(s):")
+		assert.Contains(t, buf.String(), "2. Note 2")
+	})
+
+	t.Run("run unknown search", func(t *testing.T) {
+		defer setMockNoteStore(&mockNoteStore{searches: testSearches()})()
+		err := savedRunCmd.RunE(savedRunCmd, []string{"Nope"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `saved search "Nope" not found`)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		mock := &mockNoteStore{searches: testSearches()}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		savedDeleteCmd.SetOut(&buf)
+		savedDeleteCmd.SetIn(strings.NewReader("y\n"))
+		defer savedDeleteCmd.SetIn(nil)
+		require.NoError(t, savedDeleteCmd.RunE(savedDeleteCmd, []string{"search-1"}))
+		assert.Equal(t, []edam.GUID{"search-1"}, mock.expungedSearches)
+		assert.Contains(t, buf.String(), "Saved search deleted: Open tasks")
+	})
+}
+
+func TestSavedCmdRegistration(t *testing.T) {
+	found := false
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "saved" {
+			found = true
+			for _, name := range []string{"list", "create", "update", "run", "delete"} {
+				sub, _, err := c.Find([]string{name})
+				require.NoError(t, err)
+				assert.Equal(t, name, sub.Name())
+			}
+			break
+		}
+	}
+	assert.True(t, found, "saved command should be registered")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -11,69 +12,275 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// searchCmd searches notes by a query string using the Evernote search grammar.
-var searchCmd = &cobra.Command{
-	Use:   "search [query]",
-	Short: "Search notes",
-	Args:  cobra.MinimumNArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		ns, token, err := getNoteStoreFunc()
-		if err != nil {
-			return err
-		}
+// notesPageSize is the number of notes requested per FindNotesMetadata call.
+const notesPageSize = 250
 
-		query := strings.Join(args, " ")
-		includeTitle := true
-		includeCreated := true
-		includeUpdated := true
-		includeNotebookGuid := true
+var (
+	searchLimit  int
+	searchOffset int
+	searchAll    bool
+	searchLocal  bool
+	searchOpts   searchFilterOptions
+)
 
-		filter := &edam.NoteFilter{
-			Words: &query,
+// pageNotes calls fn for each note matching filter, starting at offset and
+// fetching notesPageSize notes per request, and returns the total number of
+// matches. A negative max returns every match. fn receives the note's index
+// in the full result set, so results can be handled as each page arrives.
+func pageNotes(ns noteStoreClient, token string, filter *edam.NoteFilter, spec *edam.NotesMetadataResultSpec, offset, max int, fn func(i int, note *edam.NoteMetadata) error) (int, error) {
+	seen := 0
+	for {
+		count := notesPageSize
+		if max >= 0 && max-seen < count {
+			// Fetch at least one note so the total is known
+			count = max - seen
+			if count == 0 {
+				count = 1
+			}
+		}
+		results, err := ns.FindNotesMetadata(context.Background(), token, filter, int32(offset+seen), int32(count), spec)
+		if err != nil {
+			return 0, fmt.Errorf("failed to search notes: %w", formatAPIError(err))
+		}
+		total := int(results.GetTotalNotes())
+		page := results.GetNotes()
+		for _, note := range page {
+			if max >= 0 && seen >= max {
+				break
+			}
+			if err := fn(offset+seen, note); err != nil {
+				return 0, err
+			}
+			seen++
 		}
-		resultSpec := &edam.NotesMetadataResultSpec{
-			IncludeTitle:        &includeTitle,
-			IncludeCreated:      &includeCreated,
-			IncludeUpdated:      &includeUpdated,
-			IncludeNotebookGuid: &includeNotebookGuid,
+		if len(page) == 0 || offset+seen >= total || (max >= 0 && seen >= max) {
+			return total, nil
 		}
+	}
+}
 
-		results, err := ns.FindNotesMetadata(context.Background(), token, filter, 0, 100, resultSpec)
+// searchNotes pages through the notes matching each filter in turn as if
+// they were one result set, and returns the combined number of matches.
+func searchNotes(ns noteStoreClient, token string, filters []*edam.NoteFilter, spec *edam.NotesMetadataResultSpec, offset, max int, fn func(i int, note *edam.NoteMetadata) error) (int, error) {
+	total := 0
+	for _, filter := range filters {
+		base, skip := total, offset-total
+		if skip < 0 {
+			skip = 0
+		}
+		seen := 0
+		n, err := pageNotes(ns, token, filter, spec, skip, max, func(i int, note *edam.NoteMetadata) error {
+			seen++
+			return fn(base+i, note)
+		})
 		if err != nil {
-			return fmt.Errorf("failed to search notes: %w", formatAPIError(err))
+			return 0, err
 		}
+		total += n
+		if max >= 0 {
+			max -= seen
+		}
+	}
+	return total, nil
+}
+
+// printNoteMetadata writes one numbered search result, showing notebook and
+// tag names.
+func printNoteMetadata(w io.Writer, r *nameResolver, n int, note *edam.NoteMetadata) {
+	printNoteFields(w, r, n, note)
+	fmt.Fprintln(w)
+}
+
+// printNoteFields writes the lines of one numbered search result.
+func printNoteFields(w io.Writer, r *nameResolver, n int, note *edam.NoteMetadata) {
+	fmt.Fprintf(w, "%d. %s\n", n, note.GetTitle())
+	fmt.Fprintf(w, "   GUID: %s\n", note.GetGUID())
+	if note.GetNotebookGuid() != "" {
+		fmt.Fprintf(w, "   Notebook: %s\n", r.notebookName(note.GetNotebookGuid()))
+	}
+	if len(note.GetTagGuids()) > 0 {
+		fmt.Fprintf(w, "   Tags: %s\n", strings.Join(r.tagNames(note.GetTagGuids()), ", "))
+	}
+	if note.GetCreated() != 0 {
+		created := time.Unix(int64(note.GetCreated())/1000, 0)
+		fmt.Fprintf(w, "   Created: %s\n", created.Format("2006-01-02 15:04:05"))
+	}
+	if note.GetUpdated() != 0 {
+		updated := time.Unix(int64(note.GetUpdated())/1000, 0)
+		fmt.Fprintf(w, "   Updated: %s\n", updated.Format("2006-01-02 15:04:05"))
+	}
+}
 
+// validateSearchPaging checks the --limit, --offset and --all flags.
+func validateSearchPaging(cmd *cobra.Command) error {
+	if searchAll && cmd.Flags().Changed("limit") {
+		return fmt.Errorf("--all and --limit cannot be used together")
+	}
+	if searchLimit < 1 {
+		return fmt.Errorf("--limit must be at least 1")
+	}
+	if searchOffset < 0 {
+		return fmt.Errorf("--offset cannot be negative")
+	}
+	return nil
+}
+
+// runSearch runs a search and prints the results using the --limit, --offset
+// and --all settings.
+func runSearch(cmd *cobra.Command, ns noteStoreClient, token, query string, opts searchFilterOptions) error {
+	resolver := newNameResolver(ns, token)
+	filters, err := buildSearchFilters(resolver, query, opts)
+	if err != nil {
+		return err
+	}
+
+	includeTitle := true
+	includeCreated := true
+	includeUpdated := true
+	includeNotebookGuid := true
+	includeTagGuids := true
+	resultSpec := &edam.NotesMetadataResultSpec{
+		IncludeTitle:        &includeTitle,
+		IncludeCreated:      &includeCreated,
+		IncludeUpdated:      &includeUpdated,
+		IncludeNotebookGuid: &includeNotebookGuid,
+		IncludeTagGuids:     &includeTagGuids,
+	}
+
+	out := cmd.OutOrStdout()
+	if searchAll {
+		// Stream every page instead of collecting the whole result set
+		enc := json.NewEncoder(out)
+		count := 0
+		_, err := searchNotes(ns, token, filters, resultSpec, searchOffset, -1, func(i int, note *edam.NoteMetadata) error {
+			count++
+			if jsonFlag {
+				return enc.Encode(resolver.nameNoteMetadata(note))
+			}
+			printNoteMetadata(out, resolver, i+1, note)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
 		if jsonFlag {
-			enc := json.NewEncoder(cmd.OutOrStdout())
-			enc.SetIndent("", "  ")
-			return enc.Encode(results)
+			return nil
 		}
+		if count == 0 {
+			fmt.Fprintln(out, "No notes found.")
+		} else {
+			fmt.Fprintf(out, "Found %d note(s).\n", count)
+		}
+		return nil
+	}
 
-		notes := results.GetNotes()
-		if len(notes) == 0 {
-			fmt.Fprintln(cmd.OutOrStdout(), "No notes found.")
-			return nil
+	results := &edam.NotesMetadataList{StartIndex: int32(searchOffset), Notes: []*edam.NoteMetadata{}}
+	total, err := searchNotes(ns, token, filters, resultSpec, searchOffset, searchLimit, func(i int, note *edam.NoteMetadata) error {
+		results.Notes = append(results.Notes, note)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	results.TotalNotes = int32(total)
+
+	if jsonFlag {
+		named := &namedNotesMetadataList{NotesMetadataList: results, Notes: []*namedNoteMetadata{}}
+		for _, note := range results.Notes {
+			named.Notes = append(named.Notes, resolver.nameNoteMetadata(note))
 		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(named)
+	}
 
-		fmt.Fprintf(cmd.OutOrStdout(), "Found %d note(s):\n\n", results.GetTotalNotes())
-		for i, note := range notes {
-			fmt.Fprintf(cmd.OutOrStdout(), "%d. %s\n", i+1, note.GetTitle())
-			fmt.Fprintf(cmd.OutOrStdout(), "   GUID: %s\n", note.GetGUID())
-			if note.GetCreated() != 0 {
-				created := time.Unix(int64(note.GetCreated())/1000, 0)
-				fmt.Fprintf(cmd.OutOrStdout(), "   Created: %s\n", created.Format("2006-01-02 15:04:05"))
-			}
-			if note.GetUpdated() != 0 {
-				updated := time.Unix(int64(note.GetUpdated())/1000, 0)
-				fmt.Fprintf(cmd.OutOrStdout(), "   Updated: %s\n", updated.Format("2006-01-02 15:04:05"))
+	notes := results.GetNotes()
+	if len(notes) == 0 {
+		fmt.Fprintln(out, "No notes found.")
+		return nil
+	}
+
+	fmt.Fprintf(out, "Found %d note(s):\n\n", total)
+	for i, note := range notes {
+		printNoteMetadata(out, resolver, searchOffset+i+1, note)
+	}
+	if shown := searchOffset + len(notes); shown < total {
+		fmt.Fprintf(out, "Showing %d-%d of %d. Use --offset %d for more or --all for everything.\n", searchOffset+1, shown, total, shown)
+	}
+
+	return nil
+}
+
+// searchCmd searches notes by a query string using the Evernote search grammar.
+var searchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search notes",
+	Long: `Search notes using the Evernote search grammar, optionally narrowed with
+structured filters.
+
+Dates for --created-after, --created-before, --updated-after and
+--updated-before can be written as 2026-01-01, today, yesterday, or an age
+such as 12h, 7d, 2w, 3m or 1y. --include-inactive also searches the trash;
+trashed notes are listed after active ones, except with --local, which
+sorts them together.
+
+The first 100 matches are returned by default. Use --limit and --offset to
+page through results, or --all to fetch every match. With --all, results are
+printed as they arrive and --json writes one note per line (NDJSON).
+
+--local searches the mirror kept by 'evernote-cli sync' instead of Evernote,
+including text recognised in attachments. Results are ranked by relevance
+and show a snippet with the matching words marked. Local search supports
+words, word* prefixes, "quoted phrases", intitle:, tag:, notebook:,
+created:, updated: (such as created:day-7 or updated:week) and any:, each of
+which can be negated with a leading "-".
+
+Examples:
+  evernote-cli search "tag:work"
+  evernote-cli search --tag work --updated-after 7d
+  evernote-cli search meeting --notebook Work --sort created --reverse
+  evernote-cli search "tag:work" --limit 20 --offset 40
+  evernote-cli search "notebook:Journal" --all --json > journal.ndjson
+  evernote-cli search --local 'any: "road trip" intitle:packing -tag:done'`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := strings.Join(args, " ")
+		if query == "" && searchOpts.empty() {
+			return fmt.Errorf("a query or at least one filter is required")
+		}
+		if err := validateSearchPaging(cmd); err != nil {
+			return err
+		}
+
+		if searchLocal {
+			store, err := openProfileStore()
+			if err != nil {
+				return err
 			}
-			fmt.Fprintln(cmd.OutOrStdout())
+			return runLocalSearch(cmd, store, query, searchOpts)
 		}
 
-		return nil
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+		return runSearch(cmd, ns, token, query, searchOpts)
 	},
 }
 
 func init() {
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 100, "maximum number of notes to return")
+	searchCmd.Flags().IntVar(&searchOffset, "offset", 0, "number of matching notes to skip")
+	searchCmd.Flags().BoolVar(&searchAll, "all", false, "return every matching note, streaming results as they arrive")
+	searchCmd.Flags().StringVar(&searchOpts.Notebook, "notebook", "", "only notes in this notebook (name or GUID)")
+	searchCmd.Flags().StringSliceVar(&searchOpts.Tags, "tag", nil, "only notes with this tag (repeatable)")
+	searchCmd.Flags().StringVar(&searchOpts.CreatedAfter, "created-after", "", "only notes created on or after this date")
+	searchCmd.Flags().StringVar(&searchOpts.CreatedBefore, "created-before", "", "only notes created before this date")
+	searchCmd.Flags().StringVar(&searchOpts.UpdatedAfter, "updated-after", "", "only notes updated on or after this date")
+	searchCmd.Flags().StringVar(&searchOpts.UpdatedBefore, "updated-before", "", "only notes updated before this date")
+	searchCmd.Flags().StringVar(&searchOpts.Sort, "sort", "", "sort order: created, updated, title or relevance")
+	searchCmd.Flags().BoolVar(&searchOpts.Reverse, "reverse", false, "reverse the sort order")
+	searchCmd.Flags().BoolVar(&searchOpts.IncludeInactive, "include-inactive", false, "also search notes in the trash")
+	searchCmd.Flags().BoolVar(&searchLocal, "local", false, "search the local mirror, with ranked results and snippets")
 	rootCmd.AddCommand(searchCmd)
 }
@@ -0,0 +1,193 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+)
+
+// searchFilterOptions holds the structured search flags.
+type searchFilterOptions struct {
+	Notebook        string
+	Tags            []string
+	CreatedAfter    string
+	CreatedBefore   string
+	UpdatedAfter    string
+	UpdatedBefore   string
+	Sort            string
+	Reverse         bool
+	IncludeInactive bool
+}
+
+// empty reports whether no filter other than sorting was given.
+func (o searchFilterOptions) empty() bool {
+	return o.Notebook == "" && len(o.Tags) == 0 && o.CreatedAfter == "" && o.CreatedBefore == "" &&
+		o.UpdatedAfter == "" && o.UpdatedBefore == "" && !o.IncludeInactive
+}
+
+// timeNow returns the current time. It can be overridden for testing.
+var timeNow = time.Now
+
+// parseHumanDate parses an absolute date (2026-01-01, 2026-01-01T15:04 or
+// RFC 3339), a relative age such as 7d, 2w, 3m or 1y, or "today" and
+// "yesterday". Relative ages count back from now; "today" and
+// "yesterday" mean local midnight.
+func parseHumanDate(s string) (time.Time, error) {
+	now := timeNow()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch strings.ToLower(s) {
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	}
+
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04", "2006-01-02T15:04:05"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	if len(s) > 1 {
+		if n, err := strconv.Atoi(s[:len(s)-1]); err == nil && n >= 0 {
+			switch s[len(s)-1] {
+			case 'h':
+				return now.Add(-time.Duration(n) * time.Hour), nil
+			case 'd':
+				return now.AddDate(0, 0, -n), nil
+			case 'w':
+				return now.AddDate(0, 0, -7*n), nil
+			case 'm':
+				return now.AddDate(0, -n, 0), nil
+			case 'y':
+				return now.AddDate(-n, 0, 0), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD, today, yesterday or an age like 7d, 2w, 3m, 1y)", s)
+}
+
+// grammarDate formats a date bound for the search grammar. Before-bounds use
+// the negated form, which matches notes earlier than the given time.
+func grammarDate(field, value string, before bool) (string, error) {
+	t, err := parseHumanDate(value)
+	if err != nil {
+		return "", err
+	}
+	term := field + ":" + t.UTC().Format("20060102T150405Z")
+	if before {
+		term = "-" + term
+	}
+	return term, nil
+}
+
+// hasAnyTerm reports whether a query uses any:, which makes every term of
+// the query optional.
+func hasAnyTerm(query string) bool {
+	for _, field := range strings.Fields(query) {
+		if len(field) >= 4 && strings.EqualFold(field[:4], "any:") {
+			return true
+		}
+	}
+	return false
+}
+
+// buildSearchFilters compiles a query and structured options into the
+// NoteFilters to search. Dates are added to the query as Evernote search
+// grammar, so they cannot be combined with any:, which would make them
+// optional; the notebook, tags, sort order and trash state are set on the
+// filter. Notebooks and tags may be given by name or GUID. With
+// IncludeInactive a second filter for trashed notes is returned.
+func buildSearchFilters(r *nameResolver, query string, opts searchFilterOptions) ([]*edam.NoteFilter, error) {
+	terms := []string{}
+	if query != "" {
+		terms = append(terms, query)
+	}
+	dates := []struct {
+		field, value string
+		before       bool
+	}{
+		{"created", opts.CreatedAfter, false},
+		{"created", opts.CreatedBefore, true},
+		{"updated", opts.UpdatedAfter, false},
+		{"updated", opts.UpdatedBefore, true},
+	}
+	for _, d := range dates {
+		if d.value == "" {
+			continue
+		}
+		if hasAnyTerm(query) {
+			return nil, fmt.Errorf("--created-* and --updated-* cannot be combined with any: (use created: or updated: in the query instead)")
+		}
+		term, err := grammarDate(d.field, d.value, d.before)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+
+	filter := &edam.NoteFilter{}
+	if len(terms) > 0 {
+		words := strings.Join(terms, " ")
+		filter.Words = &words
+	}
+
+	var order edam.NoteSortOrder
+	ascending := false
+	switch opts.Sort {
+	case "", "relevance":
+		if query != "" || opts.Sort == "relevance" {
+			order = edam.NoteSortOrder_RELEVANCE
+		} else {
+			order = edam.NoteSortOrder_UPDATED
+		}
+	case "created":
+		order = edam.NoteSortOrder_CREATED
+	case "updated":
+		order = edam.NoteSortOrder_UPDATED
+	case "title":
+		order = edam.NoteSortOrder_TITLE
+		ascending = true
+	default:
+		return nil, fmt.Errorf("invalid --sort %q (use created, updated, title or relevance)", opts.Sort)
+	}
+	if opts.Reverse {
+		ascending = !ascending
+	}
+	orderValue := int32(order)
+	filter.Order = &orderValue
+	filter.Ascending = &ascending
+
+	if opts.Notebook != "" {
+		nb, err := r.notebook(opts.Notebook)
+		if err != nil {
+			return nil, err
+		}
+		guid := nb.GetGUID()
+		filter.NotebookGuid = &guid
+	}
+	for _, name := range opts.Tags {
+		tag, err := r.tag(name)
+		if err != nil {
+			return nil, err
+		}
+		filter.TagGuids = append(filter.TagGuids, tag.GetGUID())
+	}
+
+	filters := []*edam.NoteFilter{filter}
+	if opts.IncludeInactive {
+		trashed := *filter
+		inactive := true
+		trashed.Inactive = &inactive
+		filters = append(filters, &trashed)
+	}
+	return filters, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setTimeNow fixes the current time for testing and returns a cleanup function.
+func setTimeNow(now time.Time) func() {
+	original := timeNow
+	timeNow = func() time.Time { return now }
+	return func() { timeNow = original }
+}
+
+func TestParseHumanDate(t *testing.T) {
+	now := time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC)
+	defer setTimeNow(now)()
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-01-01", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2026-01-01T08:15", time.Date(2026, 1, 1, 8, 15, 0, 0, time.UTC)},
+		{"2026-01-01T08:15:00+02:00", time.Date(2026, 1, 1, 6, 15, 0, 0, time.UTC)},
+		{"today", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"Yesterday", time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{"12h", time.Date(2026, 3, 14, 22, 30, 0, 0, time.UTC)},
+		{"7d", time.Date(2026, 3, 8, 10, 30, 0, 0, time.UTC)},
+		{"2w", time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{"1m", time.Date(2026, 2, 15, 10, 30, 0, 0, time.UTC)},
+		{"1y", time.Date(2025, 3, 15, 10, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseHumanDate(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.True(t, tt.want.Equal(got), "%s: got %s, want %s", tt.in, got, tt.want)
+	}
+
+	for _, bad := range []string{"", "soon", "7x", "-3d", "2026-13-01"} {
+		_, err := parseHumanDate(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestBuildSearchFilters(t *testing.T) {
+	defer setTimeNow(time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC))()
+
+	t.Run("query only sorts by relevance", func(t *testing.T) {
+		filters, err := buildSearchFilters(newNameResolver(&mockNoteStore{}, "token"), "meeting", searchFilterOptions{})
+		require.NoError(t, err)
+		require.Len(t, filters, 1)
+		assert.Equal(t, "meeting", filters[0].GetWords())
+		assert.Equal(t, int32(edam.NoteSortOrder_RELEVANCE), filters[0].GetOrder())
+		assert.False(t, filters[0].GetAscending())
+		assert.Nil(t, filters[0].Inactive)
+	})
+
+	t.Run("tags are set on the filter and dates compile to grammar", func(t *testing.T) {
+		name1, guid1 := "Work", edam.GUID("tag-1")
+		name2, guid2 := "To Do", edam.GUID("tag-2")
+		mock := &mockNoteStore{tags: []*edam.Tag{{GUID: &guid1, Name: &name1}, {GUID: &guid2, Name: &name2}}}
+
+		filters, err := buildSearchFilters(newNameResolver(mock, "token"), "", searchFilterOptions{
+			Tags:          []string{"work", "tag-2"},
+			CreatedAfter:  "2026-01-01",
+			UpdatedBefore: "7d",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, `created:20260101T000000Z -updated:20260308T103000Z`, filters[0].GetWords())
+		assert.Equal(t, []edam.GUID{"tag-1", "tag-2"}, filters[0].GetTagGuids())
+		assert.Equal(t, int32(edam.NoteSortOrder_UPDATED), filters[0].GetOrder())
+
+		filters, err = buildSearchFilters(newNameResolver(mock, "token"), "any: foo bar", searchFilterOptions{Tags: []string{"work"}})
+		require.NoError(t, err)
+		assert.Equal(t, "any: foo bar", filters[0].GetWords(), "tags are not optional under any:")
+		assert.Equal(t, []edam.GUID{"tag-1"}, filters[0].GetTagGuids())
+
+		_, err = buildSearchFilters(newNameResolver(mock, "token"), "ANY: foo bar", searchFilterOptions{CreatedAfter: "7d"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot be combined with any:")
+
+		_, err = buildSearchFilters(newNameResolver(mock, "token"), "", searchFilterOptions{Tags: []string{"missing"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `tag "missing" not found`)
+	})
+
+	t.Run("notebook by name or guid", func(t *testing.T) {
+		name1, guid1 := "Work", edam.GUID("nb-1")
+		name2, guid2 := "Personal", edam.GUID("nb-2")
+		mock := &mockNoteStore{notebooks: []*edam.Notebook{
+			{GUID: &guid1, Name: &name1},
+			{GUID: &guid2, Name: &name2},
+		}}
+
+		filters, err := buildSearchFilters(newNameResolver(mock, "token"), "", searchFilterOptions{Notebook: "work"})
+		require.NoError(t, err)
+		assert.Equal(t, edam.GUID("nb-1"), filters[0].GetNotebookGuid())
+
+		filters, err = buildSearchFilters(newNameResolver(mock, "token"), "", searchFilterOptions{Notebook: "nb-2"})
+		require.NoError(t, err)
+		assert.Equal(t, edam.GUID("nb-2"), filters[0].GetNotebookGuid())
+
+		_, err = buildSearchFilters(newNameResolver(mock, "token"), "", searchFilterOptions{Notebook: "Missing"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `notebook "Missing" not found`)
+	})
+
+	t.Run("sort and reverse", func(t *testing.T) {
+		filters, err := buildSearchFilters(newNameResolver(&mockNoteStore{}, "token"), "x", searchFilterOptions{Sort: "title"})
+		require.NoError(t, err)
+		assert.Equal(t, int32(edam.NoteSortOrder_TITLE), filters[0].GetOrder())
+		assert.True(t, filters[0].GetAscending())
+
+		filters, err = buildSearchFilters(newNameResolver(&mockNoteStore{}, "token"), "x", searchFilterOptions{Sort: "created", Reverse: true})
+		require.NoError(t, err)
+		assert.Equal(t, int32(edam.NoteSortOrder_CREATED), filters[0].GetOrder())
+		assert.True(t, filters[0].GetAscending())
+
+		_, err = buildSearchFilters(newNameResolver(&mockNoteStore{}, "token"), "x", searchFilterOptions{Sort: "size"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid --sort")
+	})
+
+	t.Run("include inactive adds a trash filter", func(t *testing.T) {
+		filters, err := buildSearchFilters(newNameResolver(&mockNoteStore{}, "token"), "x", searchFilterOptions{IncludeInactive: true})
+		require.NoError(t, err)
+		require.Len(t, filters, 2)
+		assert.Nil(t, filters[0].Inactive)
+		assert.True(t, filters[1].GetInactive())
+		assert.Equal(t, "x", filters[1].GetWords())
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		_, err := buildSearchFilters(newNameResolver(&mockNoteStore{}, "token"), "", searchFilterOptions{CreatedBefore: "later"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid date "later"`)
+	})
+}
@@ -0,0 +1,106 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/spf13/cobra"
+)
+
+// localSearchList is a page of local search results.
+type localSearchList struct {
+	StartIndex int                  `json:"startIndex"`
+	TotalNotes int                  `json:"totalNotes"`
+	Notes      []*localSearchResult `json:"notes"`
+}
+
+// runLocalSearch searches the local mirror and prints ranked results with
+// snippets, using the --limit, --offset and --all settings.
+func runLocalSearch(cmd *cobra.Command, store *localStore, query string, opts searchFilterOptions) error {
+	// Resolve notebook and tag names from the mirror too
+	resolver := newNameResolver(newCachingNoteStore(nil, store, true), "")
+	filters, err := buildSearchFilters(resolver, query, opts)
+	if err != nil {
+		return err
+	}
+	ix, err := loadSearchIndex(store)
+	if err != nil {
+		return err
+	}
+	if len(ix.Docs) == 0 {
+		return fmt.Errorf("the local mirror is empty, run 'evernote-cli sync' first")
+	}
+
+	var results []*localSearchResult
+	var q *searchQuery
+	for _, filter := range filters {
+		found, parsed, err := searchLocalStore(store, ix, filter)
+		if err != nil {
+			return err
+		}
+		results = append(results, found...)
+		q = parsed
+	}
+	if len(filters) > 1 {
+		// Rank trashed notes together with active ones
+		sortLocalResults(results, edam.NoteSortOrder(filters[0].GetOrder()), filters[0].GetAscending())
+	}
+
+	total := len(results)
+	start, end := searchOffset, total
+	if start > total {
+		start = total
+	}
+	if !searchAll && start+searchLimit < end {
+		end = start + searchLimit
+	}
+	page := results[start:end]
+
+	words, prefixes := q.highlightWords()
+	for _, r := range page {
+		var note edam.Note
+		if err := store.get(storeNotes, r.GetGUID(), &note); err == nil {
+			r.Snippet = snippet(noteText(&note), words, prefixes)
+		}
+		named := resolver.nameNoteMetadata(r.NoteMetadata)
+		r.NotebookName, r.TagNames = named.NotebookName, named.TagNames
+	}
+
+	out := cmd.OutOrStdout()
+	if jsonFlag {
+		enc := json.NewEncoder(out)
+		if searchAll {
+			for _, r := range page {
+				if err := enc.Encode(r); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		enc.SetIndent("", "  ")
+		return enc.Encode(&localSearchList{StartIndex: start, TotalNotes: total, Notes: append([]*localSearchResult{}, page...)})
+	}
+
+	if len(page) == 0 {
+		fmt.Fprintln(out, "No notes found.")
+		return nil
+	}
+	fmt.Fprintf(out, "Found %d note(s):\n\n", total)
+	for i, r := range page {
+		printNoteFields(out, resolver, start+i+1, r.NoteMetadata)
+		if r.Score > 0 {
+			fmt.Fprintf(out, "   Score: %.2f\n", r.Score)
+		}
+		if r.Snippet != "" {
+			fmt.Fprintf(out, "   %s\n", r.Snippet)
+		}
+		fmt.Fprintln(out)
+	}
+	if end < total {
+		fmt.Fprintf(out, "Showing %d-%d of %d. Use --offset %d for more or --all for everything.\n", start+1, end, total, end)
+	}
+	return nil
+}
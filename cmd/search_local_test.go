@@ -0,0 +1,128 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setLocalSearch enables --local with default paging for a test.
+func setLocalSearch(t *testing.T) *localStore {
+	t.Helper()
+	store, err := openLocalStore(setDataDir(t))
+	require.NoError(t, err)
+	searchLocal, searchLimit, searchOffset, searchAll = true, 100, 0, false
+	t.Cleanup(func() {
+		searchLocal, searchLimit, searchOffset, searchAll = false, 100, 0, false
+		searchOpts = searchFilterOptions{}
+		jsonFlag = false
+	})
+	return store
+}
+
+func TestLocalSearchCommand(t *testing.T) {
+	t.Run("ranked results with snippets", func(t *testing.T) {
+		store := setLocalSearch(t)
+		tag, nb := edam.GUID("tag-travel"), edam.GUID("nb-1")
+		require.NoError(t, store.put(storeTags, tag, &edam.Tag{GUID: &tag, Name: strRef("Travel")}))
+		require.NoError(t, store.put(storeNotebooks, nb, &edam.Notebook{GUID: &nb, Name: strRef("Personal")}))
+		for _, note := range []*edam.Note{
+			queryTestNote("n-1", "Packing list", "Passport, charger and snacks.", nb, 2000, tag),
+			queryTestNote("n-2", "Passport renewal", "Renew the passport before the passport expires in March.", nb, 1000),
+			queryTestNote("n-3", "Groceries", "Milk and bread.", nb, 3000),
+		} {
+			require.NoError(t, store.put(storeNotes, note.GetGUID(), note))
+		}
+
+		var buf bytes.Buffer
+		searchCmd.SetOut(&buf)
+		require.NoError(t, searchCmd.RunE(searchCmd, []string{"passport"}))
+		out := buf.String()
+		assert.Contains(t, out, "Found 2 note(s):")
+		assert.Contains(t, out, "1. Passport renewal\n   GUID: n-2\n   Notebook: Personal\n")
+		assert.Contains(t, out, "   Renew the **passport** before the **passport** expires in March.\n")
+		assert.Contains(t, out, "2. Packing list")
+		assert.Contains(t, out, "   Tags: Travel\n")
+		assert.Contains(t, out, "   **Passport**, charger and snacks.\n")
+
+		buf.Reset()
+		searchOpts.Tags = []string{"travel"}
+		jsonFlag = true
+		require.NoError(t, searchCmd.RunE(searchCmd, []string{"passport"}))
+		var list localSearchList
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &list))
+		assert.Equal(t, 1, list.TotalNotes)
+		require.Len(t, list.Notes, 1)
+		assert.Equal(t, edam.GUID("n-1"), list.Notes[0].GetGUID())
+		assert.Greater(t, list.Notes[0].Score, 0.0)
+		assert.Equal(t, "**Passport**, charger and snacks.", list.Notes[0].Snippet)
+	})
+
+	t.Run("trashed notes are ranked with active ones", func(t *testing.T) {
+		store := setLocalSearch(t)
+		trashed := queryTestNote("n-trashed", "Passport", "Passport passport.", "nb-1", 1000)
+		inactive := false
+		trashed.Active = &inactive
+		for _, note := range []*edam.Note{
+			queryTestNote("n-1", "Packing", "Passport and snacks.", "nb-1", 3000),
+			queryTestNote("n-2", "Errands", "Renew passport.", "nb-1", 2000),
+			trashed,
+		} {
+			require.NoError(t, store.put(storeNotes, note.GetGUID(), note))
+		}
+
+		searchOpts.IncludeInactive = true
+		searchLimit = 2
+		jsonFlag = true
+		var buf bytes.Buffer
+		searchCmd.SetOut(&buf)
+		require.NoError(t, searchCmd.RunE(searchCmd, []string{"passport"}))
+		var list localSearchList
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &list))
+		assert.Equal(t, 3, list.TotalNotes)
+		require.Len(t, list.Notes, 2)
+		assert.Equal(t, edam.GUID("n-trashed"), list.Notes[0].GetGUID(), "the best match comes first even in the trash")
+
+		buf.Reset()
+		searchOpts.Sort = "updated"
+		require.NoError(t, searchCmd.RunE(searchCmd, []string{"passport"}))
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &list))
+		assert.Equal(t, edam.GUID("n-1"), list.Notes[0].GetGUID())
+		assert.Equal(t, edam.GUID("n-2"), list.Notes[1].GetGUID())
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		store := setLocalSearch(t)
+		for i, title := range []string{"one", "two", "three"} {
+			note := queryTestNote("n-"+title, title, "note", "nb-1", int64(i))
+			require.NoError(t, store.put(storeNotes, note.GetGUID(), note))
+		}
+		searchLimit = 2
+		var buf bytes.Buffer
+		searchCmd.SetOut(&buf)
+		require.NoError(t, searchCmd.RunE(searchCmd, []string{"note"}))
+		assert.Contains(t, buf.String(), "Showing 1-2 of 3. Use --offset 2 for more or --all for everything.")
+	})
+
+	t.Run("empty mirror", func(t *testing.T) {
+		setLocalSearch(t)
+		err := searchCmd.RunE(searchCmd, []string{"anything"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "run 'evernote-cli sync' first")
+	})
+
+	t.Run("unsupported grammar", func(t *testing.T) {
+		store := setLocalSearch(t)
+		note := queryTestNote("n-1", "x", "y", "nb-1", 0)
+		require.NoError(t, store.put(storeNotes, note.GetGUID(), note))
+		err := searchCmd.RunE(searchCmd, []string{"source:web"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not supported locally")
+	})
+}
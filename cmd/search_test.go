@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/dreampuf/evernote-sdk-golang/edam"
@@ -137,6 +139,180 @@ func TestSearchCommand(t *testing.T) {
 	})
 }
 
+// manyNotes returns n mock notes titled "Note 1" to "Note n".
+func manyNotes(n int) *edam.NotesMetadataList {
+	list := &edam.NotesMetadataList{TotalNotes: int32(n)}
+	for i := 1; i <= n; i++ {
+		title := fmt.Sprintf("Note %d", i)
+		list.Notes = append(list.Notes, &edam.NoteMetadata{GUID: edam.GUID(fmt.Sprintf("note-%d", i)), Title: &title})
+	}
+	return list
+}
+
+func TestSearchShowsNames(t *testing.T) {
+	nbName, nbGUID := "Work Journal", edam.GUID("nb-1")
+	tagName, tagGUID := "urgent", edam.GUID("tag-1")
+	title, notebookGUID := "Standup", "nb-1"
+	mock := &mockNoteStore{
+		notebooks: []*edam.Notebook{{Name: &nbName, GUID: &nbGUID}},
+		tags:      []*edam.Tag{{Name: &tagName, GUID: &tagGUID}},
+		notes: &edam.NotesMetadataList{
+			TotalNotes: 1,
+			Notes: []*edam.NoteMetadata{
+				{GUID: "note-1", Title: &title, NotebookGuid: &notebookGUID, TagGuids: []edam.GUID{"tag-1", "tag-gone"}},
+			},
+		},
+	}
+	defer setMockNoteStore(mock)()
+
+	var buf bytes.Buffer
+	searchCmd.SetOut(&buf)
+	jsonFlag = false
+	require.NoError(t, searchCmd.RunE(searchCmd, []string{"standup"}))
+	assert.Contains(t, buf.String(), "Notebook: Work Journal")
+	assert.Contains(t, buf.String(), "Tags: urgent, tag-gone")
+
+	buf.Reset()
+	jsonFlag = true
+	defer func() { jsonFlag = false }()
+	require.NoError(t, searchCmd.RunE(searchCmd, []string{"standup"}))
+	var got struct {
+		TotalNotes int `json:"totalNotes"`
+		Notes      []struct {
+			NotebookGUID string   `json:"notebookGuid"`
+			NotebookName string   `json:"notebookName"`
+			TagNames     []string `json:"tagNames"`
+		} `json:"notes"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, 1, got.TotalNotes)
+	require.Len(t, got.Notes, 1)
+	assert.Equal(t, "nb-1", got.Notes[0].NotebookGUID)
+	assert.Equal(t, "Work Journal", got.Notes[0].NotebookName)
+	assert.Equal(t, []string{"urgent", "tag-gone"}, got.Notes[0].TagNames)
+}
+
+func TestSearchPagination(t *testing.T) {
+	defer func() { searchLimit, searchOffset, searchAll, jsonFlag = 100, 0, false, false }()
+
+	t.Run("limit and offset", func(t *testing.T) {
+		mock := &mockNoteStore{notes: manyNotes(10)}
+		defer setMockNoteStore(mock)()
+
+		searchLimit, searchOffset, searchAll, jsonFlag = 3, 4, false, false
+		var buf bytes.Buffer
+		searchCmd.SetOut(&buf)
+		require.NoError(t, searchCmd.RunE(searchCmd, []string{"q"}))
+
+		output := buf.String()
+		assert.Contains(t, output, "Found 10 note(s):")
+		assert.Contains(t, output, "5. Note 5")
+		assert.Contains(t, output, "7. Note 7")
+		assert.NotContains(t, output, "Note 8")
+		assert.Contains(t, output, "Showing 5-7 of 10. Use --offset 7")
+	})
+
+	t.Run("limit above page size spans requests", func(t *testing.T) {
+		mock := &mockNoteStore{notes: manyNotes(600)}
+		defer setMockNoteStore(mock)()
+
+		searchLimit, searchOffset, searchAll, jsonFlag = 300, 0, false, true
+		var buf bytes.Buffer
+		searchCmd.SetOut(&buf)
+		require.NoError(t, searchCmd.RunE(searchCmd, []string{"q"}))
+
+		var results edam.NotesMetadataList
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &results))
+		assert.Len(t, results.Notes, 300)
+		assert.Equal(t, int32(600), results.TotalNotes)
+		assert.Equal(t, 2, mock.pages)
+	})
+
+	t.Run("all streams ndjson", func(t *testing.T) {
+		mock := &mockNoteStore{notes: manyNotes(600)}
+		defer setMockNoteStore(mock)()
+
+		searchLimit, searchOffset, searchAll, jsonFlag = 100, 0, true, true
+		var buf bytes.Buffer
+		searchCmd.SetOut(&buf)
+		require.NoError(t, searchCmd.RunE(searchCmd, []string{"q"}))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 600)
+		var last edam.NoteMetadata
+		require.NoError(t, json.Unmarshal([]byte(lines[599]), &last))
+		assert.Equal(t, "Note 600", last.GetTitle())
+		assert.Equal(t, 3, mock.pages)
+	})
+
+	t.Run("all in text mode", func(t *testing.T) {
+		mock := &mockNoteStore{notes: manyNotes(260)}
+		defer setMockNoteStore(mock)()
+
+		searchLimit, searchOffset, searchAll, jsonFlag = 100, 250, true, false
+		var buf bytes.Buffer
+		searchCmd.SetOut(&buf)
+		require.NoError(t, searchCmd.RunE(searchCmd, []string{"q"}))
+		assert.Contains(t, buf.String(), "251. Note 251")
+		assert.Contains(t, buf.String(), "260. Note 260")
+		assert.NotContains(t, buf.String(), "Showing")
+	})
+
+	t.Run("include inactive pages through both result sets", func(t *testing.T) {
+		mock := &mockNoteStore{notes: manyNotes(3)}
+		defer setMockNoteStore(mock)()
+
+		searchLimit, searchOffset, searchAll, jsonFlag = 2, 2, false, false
+		searchOpts = searchFilterOptions{IncludeInactive: true}
+		defer func() { searchOpts = searchFilterOptions{} }()
+
+		var buf bytes.Buffer
+		searchCmd.SetOut(&buf)
+		require.NoError(t, searchCmd.RunE(searchCmd, []string{"q"}))
+
+		output := buf.String()
+		assert.Contains(t, output, "Found 6 note(s):")
+		assert.Contains(t, output, "3. Note 3")
+		assert.Contains(t, output, "4. Note 1")
+		assert.Contains(t, output, "Showing 3-4 of 6.")
+		assert.True(t, mock.filter.GetInactive())
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		searchLimit, searchOffset, searchAll = 0, 0, false
+		err := searchCmd.RunE(searchCmd, []string{"q"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--limit must be at least 1")
+	})
+}
+
+func TestSearchFilters(t *testing.T) {
+	t.Run("filters without a query", func(t *testing.T) {
+		tagName, tagGUID := "work", edam.GUID("tag-1")
+		mock := &mockNoteStore{notes: manyNotes(1), tags: []*edam.Tag{{Name: &tagName, GUID: &tagGUID}}}
+		defer setMockNoteStore(mock)()
+
+		searchOpts = searchFilterOptions{Tags: []string{"work"}, Sort: "created"}
+		defer func() { searchOpts = searchFilterOptions{} }()
+
+		var buf bytes.Buffer
+		searchCmd.SetOut(&buf)
+		jsonFlag = false
+		require.NoError(t, searchCmd.RunE(searchCmd, nil))
+
+		assert.Empty(t, mock.filter.GetWords())
+		assert.Equal(t, []edam.GUID{"tag-1"}, mock.filter.GetTagGuids())
+		assert.Equal(t, int32(edam.NoteSortOrder_CREATED), mock.filter.GetOrder())
+		assert.Contains(t, buf.String(), "1. Note 1")
+	})
+
+	t.Run("query or filter required", func(t *testing.T) {
+		err := searchCmd.RunE(searchCmd, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "a query or at least one filter is required")
+	})
+}
+
 func TestSearchCmdRegistration(t *testing.T) {
 	found := false
 	for _, cmd := range rootCmd.Commands() {
@@ -0,0 +1,339 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"golang.org/x/term"
+)
+
+// Secret stores a profile's auth token and client secret can be kept in.
+const (
+	secretStorePlaintext = "plaintext"
+	secretStoreKeyring   = "keyring"
+	secretStoreFile      = "file"
+)
+
+// Keys of the secrets kept for each profile.
+const (
+	secretAuthToken    = "auth_token"
+	secretClientSecret = "client_secret"
+)
+
+// secretStoreFlag is the --secret-store flag of auth and init.
+var secretStoreFlag string
+
+// secretStore keeps the secrets of profiles outside the config file.
+type secretStore interface {
+	// get returns a secret, or "" when none is stored.
+	get(profile, key string) (string, error)
+	// set stores secrets by key, replacing any stored before; an empty value
+	// removes the secret.
+	set(profile string, secrets map[string]string) error
+}
+
+// checkSecretStore rejects unknown secret store names.
+func checkSecretStore(name string) error {
+	switch name {
+	case "", secretStorePlaintext, secretStoreKeyring, secretStoreFile:
+		return nil
+	}
+	return fmt.Errorf("invalid secret store %q (use plaintext, keyring or file)", name)
+}
+
+// openSecretStore returns the store for a profile's secrets, or nil when
+// they are kept in the config file itself.
+func openSecretStore(name string) (secretStore, error) {
+	switch name {
+	case "", secretStorePlaintext:
+		return nil, nil
+	case secretStoreKeyring:
+		return keyringStore{}, nil
+	case secretStoreFile:
+		return &fileSecretStore{path: filepath.Join(filepath.Dir(configPath), "secrets.age")}, nil
+	}
+	return nil, checkSecretStore(name)
+}
+
+// loadSecrets fills in the secrets of a profile kept outside the config
+// file.
+func loadSecrets(profile string, c *Config) error {
+	store, err := openSecretStore(c.SecretStore)
+	if err != nil || store == nil {
+		return err
+	}
+	if c.AuthToken, err = store.get(profile, secretAuthToken); err != nil {
+		return err
+	}
+	if c.ClientSecret, err = store.get(profile, secretClientSecret); err != nil {
+		return err
+	}
+	return nil
+}
+
+// storeSecrets moves the secrets of a profile into its secret store and
+// returns the config to write to the file, without them. Secrets left in
+// the store the profile used before are removed.
+func storeSecrets(profile string, c *Config, previous string) (*Config, error) {
+	stored := *c
+	store, err := openSecretStore(c.SecretStore)
+	if err != nil {
+		return nil, err
+	}
+	if store != nil {
+		secrets := map[string]string{secretAuthToken: c.AuthToken, secretClientSecret: c.ClientSecret}
+		if err := store.set(profile, secrets); err != nil {
+			return nil, fmt.Errorf("failed to save secrets in the %s store: %w", c.SecretStore, err)
+		}
+		stored.AuthToken, stored.ClientSecret = "", ""
+	}
+	if previous != c.SecretStore {
+		if err := removeSecrets(profile, previous); err != nil {
+			fmt.Fprintf(secretWarnings, "Warning: %v\n", err)
+		}
+	}
+	return &stored, nil
+}
+
+// secretWarnings receives a warning when secrets are left behind in a store
+// they could not be removed from. Can be overridden in tests.
+var secretWarnings io.Writer = os.Stderr
+
+// removeSecrets deletes a profile's secrets from a store.
+func removeSecrets(profile, storeName string) error {
+	store, err := openSecretStore(storeName)
+	if err == nil && store != nil {
+		err = store.set(profile, map[string]string{secretAuthToken: "", secretClientSecret: ""})
+	}
+	if err != nil {
+		return fmt.Errorf("could not remove the secrets of profile %s from the %s store, remove them by hand: %w", profile, storeName, err)
+	}
+	return nil
+}
+
+// secretToolCommand is the Secret Service client used by the keyring store.
+// Can be overridden in tests.
+var secretToolCommand = "secret-tool"
+
+// keyringStore keeps secrets in the desktop keyring through the Secret
+// Service D-Bus API (GNOME Keyring, KWallet), using secret-tool.
+type keyringStore struct{}
+
+// run runs secret-tool with the attributes of a secret.
+func (keyringStore) run(stdin io.Reader, action, profile, key string, extra ...string) (string, error) {
+	args := append([]string{action}, extra...)
+	args = append(args, "service", "evernote-cli", "profile", profile, "key", key)
+	cmd := exec.Command(secretToolCommand, args...)
+	cmd.Stdin = stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("the keyring store needs %s (libsecret-tools) to be installed", secretToolCommand)
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && action == "lookup" && stderr.Len() == 0 {
+			// lookup fails without a message when nothing is stored
+			return "", nil
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s %s: %s", secretToolCommand, action, msg)
+		}
+		return "", fmt.Errorf("%s %s: %w", secretToolCommand, action, err)
+	}
+	return stdout.String(), nil
+}
+
+func (s keyringStore) get(profile, key string) (string, error) {
+	out, err := s.run(nil, "lookup", profile, key)
+	return strings.TrimRight(out, "\n"), err
+}
+
+func (s keyringStore) set(profile string, secrets map[string]string) error {
+	for key, value := range secrets {
+		var err error
+		if value == "" {
+			_, err = s.run(nil, "clear", profile, key)
+		} else {
+			_, err = s.run(strings.NewReader(value), "store", profile, key, "--label", fmt.Sprintf("evernote-cli %s %s", profile, key))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// secretPassphrase is the passphrase of the encrypted secrets file, asked
+// for once per run.
+var secretPassphrase string
+
+// readPassphrase returns the passphrase of the encrypted secrets file from
+// EVERNOTE_SECRET_PASSPHRASE, or asks for it on the terminal, twice when
+// the file is being created.
+var readPassphrase = func(create bool) (string, error) {
+	if secretPassphrase != "" {
+		return secretPassphrase, nil
+	}
+	if pass := os.Getenv("EVERNOTE_SECRET_PASSPHRASE"); pass != "" {
+		secretPassphrase = pass
+		return pass, nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("the secrets file needs a passphrase: set EVERNOTE_SECRET_PASSPHRASE or run from a terminal")
+	}
+	ask := func(prompt string) (string, error) {
+		fmt.Fprint(os.Stderr, prompt)
+		pass, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(pass), err
+	}
+	pass, err := ask("Passphrase for the evernote-cli secrets file: ")
+	if err != nil {
+		return "", err
+	}
+	if pass == "" {
+		return "", fmt.Errorf("the passphrase cannot be empty")
+	}
+	if create {
+		again, err := ask("Repeat the passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != pass {
+			return "", fmt.Errorf("the passphrases do not match")
+		}
+	}
+	secretPassphrase = pass
+	return pass, nil
+}
+
+// scryptWorkFactor is the log2 of the scrypt cost used to derive the key of
+// the secrets file; 18 is age's default, about a second. Can be lowered in
+// tests.
+var scryptWorkFactor = 18
+
+// fileSecretStore keeps secrets in a file encrypted with age, using a key
+// derived from a passphrase with scrypt. The file holds the secrets of
+// every profile as JSON, by profile and key.
+type fileSecretStore struct {
+	path    string
+	secrets map[string]map[string]string
+}
+
+// load decrypts the file the first time it is needed.
+func (s *fileSecretStore) load() error {
+	if s.secrets != nil {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		s.secrets = map[string]map[string]string{}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	pass, err := readPassphrase(false)
+	if err != nil {
+		return err
+	}
+	identity, err := age.NewScryptIdentity(pass)
+	if err != nil {
+		return err
+	}
+	r, err := age.Decrypt(bytes.NewReader(data), identity)
+	if err != nil {
+		return fmt.Errorf("could not decrypt %s (wrong passphrase?): %w", s.path, err)
+	}
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("could not decrypt %s: %w", s.path, err)
+	}
+	return json.Unmarshal(plain, &s.secrets)
+}
+
+// save encrypts the secrets and replaces the file.
+func (s *fileSecretStore) save() error {
+	_, statErr := os.Stat(s.path)
+	pass, err := readPassphrase(os.IsNotExist(statErr))
+	if err != nil {
+		return err
+	}
+	recipient, err := age.NewScryptRecipient(pass)
+	if err != nil {
+		return err
+	}
+	recipient.SetWorkFactor(scryptWorkFactor)
+	plain, err := json.Marshal(s.secrets)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipient)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(plain); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, buf.Bytes())
+}
+
+func (s *fileSecretStore) get(profile, key string) (string, error) {
+	if err := s.load(); err != nil {
+		return "", err
+	}
+	return s.secrets[profile][key], nil
+}
+
+func (s *fileSecretStore) set(profile string, secrets map[string]string) error {
+	if _, err := os.Stat(s.path); os.IsNotExist(err) && !hasSecret(secrets) {
+		return nil
+	}
+	if err := s.load(); err != nil {
+		return err
+	}
+	for key, value := range secrets {
+		if value == "" {
+			delete(s.secrets[profile], key)
+			continue
+		}
+		if s.secrets[profile] == nil {
+			s.secrets[profile] = map[string]string{}
+		}
+		s.secrets[profile][key] = value
+	}
+	if len(s.secrets[profile]) == 0 {
+		delete(s.secrets, profile)
+	}
+	return s.save()
+}
+
+// hasSecret reports whether any of the secrets is set.
+func hasSecret(secrets map[string]string) bool {
+	for _, value := range secrets {
+		if value != "" {
+			return true
+		}
+	}
+	return false
+}
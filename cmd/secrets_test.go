@@ -0,0 +1,192 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSecretTool installs a secret-tool stand-in that keeps each secret in a
+// file named after its attributes, and returns the directory it keeps them in.
+func fakeSecretTool(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	script := `#!/bin/sh
+action=$1; shift
+[ "$action" = store ] && shift 2
+file="` + dir + `/$(echo "$@" | tr ' ' '_')"
+case $action in
+store) cat > "$file" ;;
+lookup) [ -f "$file" ] || exit 1; cat "$file" ;;
+clear) rm -f "$file" ;;
+esac
+`
+	path := filepath.Join(t.TempDir(), "secret-tool")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	original := secretToolCommand
+	secretToolCommand = path
+	t.Cleanup(func() { secretToolCommand = original })
+	return dir
+}
+
+// setSecretPassphrase gives the encrypted secrets file a passphrase, and a
+// cheap key derivation, for the duration of a test.
+func setSecretPassphrase(t *testing.T, pass string) {
+	t.Helper()
+	t.Setenv("EVERNOTE_SECRET_PASSPHRASE", pass)
+	secretPassphrase, scryptWorkFactor = "", 10
+	t.Cleanup(func() { secretPassphrase, scryptWorkFactor = "", 18 })
+}
+
+// rawProfile returns a profile as written in the config file.
+func rawProfile(t *testing.T, name string) *Config {
+	t.Helper()
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	var f configFile
+	require.NoError(t, json.Unmarshal(data, &f))
+	return f.Profiles[name]
+}
+
+func TestKeyringSecretStore(t *testing.T) {
+	setConfigPath(t)
+	dir := fakeSecretTool(t)
+
+	require.NoError(t, saveConfig(&Config{ClientID: "id", ClientSecret: "secret", AuthToken: "token", SecretStore: "keyring"}))
+	assert.Equal(t, &Config{ClientID: "id", SecretStore: "keyring"}, rawProfile(t, "default"), "no secrets in the config file")
+	stored, err := os.ReadFile(filepath.Join(dir, "service_evernote-cli_profile_default_key_auth_token"))
+	require.NoError(t, err)
+	assert.Equal(t, "token", string(stored))
+
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "token", cfg.AuthToken)
+	assert.Equal(t, "secret", cfg.ClientSecret)
+
+	t.Run("missing secrets are empty", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(dir, "service_evernote-cli_profile_default_key_client_secret")))
+		cfg, err := loadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, "token", cfg.AuthToken)
+		assert.Empty(t, cfg.ClientSecret)
+	})
+
+	t.Run("secret-tool not installed", func(t *testing.T) {
+		fake := secretToolCommand
+		secretToolCommand = filepath.Join(t.TempDir(), "missing")
+		defer func() { secretToolCommand = fake }()
+		_, err := keyringStore{}.get("default", secretAuthToken)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "needs")
+	})
+}
+
+func TestFileSecretStore(t *testing.T) {
+	setConfigPath(t)
+	setSecretPassphrase(t, "correct horse")
+
+	require.NoError(t, saveConfig(&Config{ClientID: "id", ClientSecret: "secret", AuthToken: "token", SecretStore: "file"}))
+	assert.Empty(t, rawProfile(t, "default").AuthToken)
+
+	path := filepath.Join(filepath.Dir(configPath), "secrets.age")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "age-encryption.org/v1"))
+	assert.NotContains(t, string(data), "token")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	secretPassphrase = ""
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "token", cfg.AuthToken)
+	assert.Equal(t, "secret", cfg.ClientSecret)
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		setSecretPassphrase(t, "wrong")
+		_, err := loadConfig()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "wrong passphrase?")
+	})
+}
+
+func TestProfileSecretStoreCmd(t *testing.T) {
+	setConfigPath(t)
+	fakeSecretTool(t)
+	setSecretPassphrase(t, "correct horse")
+	require.NoError(t, saveConfig(&Config{ClientID: "id", ClientSecret: "secret", AuthToken: "token"}))
+	assert.Equal(t, "token", rawProfile(t, "default").AuthToken)
+
+	var buf bytes.Buffer
+	profileSecretStoreCmd.SetOut(&buf)
+	require.NoError(t, profileSecretStoreCmd.RunE(profileSecretStoreCmd, []string{"keyring"}))
+	assert.Equal(t, "Secrets of profile default are now kept in the keyring store.\n", buf.String())
+	assert.Empty(t, rawProfile(t, "default").AuthToken)
+
+	require.NoError(t, profileSecretStoreCmd.RunE(profileSecretStoreCmd, []string{"file"}))
+	keyring, err := keyringStore{}.get("default", secretAuthToken)
+	require.NoError(t, err)
+	assert.Empty(t, keyring, "the old store is cleared")
+
+	buf.Reset()
+	profileListCmd.SetOut(&buf)
+	require.NoError(t, profileListCmd.RunE(profileListCmd, nil))
+	assert.Equal(t, "* default (secrets in file)\n", buf.String())
+
+	require.NoError(t, profileSecretStoreCmd.RunE(profileSecretStoreCmd, []string{"plaintext"}))
+	assert.Equal(t, "token", rawProfile(t, "default").AuthToken)
+	_, err = os.Stat(filepath.Join(filepath.Dir(configPath), "secrets.age"))
+	require.NoError(t, err)
+	store, _ := openSecretStore("file")
+	secret, err := store.get("default", secretAuthToken)
+	require.NoError(t, err)
+	assert.Empty(t, secret)
+
+	err = profileSecretStoreCmd.RunE(profileSecretStoreCmd, []string{"vault"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid secret store "vault"`)
+}
+
+func TestRemoveSecretsWarns(t *testing.T) {
+	setConfigPath(t)
+	fakeSecretTool(t)
+	require.NoError(t, saveConfig(&Config{AuthToken: "token", SecretStore: secretStoreKeyring}))
+	original := secretToolCommand
+	secretToolCommand = filepath.Join(t.TempDir(), "missing")
+	defer func() { secretToolCommand = original }()
+
+	err := removeSecrets("default", secretStoreKeyring)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "from the keyring store")
+
+	t.Run("moving secrets", func(t *testing.T) {
+		var warnings bytes.Buffer
+		secretWarnings = &warnings
+		defer func() { secretWarnings = os.Stderr }()
+		require.NoError(t, saveConfig(&Config{AuthToken: "token", SecretStore: secretStorePlaintext}))
+		assert.Contains(t, warnings.String(), "Warning: could not remove the secrets of profile default from the keyring store")
+		assert.Equal(t, "token", rawProfile(t, "default").AuthToken)
+	})
+
+	t.Run("removing the profile", func(t *testing.T) {
+		require.NoError(t, writeConfigFile(&configFile{Profiles: map[string]*Config{"work": {SecretStore: secretStoreKeyring}}}))
+		profileYes = true
+		defer func() { profileYes = false }()
+		var out, warnings bytes.Buffer
+		profileRemoveCmd.SetOut(&out)
+		profileRemoveCmd.SetErr(&warnings)
+		defer profileRemoveCmd.SetErr(nil)
+		require.NoError(t, profileRemoveCmd.RunE(profileRemoveCmd, []string{"work"}))
+		assert.Contains(t, warnings.String(), "Warning: could not remove the secrets of profile work from the keyring store")
+		assert.Equal(t, "Removed profile work\n", out.String())
+	})
+}
@@ -0,0 +1,301 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+)
+
+// dataDir is where the local mirror is kept. It follows $XDG_DATA_HOME and
+// can be overridden in tests.
+var dataDir = defaultDataDir()
+
+// defaultDataDir returns $XDG_DATA_HOME/evernote-cli, or
+// ~/.local/share/evernote-cli when XDG_DATA_HOME is not set.
+func defaultDataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "evernote-cli")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".local", "share", "evernote-cli")
+}
+
+// Kinds of objects kept in the local store, each in its own directory.
+const (
+	storeNotes     = "notes"
+	storeNotebooks = "notebooks"
+	storeTags      = "tags"
+	storeSearches  = "searches"
+	storeResources = "resources"
+)
+
+// localSyncState records how far the local mirror has been synced.
+type localSyncState struct {
+	// UpdateCount is the account's update count at the end of the last
+	// complete sync.
+	UpdateCount int32 `json:"update_count"`
+	// LastUSN is the highest update sequence number applied, saved after each
+	// chunk so an interrupted sync resumes where it stopped.
+	LastUSN int32 `json:"last_usn"`
+	// LastSyncTime is the server time of the last complete sync, in
+	// milliseconds. It is zero until the first full sync finishes.
+	LastSyncTime int64 `json:"last_sync_time"`
+}
+
+// localStore is an on-disk mirror of an Evernote account. Each object is a
+// JSON file named by its GUID; resource bodies are stored as raw files.
+type localStore struct {
+	dir string
+}
+
+// openLocalStore opens the store in dir, creating it if needed.
+func openLocalStore(dir string) (*localStore, error) {
+	for _, kind := range []string{storeNotes, storeNotebooks, storeTags, storeSearches, storeResources} {
+		if err := os.MkdirAll(filepath.Join(dir, kind), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create local store: %w", err)
+		}
+	}
+	return &localStore{dir: dir}, nil
+}
+
+// openProfileStore opens the local store of the active profile.
+func openProfileStore() (*localStore, error) {
+	dir, err := profileDataDir()
+	if err != nil {
+		return nil, err
+	}
+	return openLocalStore(dir)
+}
+
+// path returns the file for an object. GUIDs are checked so a malformed one
+// cannot escape the store directory.
+func (s *localStore) path(kind string, guid edam.GUID, ext string) (string, error) {
+	g := string(guid)
+	if g == "" || strings.ContainsAny(g, `/\`) || g == "." || g == ".." {
+		return "", fmt.Errorf("invalid GUID %q", g)
+	}
+	return filepath.Join(s.dir, kind, g+ext), nil
+}
+
+// writeFileAtomic writes data to path via a temporary file and rename, so a
+// crash never leaves a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// put stores an object as JSON.
+func (s *localStore) put(kind string, guid edam.GUID, v interface{}) error {
+	path, err := s.path(kind, guid, ".json")
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// get loads an object into v. It returns an error wrapping os.ErrNotExist
+// when the object is not stored.
+func (s *localStore) get(kind string, guid edam.GUID, v interface{}) error {
+	path, err := s.path(kind, guid, ".json")
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// remove deletes an object and, for resources, its body. Missing objects
+// are ignored.
+func (s *localStore) remove(kind string, guid edam.GUID) error {
+	exts := []string{".json"}
+	if kind == storeResources {
+		exts = append(exts, "")
+	}
+	for _, ext := range exts {
+		path, err := s.path(kind, guid, ext)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// list returns the GUIDs of every stored object of a kind, sorted.
+func (s *localStore) list(kind string) ([]edam.GUID, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, kind))
+	if err != nil {
+		return nil, err
+	}
+	var guids []edam.GUID
+	for _, e := range entries {
+		if name := e.Name(); strings.HasSuffix(name, ".json") && !strings.HasPrefix(name, ".") {
+			guids = append(guids, edam.GUID(strings.TrimSuffix(name, ".json")))
+		}
+	}
+	sort.Slice(guids, func(i, j int) bool { return guids[i] < guids[j] })
+	return guids, nil
+}
+
+// modTimes returns the modification time, in nanoseconds, of every stored
+// object of a kind.
+func (s *localStore) modTimes(kind string) (map[edam.GUID]int64, error) {
+	guids, err := s.list(kind)
+	if err != nil {
+		return nil, err
+	}
+	stamps := make(map[edam.GUID]int64, len(guids))
+	for _, guid := range guids {
+		info, err := os.Stat(filepath.Join(s.dir, kind, string(guid)+".json"))
+		if err != nil {
+			return nil, err
+		}
+		stamps[guid] = info.ModTime().UnixNano()
+	}
+	return stamps, nil
+}
+
+// putResourceData stores the body of a resource.
+func (s *localStore) putResourceData(guid edam.GUID, data []byte) error {
+	path, err := s.path(storeResources, guid, "")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// resourceData returns the stored body of a resource.
+func (s *localStore) resourceData(guid edam.GUID) ([]byte, error) {
+	path, err := s.path(storeResources, guid, "")
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// notes returns every stored note.
+func (s *localStore) notes() ([]*edam.Note, error) {
+	var notes []*edam.Note
+	err := s.each(storeNotes, func(guid edam.GUID) error {
+		var note edam.Note
+		notes = append(notes, &note)
+		return s.get(storeNotes, guid, &note)
+	})
+	return notes, err
+}
+
+// notebooks returns every stored notebook.
+func (s *localStore) notebooks() ([]*edam.Notebook, error) {
+	var notebooks []*edam.Notebook
+	err := s.each(storeNotebooks, func(guid edam.GUID) error {
+		var nb edam.Notebook
+		notebooks = append(notebooks, &nb)
+		return s.get(storeNotebooks, guid, &nb)
+	})
+	return notebooks, err
+}
+
+// tags returns every stored tag.
+func (s *localStore) tags() ([]*edam.Tag, error) {
+	var tags []*edam.Tag
+	err := s.each(storeTags, func(guid edam.GUID) error {
+		var tag edam.Tag
+		tags = append(tags, &tag)
+		return s.get(storeTags, guid, &tag)
+	})
+	return tags, err
+}
+
+// searches returns every stored saved search.
+func (s *localStore) searches() ([]*edam.SavedSearch, error) {
+	var searches []*edam.SavedSearch
+	err := s.each(storeSearches, func(guid edam.GUID) error {
+		var search edam.SavedSearch
+		searches = append(searches, &search)
+		return s.get(storeSearches, guid, &search)
+	})
+	return searches, err
+}
+
+// each calls fn with the GUID of every stored object of a kind.
+func (s *localStore) each(kind string, fn func(guid edam.GUID) error) error {
+	guids, err := s.list(kind)
+	if err != nil {
+		return err
+	}
+	for _, guid := range guids {
+		if err := fn(guid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// state returns the sync state, or a zero state before the first sync.
+func (s *localStore) state() (*localSyncState, error) {
+	var st localSyncState
+	data, err := os.ReadFile(filepath.Join(s.dir, "state.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return &st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("corrupt sync state: %w", err)
+	}
+	return &st, nil
+}
+
+// saveState records the sync state.
+func (s *localStore) saveState(st *localSyncState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(s.dir, "state.json"), data)
+}
+
+// reset removes every stored object and the sync state.
+func (s *localStore) reset() error {
+	for _, kind := range []string{storeNotes, storeNotebooks, storeTags, storeSearches, storeResources} {
+		if err := os.RemoveAll(filepath.Join(s.dir, kind)); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Join(s.dir, kind), 0700); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(filepath.Join(s.dir, "state.json")); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setDataDir points the local store at a temporary directory for a test.
+func setDataDir(t *testing.T) string {
+	t.Helper()
+	original := dataDir
+	dataDir = t.TempDir()
+	t.Cleanup(func() { dataDir = original })
+	return dataDir
+}
+
+func TestDefaultDataDir(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/data")
+	assert.Equal(t, filepath.Join("/data", "evernote-cli"), defaultDataDir())
+
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("HOME", "/home/me")
+	assert.Equal(t, filepath.Join("/home/me", ".local", "share", "evernote-cli"), defaultDataDir())
+}
+
+func TestLocalStore(t *testing.T) {
+	store, err := openLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	t.Run("put, get and list", func(t *testing.T) {
+		for _, g := range []string{"n-2", "n-1"} {
+			guid, title := edam.GUID(g), "Note "+g
+			require.NoError(t, store.put(storeNotes, guid, &edam.Note{GUID: &guid, Title: &title}))
+		}
+
+		var note edam.Note
+		require.NoError(t, store.get(storeNotes, "n-1", &note))
+		assert.Equal(t, "Note n-1", note.GetTitle())
+
+		guids, err := store.list(storeNotes)
+		require.NoError(t, err)
+		assert.Equal(t, []edam.GUID{"n-1", "n-2"}, guids)
+
+		notes, err := store.notes()
+		require.NoError(t, err)
+		require.Len(t, notes, 2)
+		assert.Equal(t, "Note n-2", notes[1].GetTitle())
+	})
+
+	t.Run("missing objects", func(t *testing.T) {
+		var note edam.Note
+		err := store.get(storeNotes, "nope", &note)
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+		assert.NoError(t, store.remove(storeNotes, "nope"))
+	})
+
+	t.Run("resources keep their body separately", func(t *testing.T) {
+		guid := edam.GUID("r-1")
+		require.NoError(t, store.put(storeResources, guid, &edam.Resource{GUID: &guid}))
+		require.NoError(t, store.putResourceData(guid, []byte("body")))
+
+		data, err := store.resourceData(guid)
+		require.NoError(t, err)
+		assert.Equal(t, "body", string(data))
+
+		require.NoError(t, store.remove(storeResources, guid))
+		_, err = store.resourceData(guid)
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+	})
+
+	t.Run("rejects unsafe GUIDs", func(t *testing.T) {
+		for _, guid := range []edam.GUID{"", "..", "../x", `a\b`} {
+			assert.Error(t, store.put(storeNotes, guid, &edam.Note{}), string(guid))
+		}
+	})
+
+	t.Run("state and reset", func(t *testing.T) {
+		st, err := store.state()
+		require.NoError(t, err)
+		assert.Equal(t, &localSyncState{}, st)
+
+		require.NoError(t, store.saveState(&localSyncState{UpdateCount: 9, LastUSN: 9, LastSyncTime: 1000}))
+		st, err = store.state()
+		require.NoError(t, err)
+		assert.Equal(t, int32(9), st.LastUSN)
+
+		require.NoError(t, store.reset())
+		st, err = store.state()
+		require.NoError(t, err)
+		assert.Equal(t, &localSyncState{}, st)
+		guids, err := store.list(storeNotes)
+		require.NoError(t, err)
+		assert.Empty(t, guids)
+	})
+}
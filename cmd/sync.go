@@ -0,0 +1,303 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/spf13/cobra"
+)
+
+// syncChunkSize is the number of entries requested per GetFilteredSyncChunk call.
+const syncChunkSize = 100
+
+var (
+	syncResources bool
+	syncFull      bool
+)
+
+// syncSummary reports what a sync changed in the local store.
+type syncSummary struct {
+	Full      bool  `json:"full"`
+	UpToDate  bool  `json:"up_to_date"`
+	Notes     int   `json:"notes"`
+	Notebooks int   `json:"notebooks"`
+	Tags      int   `json:"tags"`
+	Searches  int   `json:"searches"`
+	Resources int   `json:"resources"`
+	Expunged  int   `json:"expunged"`
+	USN       int32 `json:"usn"`
+}
+
+// syncer applies sync chunks from the server to a local store.
+type syncer struct {
+	ns            noteStoreClient
+	token         string
+	store         *localStore
+	withResources bool
+	progress      io.Writer
+	summary       syncSummary
+}
+
+// syncAccount brings the local store up to date with the account. The first
+// sync, or one the server demands by moving FullSyncBefore past the last
+// sync, downloads everything; later syncs only fetch what changed since the
+// stored update sequence number. Progress is saved after every chunk, so an
+// interrupted sync resumes where it stopped.
+func syncAccount(ns noteStoreClient, token string, store *localStore, withResources, full bool, progress io.Writer) (*syncSummary, error) {
+	state, err := store.state()
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := ns.GetSyncState(context.Background(), token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync state: %w", formatAPIError(err))
+	}
+
+	s := &syncer{ns: ns, token: token, store: store, withResources: withResources, progress: progress}
+	if full || (state.LastSyncTime != 0 && int64(server.GetFullSyncBefore()) > state.LastSyncTime) {
+		if err := store.reset(); err != nil {
+			return nil, fmt.Errorf("failed to reset local store: %w", err)
+		}
+		state = &localSyncState{}
+	}
+	s.summary.Full = state.LastSyncTime == 0
+	if !s.summary.Full && server.GetUpdateCount() == state.UpdateCount {
+		s.summary.UpToDate = true
+		s.summary.USN = state.LastUSN
+		return &s.summary, nil
+	}
+
+	yes := true
+	filter := &edam.SyncChunkFilter{
+		IncludeNotes:          &yes,
+		IncludeNoteResources:  &yes,
+		IncludeNoteAttributes: &yes,
+		IncludeNotebooks:      &yes,
+		IncludeTags:           &yes,
+		IncludeSearches:       &yes,
+		IncludeResources:      &withResources,
+	}
+	if !s.summary.Full {
+		filter.IncludeExpunged = &yes
+	}
+
+	updateCount := server.GetUpdateCount()
+	for state.LastUSN < updateCount {
+		chunk, err := ns.GetFilteredSyncChunk(context.Background(), token, state.LastUSN, syncChunkSize, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sync chunk: %w", formatAPIError(err))
+		}
+		if err := s.apply(chunk); err != nil {
+			return nil, err
+		}
+		updateCount = chunk.GetUpdateCount()
+		if chunk.ChunkHighUSN == nil || chunk.GetChunkHighUSN() <= state.LastUSN {
+			break
+		}
+		state.LastUSN = chunk.GetChunkHighUSN()
+		if err := store.saveState(state); err != nil {
+			return nil, fmt.Errorf("failed to save sync state: %w", err)
+		}
+		if progress != nil {
+			fmt.Fprintf(progress, "Synced %d of %d updates\n", state.LastUSN, updateCount)
+		}
+	}
+
+	state.UpdateCount = updateCount
+	state.LastSyncTime = int64(server.GetCurrentTime())
+	if err := store.saveState(state); err != nil {
+		return nil, fmt.Errorf("failed to save sync state: %w", err)
+	}
+	s.summary.USN = state.LastUSN
+	return &s.summary, nil
+}
+
+// apply stores everything in one sync chunk.
+func (s *syncer) apply(chunk *edam.SyncChunk) error {
+	for _, nb := range chunk.GetNotebooks() {
+		if err := s.store.put(storeNotebooks, nb.GetGUID(), nb); err != nil {
+			return err
+		}
+		s.summary.Notebooks++
+	}
+	for _, tag := range chunk.GetTags() {
+		if err := s.store.put(storeTags, tag.GetGUID(), tag); err != nil {
+			return err
+		}
+		s.summary.Tags++
+	}
+	for _, search := range chunk.GetSearches() {
+		if err := s.store.put(storeSearches, search.GetGUID(), search); err != nil {
+			return err
+		}
+		s.summary.Searches++
+	}
+
+	for _, meta := range chunk.GetNotes() {
+		// Sync chunks carry metadata only, so fetch the content separately,
+		// with recognition text so attachments can be searched locally
+		note, err := s.ns.GetNote(context.Background(), s.token, meta.GetGUID(), true, false, true, false)
+		if err != nil {
+			return fmt.Errorf("failed to get note %s: %w", meta.GetGUID(), formatAPIError(err))
+		}
+		if err := s.store.put(storeNotes, note.GetGUID(), note); err != nil {
+			return err
+		}
+		s.summary.Notes++
+		if s.withResources {
+			for _, res := range note.GetResources() {
+				if err := s.syncResource(res); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if s.withResources {
+		for _, res := range chunk.GetResources() {
+			if err := s.syncResource(res); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, guid := range chunk.GetExpungedNotes() {
+		var note edam.Note
+		if err := s.store.get(storeNotes, guid, &note); err == nil {
+			for _, res := range note.GetResources() {
+				if err := s.store.remove(storeResources, res.GetGUID()); err != nil {
+					return err
+				}
+			}
+		}
+		if err := s.store.remove(storeNotes, guid); err != nil {
+			return err
+		}
+		s.summary.Expunged++
+	}
+	expunged := []struct {
+		kind  string
+		guids []edam.GUID
+	}{
+		{storeNotebooks, chunk.GetExpungedNotebooks()},
+		{storeTags, chunk.GetExpungedTags()},
+		{storeSearches, chunk.GetExpungedSearches()},
+	}
+	for _, e := range expunged {
+		for _, guid := range e.guids {
+			if err := s.store.remove(e.kind, guid); err != nil {
+				return err
+			}
+			s.summary.Expunged++
+		}
+	}
+	return nil
+}
+
+// syncResource downloads a resource body unless the stored copy already has
+// the same hash.
+func (s *syncer) syncResource(res *edam.Resource) error {
+	var stored edam.Resource
+	if err := s.store.get(storeResources, res.GetGUID(), &stored); err == nil {
+		if _, err := s.store.resourceData(res.GetGUID()); err == nil && stored.GetData() != nil && res.GetData() != nil &&
+			bytes.Equal(stored.GetData().GetBodyHash(), res.GetData().GetBodyHash()) {
+			return nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	full, err := s.ns.GetResource(context.Background(), s.token, res.GetGUID(), true, false, true, false)
+	if err != nil {
+		return fmt.Errorf("failed to get resource %s: %w", res.GetGUID(), formatAPIError(err))
+	}
+	if full.GetData() == nil {
+		return fmt.Errorf("resource %s has no data", res.GetGUID())
+	}
+	if err := s.store.putResourceData(full.GetGUID(), full.GetData().GetBody()); err != nil {
+		return err
+	}
+
+	// Keep the metadata without the body, which is stored on its own
+	meta := *full
+	if full.Data != nil {
+		data := *full.Data
+		data.Body = nil
+		meta.Data = &data
+	}
+	if err := s.store.put(storeResources, full.GetGUID(), &meta); err != nil {
+		return err
+	}
+	s.summary.Resources++
+	return nil
+}
+
+// syncCmd mirrors the account into the local store.
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync notes to a local mirror",
+	Long: `Download notes, notebooks, tags and saved searches into a local mirror under
+~/.local/share/evernote-cli (or $XDG_DATA_HOME/evernote-cli).
+
+The first run downloads everything. Later runs only fetch what changed and
+remove anything deleted in Evernote. An interrupted sync resumes where it
+stopped. Use --resources to also download attachments, or --full to discard
+the mirror and download everything again.
+
+Examples:
+  evernote-cli sync
+  evernote-cli sync --resources`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ns, token, err := getRemoteNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		store, err := openProfileStore()
+		if err != nil {
+			return err
+		}
+
+		var progress io.Writer
+		if !jsonFlag {
+			progress = cmd.ErrOrStderr()
+		}
+		summary, err := syncAccount(ns, token, store, syncResources, syncFull, progress)
+		if err != nil {
+			return err
+		}
+
+		if jsonFlag {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(summary)
+		}
+
+		if summary.UpToDate {
+			fmt.Fprintln(cmd.OutOrStdout(), "Already up to date.")
+			return nil
+		}
+		kind := "Incremental"
+		if summary.Full {
+			kind = "Full"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s sync complete: %d note(s), %d notebook(s), %d tag(s), %d saved search(es), %d resource(s) updated; %d expunged.\n",
+			kind, summary.Notes, summary.Notebooks, summary.Tags, summary.Searches, summary.Resources, summary.Expunged)
+		return nil
+	},
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncResources, "resources", false, "also download attachment bodies")
+	syncCmd.Flags().BoolVar(&syncFull, "full", false, "discard the local mirror and download everything again")
+	rootCmd.AddCommand(syncCmd)
+}
@@ -0,0 +1,233 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncTestNote returns a note with content and a single attachment.
+func syncTestNote(guid, title string, usn int32) *edam.Note {
+	g, resGUID := edam.GUID(guid), edam.GUID(guid+"-res")
+	content := wrapENML(title)
+	return &edam.Note{
+		GUID:              &g,
+		Title:             &title,
+		Content:           &content,
+		UpdateSequenceNum: &usn,
+		Resources: []*edam.Resource{
+			{GUID: &resGUID, Data: &edam.Data{BodyHash: []byte(title)}},
+		},
+	}
+}
+
+func TestSyncCommand(t *testing.T) {
+	nbGUID, nbName := edam.GUID("nb-1"), "Inbox"
+	tagGUID, tagName := edam.GUID("tag-1"), "work"
+	n1 := syncTestNote("n-1", "First", 2)
+	n2 := syncTestNote("n-2", "Second", 3)
+	high1, high2 := int32(2), int32(4)
+	resBody := []byte("attachment")
+
+	newMock := func() *mockNoteStore {
+		return &mockNoteStore{
+			syncState: &edam.SyncState{UpdateCount: 4, CurrentTime: 5000},
+			syncChunks: []*edam.SyncChunk{
+				{
+					ChunkHighUSN: &high1,
+					UpdateCount:  4,
+					Notebooks:    []*edam.Notebook{{GUID: &nbGUID, Name: &nbName}},
+					Notes:        []*edam.Note{{GUID: n1.GUID}},
+				},
+				{
+					ChunkHighUSN: &high2,
+					UpdateCount:  4,
+					Tags:         []*edam.Tag{{GUID: &tagGUID, Name: &tagName}},
+					Notes:        []*edam.Note{{GUID: n2.GUID}},
+				},
+			},
+			notesByGUID: map[edam.GUID]*edam.Note{"n-1": n1, "n-2": n2},
+			resource:    &edam.Resource{GUID: n1.Resources[0].GUID, Data: &edam.Data{Body: resBody, BodyHash: []byte("First")}},
+		}
+	}
+
+	t.Run("full then incremental sync", func(t *testing.T) {
+		dir := setDataDir(t)
+		mock := newMock()
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+
+		jsonFlag = false
+		var buf, progress bytes.Buffer
+		syncCmd.SetOut(&buf)
+		syncCmd.SetErr(&progress)
+		require.NoError(t, syncCmd.RunE(syncCmd, nil))
+		assert.Equal(t, "Full sync complete: 2 note(s), 1 notebook(s), 1 tag(s), 0 saved search(es), 0 resource(s) updated; 0 expunged.\n", buf.String())
+		assert.Contains(t, progress.String(), "Synced 4 of 4 updates")
+		assert.Nil(t, mock.syncFilter.IncludeExpunged)
+
+		store, err := openLocalStore(dir)
+		require.NoError(t, err)
+		var note edam.Note
+		require.NoError(t, store.get(storeNotes, "n-2", &note))
+		assert.Equal(t, n2.GetContent(), note.GetContent())
+		st, err := store.state()
+		require.NoError(t, err)
+		assert.Equal(t, &localSyncState{UpdateCount: 4, LastUSN: 4, LastSyncTime: 5000}, st)
+
+		// Nothing changed on the server
+		buf.Reset()
+		mock.chunkCalls = 0
+		require.NoError(t, syncCmd.RunE(syncCmd, nil))
+		assert.Equal(t, "Already up to date.\n", buf.String())
+		assert.Zero(t, mock.chunkCalls)
+
+		// A note is expunged and a search is added
+		high3, searchGUID, searchName := int32(6), edam.GUID("s-1"), "todo"
+		mock.syncState = &edam.SyncState{UpdateCount: 6, CurrentTime: 6000}
+		mock.syncChunks = []*edam.SyncChunk{{
+			ChunkHighUSN:  &high3,
+			UpdateCount:   6,
+			Searches:      []*edam.SavedSearch{{GUID: &searchGUID, Name: &searchName}},
+			ExpungedNotes: []edam.GUID{"n-1"},
+		}}
+		buf.Reset()
+		require.NoError(t, syncCmd.RunE(syncCmd, nil))
+		assert.Equal(t, "Incremental sync complete: 0 note(s), 0 notebook(s), 0 tag(s), 1 saved search(es), 0 resource(s) updated; 1 expunged.\n", buf.String())
+		assert.True(t, mock.syncFilter.GetIncludeExpunged())
+
+		guids, err := store.list(storeNotes)
+		require.NoError(t, err)
+		assert.Equal(t, []edam.GUID{"n-2"}, guids)
+		guids, err = store.list(storeSearches)
+		require.NoError(t, err)
+		assert.Equal(t, []edam.GUID{"s-1"}, guids)
+	})
+
+	t.Run("resumes an interrupted sync", func(t *testing.T) {
+		dir := setDataDir(t)
+		store, err := openLocalStore(dir)
+		require.NoError(t, err)
+		require.NoError(t, store.saveState(&localSyncState{LastUSN: 2}))
+
+		mock := newMock()
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+
+		jsonFlag = true
+		defer func() { jsonFlag = false }()
+		var buf bytes.Buffer
+		syncCmd.SetOut(&buf)
+		syncCmd.SetErr(&bytes.Buffer{})
+		require.NoError(t, syncCmd.RunE(syncCmd, nil))
+
+		var summary syncSummary
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &summary))
+		assert.True(t, summary.Full)
+		assert.Equal(t, 1, summary.Notes)
+		assert.Equal(t, int32(4), summary.USN)
+		assert.Equal(t, 1, mock.chunkCalls)
+	})
+
+	t.Run("full resync when the server requires it", func(t *testing.T) {
+		dir := setDataDir(t)
+		store, err := openLocalStore(dir)
+		require.NoError(t, err)
+		stale := edam.GUID("stale")
+		require.NoError(t, store.put(storeNotes, stale, &edam.Note{GUID: &stale}))
+		require.NoError(t, store.saveState(&localSyncState{UpdateCount: 4, LastUSN: 4, LastSyncTime: 1000}))
+
+		mock := newMock()
+		mock.syncState.FullSyncBefore = 2000
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+
+		jsonFlag = false
+		var buf bytes.Buffer
+		syncCmd.SetOut(&buf)
+		syncCmd.SetErr(&bytes.Buffer{})
+		require.NoError(t, syncCmd.RunE(syncCmd, nil))
+		assert.Contains(t, buf.String(), "Full sync complete: 2 note(s)")
+
+		guids, err := store.list(storeNotes)
+		require.NoError(t, err)
+		assert.Equal(t, []edam.GUID{"n-1", "n-2"}, guids)
+	})
+
+	t.Run("downloads resources", func(t *testing.T) {
+		dir := setDataDir(t)
+		mock := newMock()
+		mock.resourcesByGUID = map[edam.GUID]*edam.Resource{
+			"n-2-res": {GUID: n2.Resources[0].GUID, Data: &edam.Data{Body: []byte("second"), BodyHash: []byte("Second")}},
+		}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+
+		syncResources = true
+		defer func() { syncResources = false }()
+		jsonFlag = false
+		var buf bytes.Buffer
+		syncCmd.SetOut(&buf)
+		syncCmd.SetErr(&bytes.Buffer{})
+		require.NoError(t, syncCmd.RunE(syncCmd, nil))
+		assert.Contains(t, buf.String(), "2 resource(s) updated")
+		assert.True(t, mock.syncFilter.GetIncludeResources())
+
+		store, err := openLocalStore(dir)
+		require.NoError(t, err)
+		data, err := store.resourceData("n-1-res")
+		require.NoError(t, err)
+		assert.Equal(t, resBody, data)
+
+		var res edam.Resource
+		require.NoError(t, store.get(storeResources, "n-1-res", &res))
+		assert.Nil(t, res.GetData().GetBody())
+
+		// Expunging the note removes its resources
+		high3 := int32(5)
+		mock.syncState = &edam.SyncState{UpdateCount: 5, CurrentTime: 6000}
+		mock.syncChunks = []*edam.SyncChunk{{ChunkHighUSN: &high3, UpdateCount: 5, ExpungedNotes: []edam.GUID{"n-1"}}}
+		require.NoError(t, syncCmd.RunE(syncCmd, nil))
+		_, err = store.resourceData("n-1-res")
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		setDataDir(t)
+		mock := &mockNoteStore{notebooks: []*edam.Notebook{}, err: fmt.Errorf("rate limited")}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+
+		err := syncCmd.RunE(syncCmd, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get sync state: rate limited")
+	})
+}
+
+func TestSyncCmdConfiguration(t *testing.T) {
+	assert.Equal(t, "sync", syncCmd.Use)
+	assert.Equal(t, "Sync notes to a local mirror", syncCmd.Short)
+	assert.NotNil(t, syncCmd.RunE)
+	assert.NotNil(t, syncCmd.Flags().Lookup("resources"))
+	assert.NotNil(t, syncCmd.Flags().Lookup("full"))
+}
+
+func TestSyncCmdRegistration(t *testing.T) {
+	found := false
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "sync" {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "sync command should be registered")
+}
@@ -4,14 +4,133 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
 
+	"github.com/dreampuf/evernote-sdk-golang/edam"
 	"github.com/spf13/cobra"
 )
 
+var (
+	tagParent string
+	tagYes    bool
+)
+
+// validTagName checks a tag name against Evernote's rules.
+func validTagName(name string) error {
+	if name == "" || strings.TrimSpace(name) != name {
+		return fmt.Errorf("tag name cannot be empty or start or end with whitespace")
+	}
+	if strings.Contains(name, ",") {
+		return fmt.Errorf("tag name cannot contain a comma")
+	}
+	if len([]rune(name)) > 100 {
+		return fmt.Errorf("tag name cannot be longer than 100 characters")
+	}
+	return nil
+}
+
+// printTagResult prints a tag after a change, as JSON with --json.
+func printTagResult(w io.Writer, action string, tag *edam.Tag) error {
+	if jsonFlag {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tag)
+	}
+	fmt.Fprintf(w, "Tag %s: %s\n", action, tag.GetName())
+	fmt.Fprintf(w, "GUID: %s\n", tag.GetGUID())
+	return nil
+}
+
+// tagNode is a tag and its children in the tag hierarchy.
+type tagNode struct {
+	Tag      *edam.Tag  `json:"tag"`
+	Children []*tagNode `json:"children,omitempty"`
+}
+
+// buildTagTree arranges tags into their hierarchy, sorted by name. Tags whose
+// parent is missing are placed at the top level.
+func buildTagTree(tags []*edam.Tag) []*tagNode {
+	nodes := make(map[edam.GUID]*tagNode, len(tags))
+	for _, tag := range tags {
+		nodes[tag.GetGUID()] = &tagNode{Tag: tag}
+	}
+
+	var roots []*tagNode
+	for _, tag := range tags {
+		node := nodes[tag.GetGUID()]
+		if parent, ok := nodes[tag.GetParentGuid()]; ok && tag.ParentGuid != nil && parent != node {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	var sortNodes func([]*tagNode)
+	sortNodes = func(list []*tagNode) {
+		sort.Slice(list, func(i, j int) bool {
+			return strings.ToLower(list[i].Tag.GetName()) < strings.ToLower(list[j].Tag.GetName())
+		})
+		for _, n := range list {
+			sortNodes(n.Children)
+		}
+	}
+	sortNodes(roots)
+	return roots
+}
+
+// printTagTree writes tag nodes as an indented tree.
+func printTagTree(w io.Writer, nodes []*tagNode, prefix string) {
+	for i, n := range nodes {
+		branch, indent := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%s%s%s [%s]\n", prefix, branch, n.Tag.GetName(), n.Tag.GetGUID())
+		printTagTree(w, n.Children, prefix+indent)
+	}
+}
+
+// isTagDescendant reports whether guid is ancestor itself or one of its
+// descendants, following parent links.
+func isTagDescendant(tags []*edam.Tag, guid, ancestor edam.GUID) bool {
+	parents := make(map[edam.GUID]edam.GUID, len(tags))
+	for _, tag := range tags {
+		if tag.ParentGuid != nil {
+			parents[tag.GetGUID()] = tag.GetParentGuid()
+		}
+	}
+	seen := make(map[edam.GUID]bool)
+	for g := guid; !seen[g]; {
+		if g == ancestor {
+			return true
+		}
+		seen[g] = true
+		parent, ok := parents[g]
+		if !ok {
+			break
+		}
+		g = parent
+	}
+	return false
+}
+
 // tagsCmd lists all tags in the authenticated Evernote account.
 var tagsCmd = &cobra.Command{
 	Use:   "tags",
 	Short: "List all tags",
+	Long: `List all tags, or manage them with the subcommands below. Tags can be given
+by name or GUID.
+
+Examples:
+  evernote-cli tags
+  evernote-cli tags tree
+  evernote-cli tags create Reports --parent Work
+  evernote-cli tags rename reprots Reports
+  evernote-cli tags move Reports --parent Archive
+  evernote-cli tags merge "to-do" todo`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ns, token, err := getNoteStoreFunc()
 		if err != nil {
@@ -48,6 +167,269 @@ var tagsCmd = &cobra.Command{
 	},
 }
 
+// tagsTreeCmd shows the tag hierarchy.
+var tagsTreeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "Show tags as a tree",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		tags, err := ns.ListTags(context.Background(), token)
+		if err != nil {
+			return fmt.Errorf("failed to list tags: %w", formatAPIError(err))
+		}
+		tree := buildTagTree(tags)
+
+		if jsonFlag {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(tree)
+		}
+
+		if len(tree) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No tags found.")
+			return nil
+		}
+		printTagTree(cmd.OutOrStdout(), tree, "")
+		return nil
+	},
+}
+
+// tagsCreateCmd creates a tag, optionally under a parent tag.
+var tagsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a tag",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validTagName(args[0]); err != nil {
+			return err
+		}
+
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		tag := &edam.Tag{Name: &args[0]}
+		if tagParent != "" {
+			parent, err := newNameResolver(ns, token).tag(tagParent)
+			if err != nil {
+				return err
+			}
+			tag.ParentGuid = parent.GUID
+		}
+
+		created, err := ns.CreateTag(context.Background(), token, tag)
+		if err != nil {
+			return fmt.Errorf("failed to create tag: %w", formatAPIError(err))
+		}
+		return printTagResult(cmd.OutOrStdout(), "created", created)
+	},
+}
+
+// tagsRenameCmd renames a tag.
+var tagsRenameCmd = &cobra.Command{
+	Use:   "rename <tag> <new-name>",
+	Short: "Rename a tag",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validTagName(args[1]); err != nil {
+			return err
+		}
+
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		tag, err := newNameResolver(ns, token).tag(args[0])
+		if err != nil {
+			return err
+		}
+
+		// UpdateTag replaces the tag, so send a copy with only the name changed
+		updated := *tag
+		updated.Name = &args[1]
+		if _, err := ns.UpdateTag(context.Background(), token, &updated); err != nil {
+			return fmt.Errorf("failed to rename tag: %w", formatAPIError(err))
+		}
+		return printTagResult(cmd.OutOrStdout(), "renamed", &updated)
+	},
+}
+
+// tagsMoveCmd changes a tag's parent.
+var tagsMoveCmd = &cobra.Command{
+	Use:   "move <tag> --parent <tag>",
+	Short: "Move a tag under another tag",
+	Long:  `Move a tag under another tag. Use --parent "" to move it to the top level.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cmd.Flags().Changed("parent") {
+			return fmt.Errorf("--parent is required (use --parent \"\" to move to the top level)")
+		}
+
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		resolver := newNameResolver(ns, token)
+		tag, err := resolver.tag(args[0])
+		if err != nil {
+			return err
+		}
+
+		updated := *tag
+		updated.ParentGuid = nil
+		if tagParent != "" {
+			parent, err := resolver.tag(tagParent)
+			if err != nil {
+				return err
+			}
+			tags, _ := resolver.listTags()
+			if isTagDescendant(tags, parent.GetGUID(), tag.GetGUID()) {
+				return fmt.Errorf("cannot move %q under itself or one of its children", tag.GetName())
+			}
+			updated.ParentGuid = parent.GUID
+		}
+
+		if _, err := ns.UpdateTag(context.Background(), token, &updated); err != nil {
+			return fmt.Errorf("failed to move tag: %w", formatAPIError(err))
+		}
+		return printTagResult(cmd.OutOrStdout(), "moved", &updated)
+	},
+}
+
+// tagsDeleteCmd permanently deletes a tag.
+var tagsDeleteCmd = &cobra.Command{
+	Use:   "delete <tag>",
+	Short: "Delete a tag",
+	Long: `Permanently delete a tag and remove it from every note. You are asked to
+confirm first unless --yes is given. Evernote only allows this with a
+full-access API key.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		tag, err := newNameResolver(ns, token).tag(args[0])
+		if err != nil {
+			return err
+		}
+
+		if !tagYes && !confirm(cmd, fmt.Sprintf("Delete tag %q?", tag.GetName())) {
+			fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+			return nil
+		}
+
+		if _, err := ns.ExpungeTag(context.Background(), token, tag.GetGUID()); err != nil {
+			return fmt.Errorf("failed to delete tag: %w", formatAPIError(err))
+		}
+		return printTagResult(cmd.OutOrStdout(), "deleted", tag)
+	},
+}
+
+// tagsMergeCmd moves every note from one tag to another and deletes the first.
+var tagsMergeCmd = &cobra.Command{
+	Use:   "merge <src> <dst>",
+	Short: "Merge one tag into another",
+	Long: `Merge one tag into another: every note tagged <src>, including notes in the
+trash, is retagged with <dst>, child tags of <src> are moved under <dst>,
+and <src> is then deleted. You are asked to confirm first unless --yes is
+given.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		resolver := newNameResolver(ns, token)
+		src, err := resolver.tag(args[0])
+		if err != nil {
+			return err
+		}
+		dst, err := resolver.tag(args[1])
+		if err != nil {
+			return err
+		}
+		if src.GetGUID() == dst.GetGUID() {
+			return fmt.Errorf("cannot merge a tag into itself")
+		}
+
+		// Collect every note first, since retagging changes the search results
+		includeTitle := true
+		includeTagGuids := true
+		spec := &edam.NotesMetadataResultSpec{IncludeTitle: &includeTitle, IncludeTagGuids: &includeTagGuids}
+		var notes []*edam.NoteMetadata
+		for _, inactive := range []bool{false, true} {
+			filter := &edam.NoteFilter{TagGuids: []edam.GUID{src.GetGUID()}}
+			if inactive {
+				filter.Inactive = &inactive
+			}
+			found, err := findAllNotes(ns, token, filter, spec)
+			if err != nil {
+				return err
+			}
+			notes = append(notes, found...)
+		}
+
+		if !tagYes && !confirm(cmd, fmt.Sprintf("Retag %d note(s) from %q to %q and delete %q?", len(notes), src.GetName(), dst.GetName(), src.GetName())) {
+			fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+			return nil
+		}
+
+		out := cmd.OutOrStdout()
+		for _, meta := range notes {
+			tagGUIDs := []edam.GUID{dst.GetGUID()}
+			for _, g := range meta.GetTagGuids() {
+				if g != src.GetGUID() && g != dst.GetGUID() {
+					tagGUIDs = append(tagGUIDs, g)
+				}
+			}
+			guid := meta.GetGUID()
+			title := meta.GetTitle()
+			if _, err := ns.UpdateNote(context.Background(), token, &edam.Note{GUID: &guid, Title: &title, TagGuids: tagGUIDs}); err != nil {
+				return fmt.Errorf("failed to retag note %s: %w", guid, formatAPIError(err))
+			}
+			if !jsonFlag {
+				fmt.Fprintf(out, "Retagged: %s\n", title)
+			}
+		}
+
+		tags, _ := resolver.listTags()
+		for _, child := range tags {
+			if child.ParentGuid == nil || child.GetParentGuid() != src.GetGUID() {
+				continue
+			}
+			updated := *child
+			updated.ParentGuid = dst.GUID
+			if isTagDescendant(tags, dst.GetGUID(), child.GetGUID()) {
+				updated.ParentGuid = src.ParentGuid
+			}
+			if _, err := ns.UpdateTag(context.Background(), token, &updated); err != nil {
+				return fmt.Errorf("failed to move child tag %q: %w", child.GetName(), formatAPIError(err))
+			}
+		}
+
+		if _, err := ns.ExpungeTag(context.Background(), token, src.GetGUID()); err != nil {
+			return fmt.Errorf("failed to delete tag: %w", formatAPIError(err))
+		}
+		return printTagResult(out, "merged into "+dst.GetName(), src)
+	},
+}
+
 func init() {
+	tagsCreateCmd.Flags().StringVar(&tagParent, "parent", "", "parent tag name or GUID")
+	tagsMoveCmd.Flags().StringVar(&tagParent, "parent", "", "new parent tag name or GUID (\"\" for the top level)")
+	tagsDeleteCmd.Flags().BoolVarP(&tagYes, "yes", "y", false, "do not ask for confirmation")
+	tagsMergeCmd.Flags().BoolVarP(&tagYes, "yes", "y", false, "do not ask for confirmation")
+	tagsCmd.AddCommand(tagsTreeCmd, tagsCreateCmd, tagsRenameCmd, tagsMoveCmd, tagsDeleteCmd, tagsMergeCmd)
 	rootCmd.AddCommand(tagsCmd)
 }
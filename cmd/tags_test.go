@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/dreampuf/evernote-sdk-golang/edam"
@@ -121,3 +122,168 @@ func TestTagsCommandRegistration(t *testing.T) {
 	}
 	assert.True(t, found, "tags command should be registered with root command")
 }
+
+// testTags returns a mock tag hierarchy: Work > Reports, Work > Meetings, and
+// a top-level "todo" and "to-do".
+func testTags() []*edam.Tag {
+	work, workGUID := "Work", edam.GUID("tag-work")
+	reports, reportsGUID := "Reports", edam.GUID("tag-reports")
+	meetings, meetingsGUID := "meetings", edam.GUID("tag-meetings")
+	todo, todoGUID := "todo", edam.GUID("tag-todo")
+	todo2, todo2GUID := "to-do", edam.GUID("tag-to-do")
+	return []*edam.Tag{
+		{Name: &reports, GUID: &reportsGUID, ParentGuid: &workGUID},
+		{Name: &work, GUID: &workGUID},
+		{Name: &todo, GUID: &todoGUID},
+		{Name: &meetings, GUID: &meetingsGUID, ParentGuid: &workGUID},
+		{Name: &todo2, GUID: &todo2GUID},
+	}
+}
+
+func TestTagsSubcommands(t *testing.T) {
+	jsonFlag = false
+
+	t.Run("tree", func(t *testing.T) {
+		defer setMockNoteStore(&mockNoteStore{tags: testTags()})()
+
+		var buf bytes.Buffer
+		tagsTreeCmd.SetOut(&buf)
+		require.NoError(t, tagsTreeCmd.RunE(tagsTreeCmd, nil))
+		assert.Equal(t, "├── to-do [tag-to-do]\n├── todo [tag-todo]\n└── Work [tag-work]\n    ├── meetings [tag-meetings]\n    └── Reports [tag-reports]\n", buf.String())
+	})
+
+	t.Run("create with parent", func(t *testing.T) {
+		mock := &mockNoteStore{tags: testTags()}
+		defer setMockNoteStore(mock)()
+
+		tagParent = "work"
+		defer func() { tagParent = "" }()
+
+		var buf bytes.Buffer
+		tagsCreateCmd.SetOut(&buf)
+		require.NoError(t, tagsCreateCmd.RunE(tagsCreateCmd, []string{"Plans"}))
+		require.Len(t, mock.savedTags, 1)
+		assert.Equal(t, "Plans", mock.savedTags[0].GetName())
+		assert.Equal(t, edam.GUID("tag-work"), mock.savedTags[0].GetParentGuid())
+		assert.Contains(t, buf.String(), "Tag created: Plans")
+	})
+
+	t.Run("create rejects commas", func(t *testing.T) {
+		err := tagsCreateCmd.RunE(tagsCreateCmd, []string{"a,b"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "comma")
+	})
+
+	t.Run("rename keeps parent", func(t *testing.T) {
+		mock := &mockNoteStore{tags: testTags()}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		tagsRenameCmd.SetOut(&buf)
+		require.NoError(t, tagsRenameCmd.RunE(tagsRenameCmd, []string{"reports", "Weekly Reports"}))
+		require.Len(t, mock.savedTags, 1)
+		assert.Equal(t, "Weekly Reports", mock.savedTags[0].GetName())
+		assert.Equal(t, edam.GUID("tag-work"), mock.savedTags[0].GetParentGuid())
+	})
+
+	t.Run("move", func(t *testing.T) {
+		mock := &mockNoteStore{tags: testTags()}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		tagsMoveCmd.SetOut(&buf)
+		require.NoError(t, tagsMoveCmd.Flags().Set("parent", "todo"))
+		defer func() { tagParent = ""; tagsMoveCmd.Flags().Lookup("parent").Changed = false }()
+		require.NoError(t, tagsMoveCmd.RunE(tagsMoveCmd, []string{"Reports"}))
+		assert.Equal(t, edam.GUID("tag-todo"), mock.savedTags[0].GetParentGuid())
+
+		require.NoError(t, tagsMoveCmd.Flags().Set("parent", ""))
+		require.NoError(t, tagsMoveCmd.RunE(tagsMoveCmd, []string{"Reports"}))
+		assert.Nil(t, mock.savedTags[1].ParentGuid)
+
+		require.NoError(t, tagsMoveCmd.Flags().Set("parent", "Reports"))
+		err := tagsMoveCmd.RunE(tagsMoveCmd, []string{"Work"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "under itself or one of its children")
+	})
+
+	t.Run("move requires --parent", func(t *testing.T) {
+		err := tagsMoveCmd.RunE(tagsMoveCmd, []string{"Reports"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--parent is required")
+	})
+
+	t.Run("delete with --yes", func(t *testing.T) {
+		mock := &mockNoteStore{tags: testTags()}
+		defer setMockNoteStore(mock)()
+
+		tagYes = true
+		defer func() { tagYes = false }()
+
+		var buf bytes.Buffer
+		tagsDeleteCmd.SetOut(&buf)
+		require.NoError(t, tagsDeleteCmd.RunE(tagsDeleteCmd, []string{"to-do"}))
+		assert.Equal(t, []edam.GUID{"tag-to-do"}, mock.expungedTags)
+		assert.Contains(t, buf.String(), "Tag deleted: to-do")
+	})
+
+	t.Run("merge retags notes and expunges the source", func(t *testing.T) {
+		title1, title2 := "Groceries", "Chores"
+		mock := &mockNoteStore{
+			tags: testTags(),
+			notes: &edam.NotesMetadataList{TotalNotes: 1, Notes: []*edam.NoteMetadata{
+				{GUID: "note-1", Title: &title1, TagGuids: []edam.GUID{"tag-to-do", "tag-work"}},
+			}},
+			trashedNotes: &edam.NotesMetadataList{TotalNotes: 1, Notes: []*edam.NoteMetadata{
+				{GUID: "note-2", Title: &title2, TagGuids: []edam.GUID{"tag-todo", "tag-to-do"}},
+			}},
+		}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		tagsMergeCmd.SetOut(&buf)
+		tagsMergeCmd.SetIn(strings.NewReader("y\n"))
+		defer tagsMergeCmd.SetIn(nil)
+		require.NoError(t, tagsMergeCmd.RunE(tagsMergeCmd, []string{"to-do", "todo"}))
+
+		assert.Contains(t, buf.String(), `Retag 2 note(s) from "to-do" to "todo"`)
+		require.Len(t, mock.savedNotes, 2)
+		assert.Equal(t, []edam.GUID{"tag-todo", "tag-work"}, mock.savedNotes[0].TagGuids)
+		assert.Equal(t, "Groceries", mock.savedNotes[0].GetTitle())
+		assert.Equal(t, []edam.GUID{"tag-todo"}, mock.savedNotes[1].TagGuids)
+		assert.Equal(t, []edam.GUID{"tag-to-do"}, mock.expungedTags)
+		assert.Contains(t, buf.String(), "Tag merged into todo: to-do")
+	})
+
+	t.Run("merge moves child tags", func(t *testing.T) {
+		mock := &mockNoteStore{tags: testTags(), notes: &edam.NotesMetadataList{}}
+		defer setMockNoteStore(mock)()
+
+		tagYes = true
+		defer func() { tagYes = false }()
+
+		var buf bytes.Buffer
+		tagsMergeCmd.SetOut(&buf)
+		require.NoError(t, tagsMergeCmd.RunE(tagsMergeCmd, []string{"Work", "todo"}))
+		require.Len(t, mock.savedTags, 2)
+		for _, tag := range mock.savedTags {
+			assert.Equal(t, edam.GUID("tag-todo"), tag.GetParentGuid())
+		}
+		assert.Equal(t, []edam.GUID{"tag-work"}, mock.expungedTags)
+	})
+
+	t.Run("merge into itself", func(t *testing.T) {
+		defer setMockNoteStore(&mockNoteStore{tags: testTags()})()
+		err := tagsMergeCmd.RunE(tagsMergeCmd, []string{"todo", "tag-todo"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "into itself")
+	})
+
+	t.Run("subcommands are registered", func(t *testing.T) {
+		for _, name := range []string{"tree", "create", "rename", "move", "delete", "merge"} {
+			sub, _, err := tagsCmd.Find([]string{name})
+			require.NoError(t, err)
+			assert.Equal(t, name, sub.Name())
+		}
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/spf13/cobra"
+)
+
+// trashCmd groups commands that work with trashed notes.
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Work with notes in the trash",
+}
+
+// trashListCmd lists the notes in the trash.
+var trashListCmd = &cobra.Command{
+	Use:   "list [query]",
+	Short: "List notes in the trash",
+	Long: `List notes in the trash, optionally limited to those matching a search query.
+
+Examples:
+  evernote-cli trash list
+  evernote-cli trash list "intitle:draft"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ns, token, err := getNoteStoreFunc()
+		if err != nil {
+			return err
+		}
+
+		inactive := true
+		filter := &edam.NoteFilter{Inactive: &inactive}
+		if len(args) > 0 {
+			query := strings.Join(args, " ")
+			filter.Words = &query
+		}
+		includeTitle := true
+		includeUpdated := true
+		includeDeleted := true
+		resultSpec := &edam.NotesMetadataResultSpec{
+			IncludeTitle:   &includeTitle,
+			IncludeUpdated: &includeUpdated,
+			IncludeDeleted: &includeDeleted,
+		}
+
+		notes, err := findAllNotes(ns, token, filter, resultSpec)
+		if err != nil {
+			return err
+		}
+
+		if jsonFlag {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(notes)
+		}
+
+		if len(notes) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "The trash is empty.")
+			return nil
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Found %d note(s) in the trash:\n\n", len(notes))
+		for i, note := range notes {
+			fmt.Fprintf(cmd.OutOrStdout(), "%d. %s\n", i+1, note.GetTitle())
+			fmt.Fprintf(cmd.OutOrStdout(), "   GUID: %s\n", note.GetGUID())
+			if note.GetDeleted() != 0 {
+				deleted := time.Unix(int64(note.GetDeleted())/1000, 0)
+				fmt.Fprintf(cmd.OutOrStdout(), "   Deleted: %s\n", deleted.Format("2006-01-02 15:04:05"))
+			}
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	trashCmd.AddCommand(trashListCmd)
+	rootCmd.AddCommand(trashCmd)
+}
@@ -0,0 +1,61 @@
+// Copyright 2026. All rights reserved.
+// Date: 2026-10-16
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashListCommand(t *testing.T) {
+	t.Run("lists trashed notes", func(t *testing.T) {
+		title := "Scratch"
+		deleted := edam.Timestamp(1700000000000)
+		mock := &mockNoteStore{
+			notes: &edam.NotesMetadataList{
+				TotalNotes: 1,
+				Notes:      []*edam.NoteMetadata{{GUID: "note-1", Title: &title, Deleted: &deleted}},
+			},
+		}
+		defer setMockNoteStore(mock)()
+
+		var buf bytes.Buffer
+		trashListCmd.SetOut(&buf)
+		err := trashListCmd.RunE(trashListCmd, []string{"scratch"})
+		require.NoError(t, err)
+
+		assert.True(t, mock.filter.GetInactive())
+		assert.Equal(t, "scratch", mock.filter.GetWords())
+		assert.Contains(t, buf.String(), "Found 1 note(s) in the trash:")
+		assert.Contains(t, buf.String(), "1. Scratch")
+		assert.Contains(t, buf.String(), "GUID: note-1")
+		assert.Contains(t, buf.String(), "Deleted:")
+	})
+
+	t.Run("empty trash", func(t *testing.T) {
+		defer setMockNoteStore(&mockNoteStore{notes: &edam.NotesMetadataList{}})()
+
+		var buf bytes.Buffer
+		trashListCmd.SetOut(&buf)
+		require.NoError(t, trashListCmd.RunE(trashListCmd, nil))
+		assert.Contains(t, buf.String(), "The trash is empty.")
+	})
+}
+
+func TestTrashCmdRegistration(t *testing.T) {
+	found := false
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "trash" {
+			found = true
+			sub, _, err := c.Find([]string{"list"})
+			require.NoError(t, err)
+			assert.Equal(t, "list", sub.Name())
+			break
+		}
+	}
+	assert.True(t, found, "trash command should be registered")
+}
@@ -6,18 +6,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 
 	"github.com/dreampuf/evernote-sdk-golang/edam"
 	"github.com/spf13/cobra"
 )
 
 var (
-	updateTitle  string
-	updateBody   string
-	updateHTML   string
-	updateAppend string
-	updateTags   []string
+	updateTitle    string
+	updateBody     string
+	updateHTML     string
+	updateAppend   string
+	updatePrepend  string
+	updateFormat   string
+	updateMarkdown string
+	updateMDFile   string
+	updateTags     []string
+	updateSanitize bool
 )
 
 // updateCmd updates an existing note by its GUID.
@@ -25,23 +29,44 @@ var updateCmd = &cobra.Command{
 	Use:   "update [guid]",
 	Short: "Update an existing note",
 	Long: `Update an existing note by GUID. You can change the title, replace the body,
-or append text to the existing content.
+or add content to the start or end of the existing note.
+
+--append and --prepend insert a new paragraph without touching the existing
+markup, so links, lists, checkboxes, tables and attachments are kept. Use
+--format html or --format markdown to insert a formatted fragment instead of
+plain text.
 
 Examples:
   evernote-cli update <guid> --title "New Title"
   evernote-cli update <guid> --body "Replace body with this"
+  evernote-cli update <guid> --md-file notes.md
   evernote-cli update <guid> --append "Add this to the end"
+  evernote-cli update <guid> --prepend "## Today" --format markdown
   evernote-cli update <guid> --title "New Title" --append "And add this"`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if updateTitle == "" && updateBody == "" && updateHTML == "" && updateAppend == "" && len(updateTags) == 0 {
-			return fmt.Errorf("at least one of --title, --body, --html, --append, or --tags is required")
+		updateMD := updateMarkdown != "" || updateMDFile != ""
+		inserting := updateAppend != "" || updatePrepend != ""
+		if updateTitle == "" && updateBody == "" && updateHTML == "" && !updateMD && !inserting && len(updateTags) == 0 {
+			return fmt.Errorf("at least one of --title, --body, --html, --markdown, --md-file, --append, --prepend, or --tags is required")
 		}
 		if updateBody != "" && updateAppend != "" {
 			return fmt.Errorf("--body and --append cannot be used together")
 		}
-		if updateHTML != "" && (updateBody != "" || updateAppend != "") {
-			return fmt.Errorf("--html cannot be used with --body or --append")
+		if updateBody != "" && updatePrepend != "" {
+			return fmt.Errorf("--body and --prepend cannot be used together")
+		}
+		if updateHTML != "" && (updateBody != "" || inserting) {
+			return fmt.Errorf("--html cannot be used with --body, --append or --prepend")
+		}
+		if updateMarkdown != "" && updateMDFile != "" {
+			return fmt.Errorf("--markdown and --md-file cannot be used together")
+		}
+		if updateMD && (updateBody != "" || updateHTML != "" || inserting) {
+			return fmt.Errorf("--markdown and --md-file cannot be used with --body, --html, --append or --prepend")
+		}
+		if updateFormat != "text" && updateFormat != "html" && updateFormat != "markdown" {
+			return fmt.Errorf("invalid --format %q (use text, html or markdown)", updateFormat)
 		}
 
 		ns, token, err := getNoteStoreFunc()
@@ -71,7 +96,21 @@ Examples:
 		}
 
 		// Handle content changes
-		if updateHTML != "" {
+		if updateMD {
+			// Replace body with Markdown, attaching any local images it references
+			src, baseDir, err := readMarkdownInput(cmd, updateMarkdown, updateMDFile)
+			if err != nil {
+				return err
+			}
+			content, images, err := markdownToENML(src, baseDir)
+			if err != nil {
+				return err
+			}
+			note.Content = &content
+			if len(images) > 0 {
+				note.Resources = append(existing.GetResources(), images...)
+			}
+		} else if updateHTML != "" {
 			// Replace body with raw HTML (no escaping)
 			content := wrapHTMLInENML(updateHTML)
 			note.Content = &content
@@ -79,19 +118,52 @@ Examples:
 			// Replace body entirely
 			content := wrapENML(updateBody)
 			note.Content = &content
-		} else if updateAppend != "" {
-			// Append to existing content
-			existingContent := existing.GetContent()
-			plainText := stripENML(existingContent)
-
-			// Combine existing text with appended text
-			var combined string
-			if strings.TrimSpace(plainText) != "" {
-				combined = plainText + "\n\n" + updateAppend
-			} else {
-				combined = updateAppend
+		} else if inserting {
+			// Insert fragments inside <en-note>, leaving existing markup untouched
+			conv := newMarkdownConverter(".")
+			fragment := func(s string) string {
+				switch updateFormat {
+				case "html":
+					return s
+				case "markdown":
+					return conv.convert(s)
+				default:
+					return textFragmentENML(s)
+				}
+			}
+
+			content := existing.GetContent()
+			if updatePrepend != "" {
+				content, err = insertENML(content, fragment(updatePrepend), true)
+				if err != nil {
+					return err
+				}
+			}
+			if updateAppend != "" {
+				content, err = insertENML(content, fragment(updateAppend), false)
+				if err != nil {
+					return err
+				}
+			}
+			if conv.err != nil {
+				return conv.err
+			}
+			note.Content = &content
+			if len(conv.resources) > 0 {
+				note.Resources = append(existing.GetResources(), conv.resources...)
+			}
+		}
+
+		// Validate new content locally so ENML errors are reported before the API call
+		if note.Content != nil {
+			resources := note.Resources
+			if resources == nil {
+				resources = existing.GetResources()
+			}
+			content, err := prepareENML(note.GetContent(), resources, updateSanitize)
+			if err != nil {
+				return err
 			}
-			content := wrapENML(combined)
 			note.Content = &content
 		}
 
@@ -122,7 +194,12 @@ func init() {
 	updateCmd.Flags().StringVar(&updateTitle, "title", "", "new title for the note")
 	updateCmd.Flags().StringVar(&updateBody, "body", "", "replace the note body entirely")
 	updateCmd.Flags().StringVar(&updateHTML, "html", "", "replace the note body with raw HTML (not escaped)")
-	updateCmd.Flags().StringVar(&updateAppend, "append", "", "append text to the existing note content")
+	updateCmd.Flags().StringVar(&updateMarkdown, "markdown", "", "replace the note body with Markdown")
+	updateCmd.Flags().StringVar(&updateMDFile, "md-file", "", "replace the note body with a Markdown file (- for stdin)")
+	updateCmd.Flags().StringVar(&updateAppend, "append", "", "append a paragraph to the end of the note")
+	updateCmd.Flags().StringVar(&updatePrepend, "prepend", "", "insert a paragraph at the start of the note")
+	updateCmd.Flags().StringVar(&updateFormat, "format", "text", "format of --append and --prepend content: text, html or markdown")
 	updateCmd.Flags().StringSliceVar(&updateTags, "tags", nil, "comma separated list of tag names")
+	updateCmd.Flags().BoolVar(&updateSanitize, "sanitize", false, "strip markup that is not valid ENML instead of failing")
 	rootCmd.AddCommand(updateCmd)
 }
@@ -106,6 +106,72 @@ func TestUpdateCommand(t *testing.T) {
 		assert.Contains(t, buf.String(), "Note updated:")
 	})
 
+	t.Run("append and prepend keep formatting", func(t *testing.T) {
+		guid := edam.GUID("note-123")
+		existingTitle := "Daily Log"
+		existingContent := wrapHTMLInENML(`<ul><li><a href="https://x.io">link</a></li></ul><en-media type="image/png" hash="abc0"/>`)
+
+		mock := &mockNoteStore{
+			gotNote: &edam.Note{
+				GUID:    &guid,
+				Title:   &existingTitle,
+				Content: &existingContent,
+				Resources: []*edam.Resource{
+					{Data: &edam.Data{BodyHash: []byte{0xab, 0xc0}}},
+				},
+			},
+		}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+
+		updateTitle = ""
+		updateBody = ""
+		updateAppend = "**done**"
+		updatePrepend = "# Today"
+		updateFormat = "markdown"
+		updateTags = nil
+		defer func() {
+			updateAppend = ""
+			updatePrepend = ""
+			updateFormat = "text"
+		}()
+
+		var buf bytes.Buffer
+		updateCmd.SetOut(&buf)
+		err := updateCmd.RunE(updateCmd, []string{"note-123"})
+		require.NoError(t, err)
+
+		require.NotNil(t, mock.savedNote)
+		assert.Equal(t, wrapHTMLInENML(`<h1>Today</h1><ul><li><a href="https://x.io">link</a></li></ul><en-media type="image/png" hash="abc0"/><p><strong>done</strong></p>`), mock.savedNote.GetContent())
+	})
+
+	t.Run("append plain text is escaped", func(t *testing.T) {
+		guid := edam.GUID("note-123")
+		existingTitle := "My Note"
+		existingContent := wrapHTMLInENML(`<div><b>bold</b></div>`)
+
+		mock := &mockNoteStore{
+			gotNote: &edam.Note{
+				GUID:    &guid,
+				Title:   &existingTitle,
+				Content: &existingContent,
+			},
+		}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+
+		updateTitle = ""
+		updateBody = ""
+		updateAppend = "a <b> c"
+		updateTags = nil
+		defer func() { updateAppend = "" }()
+
+		var buf bytes.Buffer
+		updateCmd.SetOut(&buf)
+		require.NoError(t, updateCmd.RunE(updateCmd, []string{"note-123"}))
+		assert.Equal(t, wrapHTMLInENML(`<div><b>bold</b></div><div>a &lt;b&gt; c</div>`), mock.savedNote.GetContent())
+	})
+
 	t.Run("append to empty note", func(t *testing.T) {
 		guid := edam.GUID("note-123")
 		existingTitle := "Empty Note"
@@ -135,6 +201,51 @@ func TestUpdateCommand(t *testing.T) {
 		assert.Contains(t, buf.String(), "Note updated:")
 	})
 
+	t.Run("replace body with markdown", func(t *testing.T) {
+		guid := edam.GUID("note-123")
+		existingTitle := "My Note"
+		existingContent := wrapENML("old content")
+
+		mock := &mockNoteStore{
+			gotNote: &edam.Note{
+				GUID:    &guid,
+				Title:   &existingTitle,
+				Content: &existingContent,
+			},
+		}
+		cleanup := setMockNoteStore(mock)
+		defer cleanup()
+
+		updateTitle = ""
+		updateBody = ""
+		updateAppend = ""
+		updateMarkdown = "## Heading\n\n- [x] done"
+		updateTags = nil
+		defer func() { updateMarkdown = "" }()
+
+		var buf bytes.Buffer
+		updateCmd.SetOut(&buf)
+		err := updateCmd.RunE(updateCmd, []string{"note-123"})
+		require.NoError(t, err)
+
+		require.NotNil(t, mock.savedNote)
+		assert.Contains(t, mock.savedNote.GetContent(), "<h2>Heading</h2>")
+		assert.Contains(t, mock.savedNote.GetContent(), `<en-todo checked="true"/>done`)
+	})
+
+	t.Run("markdown and append conflict", func(t *testing.T) {
+		updateMarkdown = "text"
+		updateAppend = "more"
+		defer func() {
+			updateMarkdown = ""
+			updateAppend = ""
+		}()
+
+		err := updateCmd.RunE(updateCmd, []string{"note-123"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot be used with --body, --html, --append or --prepend")
+	})
+
 	t.Run("no flags provided", func(t *testing.T) {
 		updateTitle = ""
 		updateBody = ""
@@ -143,7 +254,7 @@ func TestUpdateCommand(t *testing.T) {
 
 		err := updateCmd.RunE(updateCmd, []string{"note-123"})
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "at least one of --title, --body, --html, --append, or --tags is required")
+		assert.Contains(t, err.Error(), "at least one of --title, --body, --html, --markdown, --md-file, --append, --prepend, or --tags is required")
 	})
 
 	t.Run("body and append conflict", func(t *testing.T) {
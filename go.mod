@@ -3,17 +3,21 @@ module github.com/cloudmanic/evernote-cli
 go 1.24.3
 
 require (
+	filippo.io/age v1.2.1
+	github.com/apache/thrift v0.13.0
 	github.com/dreampuf/evernote-sdk-golang v0.0.0-20200205091351-d2ad936dfa1c
+	github.com/mrjones/oauth v0.0.0-20180629183705-f4e24b6d100c
 	github.com/spf13/cobra v1.9.1
 	github.com/stretchr/testify v1.10.0
+	golang.org/x/term v0.30.0
 )
 
 require (
-	github.com/apache/thrift v0.13.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/mrjones/oauth v0.0.0-20180629183705-f4e24b6d100c // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/spf13/pflag v1.0.6 // indirect
+	golang.org/x/crypto v0.24.0 // indirect
+	golang.org/x/sys v0.31.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )